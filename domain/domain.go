@@ -24,7 +24,9 @@ type TransactionManager interface {
 type Service interface {
 	EventService
 	GroupService
+	OAuthService
 	RoomService
+	RoomSeriesService
 	TagService
 	UserService
 }
@@ -32,7 +34,9 @@ type Service interface {
 type Repository interface {
 	EventRepository
 	GroupRepository
+	OAuthRepository
 	RoomRepository
+	RoomSeriesRepository
 	TagRepository
 	UserRepository
 }
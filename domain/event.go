@@ -31,6 +31,8 @@ type Event struct {
 	AllowTogether bool
 	Attendees     []Attendee
 	Open          bool
+	// BlackoutConflict 後から設定された使えない時間帯と重なっている
+	BlackoutConflict bool
 	Model
 }
 
@@ -61,6 +63,26 @@ func (e *Event) RoomTimeConsistency() bool {
 	return false
 }
 
+// ExpectedAttendees 欠席と答えた人以外の参加者数
+func (e *Event) ExpectedAttendees() int {
+	n := 0
+	for _, a := range e.Attendees {
+		if a.Schedule != Absent {
+			n++
+		}
+	}
+	return n
+}
+
+// ExceedsCapacity 参加者数が部屋の座席数を超えるか
+// 座席数が登録されていない場合は false
+func (e *Event) ExceedsCapacity() bool {
+	if e.Room.Attribute == nil || e.Room.Attribute.Capacity == 0 {
+		return false
+	}
+	return e.ExpectedAttendees() > e.Room.Attribute.Capacity
+}
+
 func (e *Event) AdminsValidation() bool {
 	return len(e.Admins) != 0
 }
@@ -91,6 +113,7 @@ type EventTagParams struct {
 
 // EventRepository is implemented by ...
 type EventService interface {
+	// CreateEvent knoQ のグループのイベントは organizer 以上の役割か特権が必要
 	CreateEvent(ctx context.Context, reqID uuid.UUID, eventParams WriteEventParams) (*Event, error)
 
 	UpdateEvent(ctx context.Context, reqID uuid.UUID, eventID uuid.UUID, eventParams WriteEventParams) (*Event, error)
@@ -125,10 +148,14 @@ type EventRepository interface {
 
 	DeleteEvent(ctx context.Context, eventID uuid.UUID) error
 
+	UpdateEventRoom(ctx context.Context, eventID, roomID uuid.UUID) error
+
 	DeleteEventTag(ctx context.Context, eventID uuid.UUID, tagName string, deleteLocked bool) error
 
 	UpsertEventSchedule(ctx context.Context, eventID, userID uuid.UUID, scheduleStatus ScheduleStatus) error
 
+	DeleteEventSchedule(ctx context.Context, eventID, userID uuid.UUID) error
+
 	GetEvent(ctx context.Context, eventID uuid.UUID) (*Event, error)
 
 	GetAllEvents(ctx context.Context, expr filters.Expr) ([]*Event, error)
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/gofrs/uuid"
 )
@@ -13,6 +14,14 @@ type Group struct {
 	JoinFreely  bool
 	Members     []User
 	Admins      []User
+	// Organizers グループの他の人のイベントも管理できる
+	Organizers []User
+	// Viewers ゲストユーザーでもグループの公開されていないイベントを閲覧できる
+	Viewers []User
+	// Archived アーカイブされたグループは一覧に出ず、イベントを作れない
+	Archived bool
+	// ParentID 親グループ。uuid.Nil の場合は親を持たない
+	ParentID    uuid.UUID
 	IsTraQGroup bool `cvt:"->"`
 	CreatedBy   User
 	Model
@@ -22,12 +31,118 @@ func (g *Group) AdminsValidation() bool {
 	return len(g.Admins) != 0
 }
 
+// GroupRole グループでの役割
+// 強い役割は弱い役割の権限を全て持つ
+type GroupRole string
+
+const (
+	GroupRoleAdmin     GroupRole = "admin"
+	GroupRoleOrganizer GroupRole = "organizer"
+	GroupRoleMember    GroupRole = "member"
+	GroupRoleViewer    GroupRole = "viewer"
+)
+
+// HasRole userID が role 以上の役割を持つか
+func (g *Group) HasRole(userID uuid.UUID, role GroupRole) bool {
+	contains := func(users []User) bool {
+		for _, u := range users {
+			if u.ID == userID {
+				return true
+			}
+		}
+		return false
+	}
+	switch role {
+	case GroupRoleViewer:
+		if contains(g.Viewers) {
+			return true
+		}
+		fallthrough
+	case GroupRoleMember:
+		if contains(g.Members) {
+			return true
+		}
+		fallthrough
+	case GroupRoleOrganizer:
+		if contains(g.Organizers) {
+			return true
+		}
+		fallthrough
+	case GroupRoleAdmin:
+		return contains(g.Admins)
+	}
+	return false
+}
+
+// GroupInvitation グループへの招待
+// Token を知っているユーザーは JoinFreely でないグループにも参加できる
+type GroupInvitation struct {
+	ID      uuid.UUID
+	GroupID uuid.UUID
+	Token   string
+	// ExpiresAt ゼロ値の場合は期限なし
+	ExpiresAt time.Time
+	// Invitee uuid.Nil でない場合はそのユーザーだけが使え、使うと無くなる
+	Invitee   uuid.UUID
+	CreatedBy User
+	Model
+}
+
+func (i *GroupInvitation) Expired(now time.Time) bool {
+	return !i.ExpiresAt.IsZero() && !now.Before(i.ExpiresAt)
+}
+
+// GroupJoinRequest JoinFreely でないグループへの参加申請
+// 承認または却下されると無くなる
+type GroupJoinRequest struct {
+	ID      uuid.UUID
+	GroupID uuid.UUID
+	User    User
+	Message string
+	Model
+}
+
+// GroupICalToken グループのイベントを iCal で購読するためのトークン
+// traQ のグループにも発行できる
+type GroupICalToken struct {
+	ID        uuid.UUID
+	GroupID   uuid.UUID
+	Token     string
+	CreatedBy User
+	Model
+}
+
+type GroupAuditAction string
+
+const (
+	GroupAuditAddMember    GroupAuditAction = "add_member"
+	GroupAuditDeleteMember GroupAuditAction = "delete_member"
+	GroupAuditAddAdmin     GroupAuditAction = "add_admin"
+	GroupAuditDeleteAdmin  GroupAuditAction = "delete_admin"
+
+	GroupAuditAddOrganizer    GroupAuditAction = "add_organizer"
+	GroupAuditDeleteOrganizer GroupAuditAction = "delete_organizer"
+	GroupAuditAddViewer       GroupAuditAction = "add_viewer"
+	GroupAuditDeleteViewer    GroupAuditAction = "delete_viewer"
+)
+
+// GroupAuditLog 管理者によるメンバーと管理者の変更履歴
+type GroupAuditLog struct {
+	ID        uuid.UUID
+	GroupID   uuid.UUID
+	Action    GroupAuditAction
+	Target    User
+	CreatedBy User
+	Model
+}
+
 type WriteGroupParams struct {
 	Name        string
 	Description string
 	JoinFreely  bool
 	Members     []uuid.UUID
 	Admins      []uuid.UUID
+	ParentID    uuid.UUID
 }
 
 type GroupService interface {
@@ -38,13 +153,66 @@ type GroupService interface {
 	DeleteGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) error
 	// DeleteMeGroup delete me in that group if that group is open.
 	DeleteMeGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) error
+	// ArchiveGroup 削除せずに過去のイベントから参照できるまま使えなくする
+	ArchiveGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) (*Group, error)
+	UnArchiveGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) (*Group, error)
+	// AddMemberToGroup 他の管理者の変更を上書きせずにメンバーを一人加える
+	AddMemberToGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*Group, error)
+	DeleteMemberOfGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*Group, error)
+	AddAdminToGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*Group, error)
+	// DeleteAdminOfGroup 最後の管理者は削除できない
+	DeleteAdminOfGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*Group, error)
+	// AddRoleToGroup organizer か viewer を加える。既に他の役割がある場合は置き換える
+	AddRoleToGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID, role GroupRole) (*Group, error)
+	DeleteRoleOfGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID, role GroupRole) (*Group, error)
+	// GetGroupAuditLogs グループの管理者である必要がある
+	GetGroupAuditLogs(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*GroupAuditLog, error)
 
 	GetGroup(ctx context.Context, groupID uuid.UUID) (*Group, error)
-	GetAllGroups(ctx context.Context) ([]*Group, error)
+	// GetAllGroups includeArchived が false の場合はアーカイブされたグループを除く
+	GetAllGroups(ctx context.Context, includeArchived bool) ([]*Group, error)
+	// GetUserBelongingGroupIDs 所属するグループの祖先のグループも含む
 	GetUserBelongingGroupIDs(ctx context.Context, reqID uuid.UUID, userID uuid.UUID) ([]uuid.UUID, error)
+	// GetSubgroupIDs 子孫のグループを全て取得する
+	GetSubgroupIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
 	GetUserAdminGroupIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 	IsGroupAdmins(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) bool
+	// HasGroupRole reqID が role 以上の役割を持つか
+	HasGroupRole(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, role GroupRole) bool
 	GetGradeGroupNames(ctx context.Context) ([]string, error)
+	// CreateGroupICalToken グループの管理者か特権が必要
+	CreateGroupICalToken(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) (*GroupICalToken, error)
+	GetGroupICalTokens(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*GroupICalToken, error)
+	DeleteGroupICalToken(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, tokenID uuid.UUID) error
+	// GetGroupICalEvents token がグループのものであれば、グループの公開されているイベントを返す
+	GetGroupICalEvents(ctx context.Context, groupID uuid.UUID, token string) ([]*Event, error)
+
+	// SyncTraQGroupAttendees traQ のグループのこれから始まるイベントの参加者をメンバーに合わせる
+	// knoQ のグループはメンバーが変わったときに合わせている
+	// 失敗したグループがあっても残りのグループは同期する
+	SyncTraQGroupAttendees(ctx context.Context) error
+
+	CreateGroupInvitation(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, params WriteGroupInvitationParams) (*GroupInvitation, error)
+	GetGroupInvitations(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*GroupInvitation, error)
+	DeleteGroupInvitation(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, invitationID uuid.UUID) error
+	// AcceptGroupInvitation 招待を使ってグループに参加する
+	AcceptGroupInvitation(ctx context.Context, reqID uuid.UUID, token string) (*Group, error)
+
+	CreateGroupJoinRequest(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, params WriteGroupJoinRequestParams) (*GroupJoinRequest, error)
+	// GetGroupJoinRequests グループの管理者である必要がある
+	GetGroupJoinRequests(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*GroupJoinRequest, error)
+	// ApproveGroupJoinRequest 申請したユーザーをメンバーに加える
+	ApproveGroupJoinRequest(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, requestID uuid.UUID) (*GroupJoinRequest, error)
+	RejectGroupJoinRequest(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, requestID uuid.UUID) (*GroupJoinRequest, error)
+}
+
+type WriteGroupInvitationParams struct {
+	ExpiresAt time.Time
+	Invitee   uuid.UUID
+}
+
+type WriteGroupJoinRequestParams struct {
+	Message string
 }
 
 type UpsertGroupArgs struct {
@@ -52,6 +220,32 @@ type UpsertGroupArgs struct {
 	CreatedBy uuid.UUID
 }
 
+type CreateGroupInvitationArgs struct {
+	WriteGroupInvitationParams
+	GroupID   uuid.UUID
+	Token     string
+	CreatedBy uuid.UUID
+}
+
+type CreateGroupJoinRequestArgs struct {
+	WriteGroupJoinRequestParams
+	GroupID uuid.UUID
+	UserID  uuid.UUID
+}
+
+type CreateGroupAuditLogArgs struct {
+	GroupID   uuid.UUID
+	Action    GroupAuditAction
+	TargetID  uuid.UUID
+	CreatedBy uuid.UUID
+}
+
+type CreateGroupICalTokenArgs struct {
+	GroupID   uuid.UUID
+	Token     string
+	CreatedBy uuid.UUID
+}
+
 type GroupRepository interface {
 	CreateGroup(ctx context.Context, args UpsertGroupArgs) (*Group, error)
 
@@ -61,13 +255,58 @@ type GroupRepository interface {
 
 	DeleteGroup(ctx context.Context, groupID uuid.UUID) error
 
+	UpdateGroupArchived(ctx context.Context, groupID uuid.UUID, archived bool) error
+
 	DeleteMemberOfGroup(ctx context.Context, groupID, userID uuid.UUID) error
 
+	AddAdminToGroup(ctx context.Context, groupID, userID uuid.UUID) error
+
+	// DeleteAdminOfGroup 管理者がいなくなる場合はエラーを返す
+	DeleteAdminOfGroup(ctx context.Context, groupID, userID uuid.UUID) error
+
+	// UpsertGroupRole organizer か viewer を設定する。一人につき一つの役割を持つ
+	UpsertGroupRole(ctx context.Context, groupID, userID uuid.UUID, role GroupRole) error
+
+	DeleteGroupRole(ctx context.Context, groupID, userID uuid.UUID, role GroupRole) error
+
+	CreateGroupAuditLog(ctx context.Context, args CreateGroupAuditLogArgs) error
+
+	CreateGroupICalToken(ctx context.Context, args CreateGroupICalTokenArgs) (*GroupICalToken, error)
+
+	GetGroupICalToken(ctx context.Context, tokenID uuid.UUID) (*GroupICalToken, error)
+
+	GetGroupICalTokens(ctx context.Context, groupID uuid.UUID) ([]*GroupICalToken, error)
+
+	DeleteGroupICalToken(ctx context.Context, tokenID uuid.UUID) error
+
+	GetGroupAuditLogs(ctx context.Context, groupID uuid.UUID) ([]*GroupAuditLog, error)
+
 	GetGroup(ctx context.Context, groupID uuid.UUID) (*Group, error)
 
 	GetAllGroups(ctx context.Context) ([]*Group, error)
 
+	// GetBelongGroupIDs 所属するグループの祖先のグループも含む
 	GetBelongGroupIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 
+	GetSubgroupIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+
 	GetAdminGroupIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	CreateGroupInvitation(ctx context.Context, args CreateGroupInvitationArgs) (*GroupInvitation, error)
+
+	GetGroupInvitation(ctx context.Context, invitationID uuid.UUID) (*GroupInvitation, error)
+
+	GetGroupInvitationByToken(ctx context.Context, token string) (*GroupInvitation, error)
+
+	GetGroupInvitations(ctx context.Context, groupID uuid.UUID) ([]*GroupInvitation, error)
+
+	DeleteGroupInvitation(ctx context.Context, invitationID uuid.UUID) error
+
+	CreateGroupJoinRequest(ctx context.Context, args CreateGroupJoinRequestArgs) (*GroupJoinRequest, error)
+
+	GetGroupJoinRequest(ctx context.Context, requestID uuid.UUID) (*GroupJoinRequest, error)
+
+	GetGroupJoinRequests(ctx context.Context, groupID uuid.UUID) ([]*GroupJoinRequest, error)
+
+	DeleteGroupJoinRequest(ctx context.Context, requestID uuid.UUID) error
 }
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestGroup_HasRole(t *testing.T) {
+	admin := User{ID: uuid.Must(uuid.NewV4())}
+	organizer := User{ID: uuid.Must(uuid.NewV4())}
+	member := User{ID: uuid.Must(uuid.NewV4())}
+	viewer := User{ID: uuid.Must(uuid.NewV4())}
+	other := User{ID: uuid.Must(uuid.NewV4())}
+	g := &Group{
+		Admins:     []User{admin},
+		Organizers: []User{organizer},
+		Members:    []User{member},
+		Viewers:    []User{viewer},
+	}
+
+	tests := []struct {
+		name string
+		user User
+		role GroupRole
+		want bool
+	}{
+		{name: "admin is admin", user: admin, role: GroupRoleAdmin, want: true},
+		{name: "admin is organizer", user: admin, role: GroupRoleOrganizer, want: true},
+		{name: "admin is viewer", user: admin, role: GroupRoleViewer, want: true},
+		{name: "organizer is not admin", user: organizer, role: GroupRoleAdmin, want: false},
+		{name: "organizer is organizer", user: organizer, role: GroupRoleOrganizer, want: true},
+		{name: "organizer is member", user: organizer, role: GroupRoleMember, want: true},
+		{name: "member is not organizer", user: member, role: GroupRoleOrganizer, want: false},
+		{name: "member is viewer", user: member, role: GroupRoleViewer, want: true},
+		{name: "viewer is not member", user: viewer, role: GroupRoleMember, want: false},
+		{name: "viewer is viewer", user: viewer, role: GroupRoleViewer, want: true},
+		{name: "other is not viewer", user: other, role: GroupRoleViewer, want: false},
+		{name: "unknown role", user: admin, role: GroupRole("owner"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.HasRole(tt.user.ID, tt.role); got != tt.want {
+				t.Errorf("Group.HasRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// OAuthClient ユーザーの代わりに knoQ の API を使う外部アプリ
+type OAuthClient struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	// RedirectURIs 認可後にリダイレクトできる URI。完全一致で比較する
+	// https、ループバックへの http、逆ドメイン名の private-use スキームだけを使える
+	RedirectURIs []string
+	// Scopes クライアントが要求できるスコープ
+	Scopes []AccessTokenScope
+	// Confidential client_secret で認証するクライアント
+	// false の場合は PKCE だけで認証する
+	Confidential bool
+	// Secret 作成したときだけ値を持つ
+	Secret    string
+	CreatedBy uuid.UUID
+	Model
+}
+
+type WriteOAuthClientParams struct {
+	Name         string
+	Description  string
+	RedirectURIs []string
+	Scopes       []AccessTokenScope
+	Confidential bool
+}
+
+type CreateOAuthClientArgs struct {
+	WriteOAuthClientParams
+	CreatedBy uuid.UUID
+	Secret    string
+}
+
+// OAuthAuthorizeParams 認可リクエスト
+// PKCE の code_challenge_method は S256 だけ使える
+type OAuthAuthorizeParams struct {
+	ClientID            uuid.UUID
+	RedirectURI         string
+	Scopes              []AccessTokenScope
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// OAuthConsent 同意画面に表示する内容
+type OAuthConsent struct {
+	Client      *OAuthClient
+	RedirectURI string
+	Scopes      []AccessTokenScope
+}
+
+// OAuthAuthorizationCode 認可コード。一度だけアクセストークンと交換できる
+type OAuthAuthorizationCode struct {
+	ClientID      uuid.UUID
+	UserID        uuid.UUID
+	RedirectURI   string
+	Scopes        []AccessTokenScope
+	CodeChallenge string
+	ExpiresAt     time.Time
+}
+
+type CreateOAuthAuthorizationCodeArgs struct {
+	OAuthAuthorizationCode
+	Code string
+}
+
+// OAuthToken 外部アプリに発行したトークン
+// AccessToken, RefreshToken は発行したときだけ値を持つ
+type OAuthToken struct {
+	ID           uuid.UUID
+	ClientID     uuid.UUID
+	UserID       uuid.UUID
+	Scopes       []AccessTokenScope
+	AccessToken  string
+	RefreshToken string
+	// ExpiresAt アクセストークンの期限
+	ExpiresAt time.Time
+	Model
+}
+
+func (t *OAuthToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *OAuthToken) HasScope(scope AccessTokenScope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+type CreateOAuthTokenArgs struct {
+	ClientID     uuid.UUID
+	UserID       uuid.UUID
+	Scopes       []AccessTokenScope
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// OAuthTokenRequest トークンエンドポイントへのリクエスト
+// GrantType は authorization_code か refresh_token
+type OAuthTokenRequest struct {
+	GrantType    string
+	ClientID     uuid.UUID
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+}
+
+// OAuthError RFC 6749 5.2 のエラー
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return fmt.Sprintf("oauth2: %s: %s", e.Code, e.Description)
+}
+
+type OAuthService interface {
+	// CreateOAuthClient, GetOAuthClients, DeleteOAuthClient 管理者だけが使える
+	CreateOAuthClient(ctx context.Context, reqID uuid.UUID, params WriteOAuthClientParams) (*OAuthClient, error)
+	GetOAuthClients(ctx context.Context, reqID uuid.UUID) ([]*OAuthClient, error)
+	DeleteOAuthClient(ctx context.Context, reqID uuid.UUID, clientID uuid.UUID) error
+
+	// GetOAuthConsent 認可リクエストを確認して同意画面の内容を返す
+	GetOAuthConsent(ctx context.Context, reqID uuid.UUID, params OAuthAuthorizeParams) (*OAuthConsent, error)
+	// AuthorizeOAuthClient 同意の結果を付けたリダイレクト先を返す
+	// approved の場合は認可コード、そうでない場合は access_denied を付ける
+	AuthorizeOAuthClient(ctx context.Context, reqID uuid.UUID, params OAuthAuthorizeParams, approved bool) (redirectURL string, err error)
+	// ExchangeOAuthToken 失敗した場合は *OAuthError
+	ExchangeOAuthToken(ctx context.Context, req OAuthTokenRequest) (*OAuthToken, error)
+	// IntrospectOAuthToken RFC 7662。clientID に発行した有効なトークンだけを返す
+	// 無効なトークンの場合は nil, nil。クライアントの認証に失敗した場合は *OAuthError
+	IntrospectOAuthToken(ctx context.Context, clientID uuid.UUID, clientSecret, token string) (*OAuthToken, error)
+	// AuthenticateOAuthToken 有効なアクセストークンを返す
+	// 無効なトークンの場合は ErrUnAuthorized
+	AuthenticateOAuthToken(ctx context.Context, token string) (*OAuthToken, error)
+}
+
+type OAuthRepository interface {
+	CreateOAuthClient(ctx context.Context, args CreateOAuthClientArgs) (*OAuthClient, error)
+	GetOAuthClient(ctx context.Context, clientID uuid.UUID) (*OAuthClient, error)
+	GetAllOAuthClients(ctx context.Context) ([]*OAuthClient, error)
+	// VerifyOAuthClientSecret secret が clientID のものか
+	VerifyOAuthClientSecret(ctx context.Context, clientID uuid.UUID, secret string) (bool, error)
+	DeleteOAuthClient(ctx context.Context, clientID uuid.UUID) error
+
+	CreateOAuthAuthorizationCode(ctx context.Context, args CreateOAuthAuthorizationCodeArgs) error
+	// TakeOAuthAuthorizationCode 認可コードを取り出して削除する
+	TakeOAuthAuthorizationCode(ctx context.Context, code string) (*OAuthAuthorizationCode, error)
+
+	CreateOAuthToken(ctx context.Context, args CreateOAuthTokenArgs) (*OAuthToken, error)
+	GetOAuthTokenByAccessToken(ctx context.Context, accessToken string) (*OAuthToken, error)
+	GetOAuthTokenByRefreshToken(ctx context.Context, refreshToken string) (*OAuthToken, error)
+	DeleteOAuthToken(ctx context.Context, tokenID uuid.UUID) error
+}
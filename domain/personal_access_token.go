@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// AccessTokenScope 個人用アクセストークンや OAuth のアクセストークンで使える操作
+type AccessTokenScope string
+
+const (
+	AccessTokenScopeReadEvents  AccessTokenScope = "read:events"
+	AccessTokenScopeWriteEvents AccessTokenScope = "write:events"
+	AccessTokenScopeRSVP        AccessTokenScope = "rsvp"
+)
+
+func (s AccessTokenScope) Valid() bool {
+	switch s {
+	case AccessTokenScopeReadEvents, AccessTokenScopeWriteEvents, AccessTokenScopeRSVP:
+		return true
+	}
+	return false
+}
+
+// PersonalAccessToken スクリプトや bot から API を使うためのトークン
+// Token は作成したときだけ値を持つ
+type PersonalAccessToken struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Name   string
+	Token  string
+	Scopes []AccessTokenScope
+	// ExpiresAt ゼロ値の場合は期限なし
+	ExpiresAt time.Time
+	// LastUsedAt ゼロ値の場合はまだ使われていない
+	LastUsedAt time.Time
+	Model
+}
+
+func (t *PersonalAccessToken) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+func (t *PersonalAccessToken) HasScope(scope AccessTokenScope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+type WritePersonalAccessTokenParams struct {
+	Name      string
+	Scopes    []AccessTokenScope
+	ExpiresAt time.Time
+}
+
+type CreatePersonalAccessTokenArgs struct {
+	WritePersonalAccessTokenParams
+	UserID uuid.UUID
+	Token  string
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -15,20 +16,82 @@ type Room struct {
 	TimeStart time.Time
 	TimeEnd   time.Time
 	Events    []Event
+	// Blackouts 部屋の確保時間のうち使えなくなった時間帯
+	Blackouts []RoomBlackout
+	// SeriesID 繰り返し確保した部屋の場合、RoomSeries の ID
+	SeriesID uuid.UUID
+	// SharedCapacity 部屋ごとの同時に入れる共用イベントの数。0 は場所の設定に従う
+	SharedCapacity int
+	// Attribute 場所の設備。登録されていない場合は nil
+	Attribute *PlaceAttribute
 	Admins    []User
 	CreatedBy User
 	Model
 }
 
+// RoomBlackout 避難訓練や試験などで部屋が使えなくなる時間帯
+// RoomID が uuid.Nil の場合、Place の全ての部屋に適用される
+type RoomBlackout struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	Place     string
+	TimeStart time.Time
+	TimeEnd   time.Time
+	Reason    string
+	CreatedBy User
+	Model
+}
+
+// PlaceAttribute 場所ごとの収容人数と設備
+// 同じ場所の部屋は全て同じ設備を持つ
+type PlaceAttribute struct {
+	Place string
+	// Capacity 座席数。0 は不明
+	Capacity     int
+	Projector    bool
+	Whiteboard   bool
+	PowerOutlets bool
+	Accessible   bool
+	// SharedCapacity 同時に入れる共用イベントの数。0 は制限なし
+	SharedCapacity int
+}
+
+// RoomAttributeFilter 部屋を設備で絞り込む
+// 設備の項目は true のとき、その設備がある部屋のみにする
+type RoomAttributeFilter struct {
+	MinCapacity  int
+	Projector    bool
+	Whiteboard   bool
+	PowerOutlets bool
+	Accessible   bool
+}
+
+func (f *RoomAttributeFilter) IsZero() bool {
+	return *f == RoomAttributeFilter{}
+}
+
 // StartEndTime has start and end time
 type StartEndTime struct {
 	TimeStart time.Time
 	TimeEnd   time.Time
 }
 
+// EffectiveSharedCapacity 同時に入れる共用イベントの数。0 は制限なし
+// 部屋の設定があれば場所の設定より優先する
+func (r *Room) EffectiveSharedCapacity() int {
+	if r.SharedCapacity > 0 {
+		return r.SharedCapacity
+	}
+	if r.Attribute == nil {
+		return 0
+	}
+	return r.Attribute.SharedCapacity
+}
+
 // CalcAvailableTime calclate available time
-// allowTogether = true 併用化の時間帯
+// allowTogether = true 併用化の時間帯。共用イベントが SharedCapacity だけ入っている時間帯は除く
 // allowTogether = false 誰も取っていない時間帯
+// Blackouts の時間帯はどちらの場合も使えない
 func (r *Room) CalcAvailableTime(allowTogether bool) []StartEndTime {
 	availabletime := []StartEndTime{
 		{
@@ -36,15 +99,58 @@ func (r *Room) CalcAvailableTime(allowTogether bool) []StartEndTime {
 			TimeEnd:   r.TimeEnd,
 		},
 	}
+	for _, b := range r.Blackouts {
+		availabletime = timeRangesSub(availabletime, StartEndTime{b.TimeStart, b.TimeEnd})
+	}
+	shared := make([]StartEndTime, 0)
 	for _, e := range r.Events {
 		if allowTogether && e.AllowTogether {
+			shared = append(shared, StartEndTime{e.TimeStart, e.TimeEnd})
 			continue
 		}
 		availabletime = timeRangesSub(availabletime, StartEndTime{e.TimeStart, e.TimeEnd})
 	}
+	if capacity := r.EffectiveSharedCapacity(); allowTogether && capacity > 0 {
+		for _, t := range overlappedTimes(shared, capacity) {
+			availabletime = timeRangesSub(availabletime, t)
+		}
+	}
 	return availabletime
 }
 
+// overlappedTimes times のうち n 個以上が重なっている時間帯
+func overlappedTimes(times []StartEndTime, n int) []StartEndTime {
+	type point struct {
+		t     time.Time
+		delta int
+	}
+	points := make([]point, 0, 2*len(times))
+	for _, t := range times {
+		points = append(points, point{t.TimeStart, 1}, point{t.TimeEnd, -1})
+	}
+	// 同時刻では終わりを先に数え、接しているだけの時間帯は重ならないとする
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].t.Equal(points[j].t) {
+			return points[i].delta < points[j].delta
+		}
+		return points[i].t.Before(points[j].t)
+	})
+
+	overlapped := make([]StartEndTime, 0)
+	count := 0
+	var start time.Time
+	for _, p := range points {
+		count += p.delta
+		if p.delta > 0 && count == n {
+			start = p.t
+		}
+		if p.delta < 0 && count == n-1 && start.Before(p.t) {
+			overlapped = append(overlapped, StartEndTime{start, p.t})
+		}
+	}
+	return overlapped
+}
+
 // as: 利用可能な時間帯のリスト
 // b: イベントの時間
 func timeRangesSub(as []StartEndTime, b StartEndTime) (cs []StartEndTime) {
@@ -138,6 +244,23 @@ func (r *Room) TimeConsistency() bool {
 	return r.TimeStart.Before((r.TimeEnd))
 }
 
+// EventsTimeConsistency 予約されているイベントが全て部屋の時間に収まるか
+// 使えなくなった時間帯との重なりは Event.BlackoutConflict で扱うので見ない
+func (r *Room) EventsTimeConsistency() bool {
+	for i, e := range r.Events {
+		room := *r
+		room.Blackouts = nil
+		room.Events = make([]Event, 0, len(r.Events)-1)
+		room.Events = append(room.Events, r.Events[:i]...)
+		room.Events = append(room.Events, r.Events[i+1:]...)
+		e.Room = room
+		if !e.RoomTimeConsistency() {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *Room) AdminsValidation() bool {
 	return len(r.Admins) != 0
 }
@@ -150,30 +273,122 @@ type WriteRoomParams struct {
 	TimeEnd   time.Time
 
 	Admins []uuid.UUID
+	// SharedCapacity 同時に入れる共用イベントの数。0 は場所の設定に従う
+	SharedCapacity int
 }
 
 func (r *WriteRoomParams) TimeConsistency() bool {
 	return r.TimeStart.Before((r.TimeEnd))
 }
 
+// WriteRoomAdminsParams 複数の部屋の管理者をまとめて変更する
+type WriteRoomAdminsParams struct {
+	RoomIDs []uuid.UUID
+	Admins  []uuid.UUID
+	// Replace true のとき既存の管理者を Admins で置き換える
+	// false のとき Admins を追加する
+	Replace bool
+}
+
+type MoveRoomEventsParams struct {
+	ToRoomID uuid.UUID
+	// EventIDs 空の場合は部屋の全てのイベントを移す
+	EventIDs []uuid.UUID
+}
+
+type WriteRoomBlackoutParams struct {
+	RoomID    uuid.UUID
+	Place     string
+	TimeStart time.Time
+	TimeEnd   time.Time
+	Reason    string
+}
+
+func (b *WriteRoomBlackoutParams) TimeConsistency() bool {
+	return b.TimeStart.Before(b.TimeEnd)
+}
+
+// RoomStatsGroupBy は部屋の利用統計の集計単位
+type RoomStatsGroupBy int
+
+const (
+	RoomStatsGroupByPlace RoomStatsGroupBy = iota
+	// RoomStatsGroupByWeek 部屋の開始日が属する週(月曜始まり)ごと
+	RoomStatsGroupByWeek
+	// RoomStatsGroupByGroup イベントを開催したグループごと
+	// 部屋の確保時間はグループに紐づかないため ReservedTime は常に0
+	RoomStatsGroupByGroup
+)
+
+// RoomStats は部屋の利用統計
+type RoomStats struct {
+	// Key は集計単位の値 (場所名, 週の初日 "2006-01-02", グループID)
+	Key       string
+	RoomCount int
+	// ReservedTime 部屋を確保していた時間
+	ReservedTime time.Duration
+	// UsedTime 確保時間のうちイベントが入っていた時間
+	UsedTime   time.Duration
+	EventCount int
+	// SharedTime 確保時間のうち部屋の併用を許可したイベントが入っていた時間
+	SharedTime       time.Duration
+	SharedEventCount int
+}
+
+// IdleTime 確保したがイベントが入っていない時間
+func (s *RoomStats) IdleTime() time.Duration {
+	if s.ReservedTime < s.UsedTime {
+		return 0
+	}
+	return s.ReservedTime - s.UsedTime
+}
+
 type RoomService interface {
 	CreateUnVerifiedRoom(ctx context.Context, reqID uuid.UUID, params WriteRoomParams) (*Room, error)
 	CreateVerifiedRoom(ctx context.Context, reqID uuid.UUID, params WriteRoomParams) (*Room, error)
 
 	UpdateRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID, params WriteRoomParams) (*Room, error)
 	VerifyRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) error
-	UnVerifyRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) error
+	// UnVerifyRoom 確認を取り消し、使えることが保証されなくなったイベントを返す
+	UnVerifyRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) ([]*Event, error)
+	// UpdateRoomsAdmins 全ての部屋の管理者であるか特権が必要
+	UpdateRoomsAdmins(ctx context.Context, reqID uuid.UUID, params WriteRoomAdminsParams) ([]*Room, error)
 
-	DeleteRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) error
+	// DeleteRoom 部屋を削除し、部屋がなくなったイベントを返す
+	DeleteRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) ([]*Event, error)
+	// MoveRoomEvents 部屋のイベントを別の部屋に移す。移した先で時間が収まらないイベントがあれば何も移さない
+	// 特権か、両方の部屋の管理者である必要がある。イベントの管理者である必要はない
+	MoveRoomEvents(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID, params MoveRoomEventsParams) ([]*Event, error)
 
 	GetRoom(ctx context.Context, roomID uuid.UUID, excludeEventID uuid.UUID) (*Room, error)
-	GetAllRooms(ctx context.Context, start time.Time, end time.Time, excludeEventID uuid.UUID, onlyVerified bool) ([]*Room, error)
+	GetAllRooms(ctx context.Context, start time.Time, end time.Time, excludeEventID uuid.UUID, onlyVerified bool, attr RoomAttributeFilter) ([]*Room, error)
+	// GetRoomsByPlace 場所の確認済みの部屋を取得する
+	GetRoomsByPlace(ctx context.Context, place string, start time.Time, end time.Time) ([]*Room, error)
+	// GetOverlappingRooms 期間と重なる確認済みの部屋を取得する。期間をまたぐ部屋も含む
+	GetOverlappingRooms(ctx context.Context, start time.Time, end time.Time) ([]*Room, error)
 	IsRoomAdmins(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) bool
+	GetRoomStats(ctx context.Context, reqID uuid.UUID, since, until time.Time, groupBy RoomStatsGroupBy, onlyVerified bool) ([]*RoomStats, error)
+
+	// CreateRoomBlackout 使えなくなる時間帯を追加し、その時間帯に重なるイベントを返す
+	CreateRoomBlackout(ctx context.Context, reqID uuid.UUID, params WriteRoomBlackoutParams) (*RoomBlackout, []*Event, error)
+	DeleteRoomBlackout(ctx context.Context, reqID uuid.UUID, blackoutID uuid.UUID) error
+	GetRoomBlackouts(ctx context.Context, start time.Time, end time.Time) ([]*RoomBlackout, error)
+
+	// UpsertPlaceAttribute 場所の設備を登録する。既にある場合は上書きする
+	UpsertPlaceAttribute(ctx context.Context, reqID uuid.UUID, params PlaceAttribute) (*PlaceAttribute, error)
+	DeletePlaceAttribute(ctx context.Context, reqID uuid.UUID, place string) error
+	GetPlaceAttributes(ctx context.Context) ([]*PlaceAttribute, error)
 }
 
 type CreateRoomArgs struct {
 	WriteRoomParams
 	Verified  bool
+	SeriesID  uuid.UUID
+	CreatedBy uuid.UUID
+}
+
+type CreateRoomBlackoutArgs struct {
+	WriteRoomBlackoutParams
 	CreatedBy uuid.UUID
 }
 
@@ -190,9 +405,32 @@ type RoomRepository interface {
 
 	UpdateRoomVerified(ctx context.Context, roomID uuid.UUID, verified bool) error
 
+	UpdateRoomAdmins(ctx context.Context, roomID uuid.UUID, admins []uuid.UUID, replace bool) error
+
 	DeleteRoom(ctx context.Context, roomID uuid.UUID) error
 
 	GetRoom(ctx context.Context, roomID uuid.UUID, excludeEventID uuid.UUID) (*Room, error)
 
-	GetAllRooms(ctx context.Context, start, end time.Time, excludeEventID uuid.UUID, onlyVerified bool) ([]*Room, error)
+	GetAllRooms(ctx context.Context, start, end time.Time, excludeEventID uuid.UUID, onlyVerified bool, attr RoomAttributeFilter) ([]*Room, error)
+
+	GetRoomsByPlace(ctx context.Context, place string, start, end time.Time) ([]*Room, error)
+
+	GetOverlappingRooms(ctx context.Context, start, end time.Time) ([]*Room, error)
+
+	GetRoomStats(ctx context.Context, since, until time.Time, groupBy RoomStatsGroupBy, onlyVerified bool) ([]*RoomStats, error)
+
+	CreateRoomBlackout(ctx context.Context, args CreateRoomBlackoutArgs) (*RoomBlackout, error)
+
+	DeleteRoomBlackout(ctx context.Context, blackoutID uuid.UUID) error
+
+	GetRoomBlackouts(ctx context.Context, start, end time.Time) ([]*RoomBlackout, error)
+
+	// GetEventsInRoomBlackout 使えなくなった時間帯に重なるイベントを取得する
+	GetEventsInRoomBlackout(ctx context.Context, blackoutID uuid.UUID) ([]*Event, error)
+
+	UpsertPlaceAttribute(ctx context.Context, attr PlaceAttribute) (*PlaceAttribute, error)
+
+	DeletePlaceAttribute(ctx context.Context, place string) error
+
+	GetPlaceAttributes(ctx context.Context) ([]*PlaceAttribute, error)
 }
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/utils/tz"
+)
+
+// MaxRoomSeriesOccurrences 一つの繰り返しで確保できる部屋の数の上限
+const MaxRoomSeriesOccurrences = 366
+
+// RoomSeries 毎週決まった曜日に確保する部屋
+// 確保した部屋は Room として展開され、Room.SeriesID で紐づく
+type RoomSeries struct {
+	ID    uuid.UUID
+	Place string
+	// Weekdays 繰り返す曜日
+	Weekdays []time.Weekday
+	// TimeStart, TimeEnd 初回の確保時間。2回目以降も同じ時刻で確保する
+	TimeStart time.Time
+	TimeEnd   time.Time
+	// Until この日まで繰り返す (この日を含む)
+	Until time.Time
+	// Exceptions 確保しない日
+	Exceptions []time.Time
+	Rooms      []Room
+	CreatedBy  User
+	Model
+}
+
+func (s *RoomSeries) Occurrences() []StartEndTime {
+	return calcOccurrences(s.TimeStart, s.TimeEnd, s.Until, s.Weekdays, s.Exceptions)
+}
+
+type WriteRoomSeriesParams struct {
+	Place      string
+	Weekdays   []time.Weekday
+	TimeStart  time.Time
+	TimeEnd    time.Time
+	Until      time.Time
+	Exceptions []time.Time
+
+	Admins []uuid.UUID
+}
+
+// TimeConsistency 一回の確保は日をまたいでもよいが、24時間以内とする
+func (p *WriteRoomSeriesParams) TimeConsistency() bool {
+	return p.TimeStart.Before(p.TimeEnd) &&
+		p.TimeEnd.Sub(p.TimeStart) <= 24*time.Hour &&
+		!p.Until.Before(truncateDate(p.TimeStart))
+}
+
+func (p *WriteRoomSeriesParams) Occurrences() []StartEndTime {
+	return calcOccurrences(p.TimeStart, p.TimeEnd, p.Until, p.Weekdays, p.Exceptions)
+}
+
+// calcOccurrences 曜日と除外日は JST で判定する
+func calcOccurrences(start, end, until time.Time, weekdays []time.Weekday, exceptions []time.Time) []StartEndTime {
+	duration := end.Sub(start)
+	first := start.In(tz.JST)
+	last := truncateDate(until)
+
+	weekdaySet := make(map[time.Weekday]bool, len(weekdays))
+	for _, w := range weekdays {
+		weekdaySet[w] = true
+	}
+	exceptionSet := make(map[time.Time]bool, len(exceptions))
+	for _, e := range exceptions {
+		exceptionSet[truncateDate(e)] = true
+	}
+
+	occurrences := make([]StartEndTime, 0)
+	for day := truncateDate(first); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !weekdaySet[day.Weekday()] || exceptionSet[day] {
+			continue
+		}
+		s := time.Date(day.Year(), day.Month(), day.Day(), first.Hour(), first.Minute(), first.Second(), 0, tz.JST)
+		occurrences = append(occurrences, StartEndTime{
+			TimeStart: s,
+			TimeEnd:   s.Add(duration),
+		})
+	}
+	return occurrences
+}
+
+// truncateDate JST でその日の 0 時にする
+func truncateDate(t time.Time) time.Time {
+	t = t.In(tz.JST)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tz.JST)
+}
+
+type UpsertRoomSeriesArgs struct {
+	WriteRoomSeriesParams
+	CreatedBy uuid.UUID
+}
+
+type RoomSeriesService interface {
+	// CreateRoomSeries 繰り返しを作成し、確認済みの部屋を確保する
+	CreateRoomSeries(ctx context.Context, reqID uuid.UUID, params WriteRoomSeriesParams) (*RoomSeries, error)
+	// UpdateRoomSeries 繰り返しを変更し、まだ終わっていない部屋を作り直す
+	// 予約されたイベントが収まらなくなる場合は ErrTimeConsistency
+	UpdateRoomSeries(ctx context.Context, reqID uuid.UUID, seriesID uuid.UUID, params WriteRoomSeriesParams) (*RoomSeries, error)
+	// DeleteRoomSeries 繰り返しを取り消し、まだ終わっていない部屋を削除する
+	// まだ終わっていない部屋にイベントが予約されている場合は ErrTimeConsistency
+	DeleteRoomSeries(ctx context.Context, reqID uuid.UUID, seriesID uuid.UUID) error
+
+	GetRoomSeries(ctx context.Context, seriesID uuid.UUID) (*RoomSeries, error)
+	GetAllRoomSeries(ctx context.Context) ([]*RoomSeries, error)
+}
+
+type RoomSeriesRepository interface {
+	CreateRoomSeries(ctx context.Context, args UpsertRoomSeriesArgs) (*RoomSeries, error)
+
+	UpdateRoomSeries(ctx context.Context, seriesID uuid.UUID, args UpsertRoomSeriesArgs) (*RoomSeries, error)
+
+	DeleteRoomSeries(ctx context.Context, seriesID uuid.UUID) error
+
+	GetRoomSeries(ctx context.Context, seriesID uuid.UUID) (*RoomSeries, error)
+
+	GetAllRoomSeries(ctx context.Context) ([]*RoomSeries, error)
+}
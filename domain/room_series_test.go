@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/traPtitech/knoQ/utils/tz"
+)
+
+func TestWriteRoomSeriesParams_Occurrences(t *testing.T) {
+	// 2024/04/02 は火曜日
+	start := time.Date(2024, 4, 2, 18, 0, 0, 0, tz.JST)
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 0, 0, 0, 0, tz.JST)
+	}
+	occurrence := func(month time.Month, day, hour, hours int) StartEndTime {
+		s := time.Date(2024, month, day, hour, 0, 0, 0, tz.JST)
+		return StartEndTime{TimeStart: s, TimeEnd: s.Add(time.Duration(hours) * time.Hour)}
+	}
+	tests := []struct {
+		name   string
+		params WriteRoomSeriesParams
+		want   []StartEndTime
+	}{
+		{
+			name: "tuesday and thursday",
+			params: WriteRoomSeriesParams{
+				TimeStart: start,
+				TimeEnd:   start.Add(2 * time.Hour),
+				Weekdays:  []time.Weekday{time.Tuesday, time.Thursday},
+				Until:     date(time.April, 11),
+			},
+			want: []StartEndTime{
+				occurrence(time.April, 2, 18, 2),
+				occurrence(time.April, 4, 18, 2),
+				occurrence(time.April, 9, 18, 2),
+				occurrence(time.April, 11, 18, 2),
+			},
+		},
+		{
+			name: "exceptions",
+			params: WriteRoomSeriesParams{
+				TimeStart:  start,
+				TimeEnd:    start.Add(2 * time.Hour),
+				Weekdays:   []time.Weekday{time.Tuesday},
+				Until:      date(time.April, 16),
+				Exceptions: []time.Time{date(time.April, 9)},
+			},
+			want: []StartEndTime{
+				occurrence(time.April, 2, 18, 2),
+				occurrence(time.April, 16, 18, 2),
+			},
+		},
+		{
+			name: "utc start",
+			params: WriteRoomSeriesParams{
+				// JST では 2024/04/03 (水) 01:00
+				TimeStart: time.Date(2024, 4, 2, 16, 0, 0, 0, time.UTC),
+				TimeEnd:   time.Date(2024, 4, 2, 17, 0, 0, 0, time.UTC),
+				Weekdays:  []time.Weekday{time.Wednesday},
+				Until:     date(time.April, 10),
+			},
+			want: []StartEndTime{
+				occurrence(time.April, 3, 1, 1),
+				occurrence(time.April, 10, 1, 1),
+			},
+		},
+		{
+			name: "no weekdays",
+			params: WriteRoomSeriesParams{
+				TimeStart: start,
+				TimeEnd:   start.Add(2 * time.Hour),
+				Until:     date(time.April, 30),
+			},
+			want: []StartEndTime{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.params.Occurrences(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WriteRoomSeriesParams.Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRoomSeriesParams_TimeConsistency(t *testing.T) {
+	start := time.Date(2024, 4, 2, 18, 0, 0, 0, tz.JST)
+	tests := []struct {
+		name   string
+		params WriteRoomSeriesParams
+		want   bool
+	}{
+		{
+			name: "ok",
+			params: WriteRoomSeriesParams{
+				TimeStart: start,
+				TimeEnd:   start.Add(2 * time.Hour),
+				Until:     time.Date(2024, 4, 2, 0, 0, 0, 0, tz.JST),
+			},
+			want: true,
+		},
+		{
+			name: "reversed",
+			params: WriteRoomSeriesParams{
+				TimeStart: start,
+				TimeEnd:   start.Add(-2 * time.Hour),
+				Until:     time.Date(2024, 4, 30, 0, 0, 0, 0, tz.JST),
+			},
+			want: false,
+		},
+		{
+			name: "too long",
+			params: WriteRoomSeriesParams{
+				TimeStart: start,
+				TimeEnd:   start.Add(25 * time.Hour),
+				Until:     time.Date(2024, 4, 30, 0, 0, 0, 0, tz.JST),
+			},
+			want: false,
+		},
+		{
+			name: "until before start",
+			params: WriteRoomSeriesParams{
+				TimeStart: start,
+				TimeEnd:   start.Add(2 * time.Hour),
+				Until:     time.Date(2024, 4, 1, 0, 0, 0, 0, tz.JST),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.params.TimeConsistency(); got != tt.want {
+				t.Errorf("WriteRoomSeriesParams.TimeConsistency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,9 +9,12 @@ import (
 func TestRoom_CalcAvailableTime(t *testing.T) {
 	now := time.Now()
 	type fields struct {
-		TimeStart time.Time
-		TimeEnd   time.Time
-		Events    []Event
+		TimeStart      time.Time
+		TimeEnd        time.Time
+		Events         []Event
+		Blackouts      []RoomBlackout
+		SharedCapacity int
+		Attribute      *PlaceAttribute
 	}
 	tests := []struct {
 		name          string
@@ -105,13 +108,115 @@ func TestRoom_CalcAvailableTime(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "blackout",
+			fields: fields{
+				TimeStart: now,
+				TimeEnd:   now.Add(10 * time.Hour),
+				Events: []Event{
+					{
+						TimeStart:     now.Add(1 * time.Hour),
+						TimeEnd:       now.Add(2 * time.Hour),
+						AllowTogether: true,
+					},
+				},
+				Blackouts: []RoomBlackout{
+					{
+						TimeStart: now.Add(5 * time.Hour),
+						TimeEnd:   now.Add(6 * time.Hour),
+					},
+				},
+			},
+			want: []StartEndTime{
+				{
+					TimeStart: now,
+					TimeEnd:   now.Add(5 * time.Hour),
+				},
+				{
+					TimeStart: now.Add(6 * time.Hour),
+					TimeEnd:   now.Add(10 * time.Hour),
+				},
+			},
+			allowTogether: true,
+		},
+		{
+			name: "shared capacity",
+			fields: fields{
+				TimeStart: now,
+				TimeEnd:   now.Add(10 * time.Hour),
+				Events: []Event{
+					{
+						TimeStart:     now.Add(1 * time.Hour),
+						TimeEnd:       now.Add(4 * time.Hour),
+						AllowTogether: true,
+					},
+					{
+						TimeStart:     now.Add(2 * time.Hour),
+						TimeEnd:       now.Add(3 * time.Hour),
+						AllowTogether: true,
+					},
+					{
+						TimeStart:     now.Add(3 * time.Hour),
+						TimeEnd:       now.Add(5 * time.Hour),
+						AllowTogether: true,
+					},
+				},
+				Attribute: &PlaceAttribute{SharedCapacity: 2},
+			},
+			want: []StartEndTime{
+				{
+					TimeStart: now,
+					TimeEnd:   now.Add(2 * time.Hour),
+				},
+				{
+					TimeStart: now.Add(4 * time.Hour),
+					TimeEnd:   now.Add(10 * time.Hour),
+				},
+			},
+			allowTogether: true,
+		},
+		{
+			name: "room shared capacity overrides place",
+			fields: fields{
+				TimeStart: now,
+				TimeEnd:   now.Add(10 * time.Hour),
+				Events: []Event{
+					{
+						TimeStart:     now.Add(1 * time.Hour),
+						TimeEnd:       now.Add(4 * time.Hour),
+						AllowTogether: true,
+					},
+					{
+						TimeStart:     now.Add(2 * time.Hour),
+						TimeEnd:       now.Add(3 * time.Hour),
+						AllowTogether: true,
+					},
+				},
+				SharedCapacity: 2,
+				Attribute:      &PlaceAttribute{SharedCapacity: 3},
+			},
+			want: []StartEndTime{
+				{
+					TimeStart: now,
+					TimeEnd:   now.Add(2 * time.Hour),
+				},
+				{
+					TimeStart: now.Add(3 * time.Hour),
+					TimeEnd:   now.Add(10 * time.Hour),
+				},
+			},
+			allowTogether: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Room{
-				TimeStart: tt.fields.TimeStart,
-				TimeEnd:   tt.fields.TimeEnd,
-				Events:    tt.fields.Events,
+				TimeStart:      tt.fields.TimeStart,
+				TimeEnd:        tt.fields.TimeEnd,
+				Events:         tt.fields.Events,
+				Blackouts:      tt.fields.Blackouts,
+				SharedCapacity: tt.fields.SharedCapacity,
+				Attribute:      tt.fields.Attribute,
 			}
 			got := r.CalcAvailableTime(tt.allowTogether)
 			if !reflect.DeepEqual(got, tt.want) {
@@ -122,3 +227,81 @@ func TestRoom_CalcAvailableTime(t *testing.T) {
 		})
 	}
 }
+
+func TestRoom_EventsTimeConsistency(t *testing.T) {
+	now := time.Now()
+	booked := Event{
+		TimeStart: now.Add(1 * time.Hour),
+		TimeEnd:   now.Add(3 * time.Hour),
+	}
+	tests := []struct {
+		name      string
+		timeStart time.Time
+		timeEnd   time.Time
+		events    []Event
+		blackouts []RoomBlackout
+		want      bool
+	}{
+		{
+			name:      "no events",
+			timeStart: now.Add(4 * time.Hour),
+			timeEnd:   now.Add(5 * time.Hour),
+			want:      true,
+		},
+		{
+			name:      "event fits",
+			timeStart: now,
+			timeEnd:   now.Add(3 * time.Hour),
+			events:    []Event{booked},
+			want:      true,
+		},
+		{
+			name:      "room shrunk",
+			timeStart: now.Add(2 * time.Hour),
+			timeEnd:   now.Add(5 * time.Hour),
+			events:    []Event{booked},
+			want:      false,
+		},
+		{
+			name:      "events overlap",
+			timeStart: now,
+			timeEnd:   now.Add(5 * time.Hour),
+			events: []Event{booked, {
+				TimeStart: now.Add(2 * time.Hour),
+				TimeEnd:   now.Add(4 * time.Hour),
+			}},
+			want: false,
+		},
+		{
+			name:      "shared events",
+			timeStart: now,
+			timeEnd:   now.Add(5 * time.Hour),
+			events: []Event{
+				{TimeStart: now, TimeEnd: now.Add(2 * time.Hour), AllowTogether: true},
+				{TimeStart: now.Add(1 * time.Hour), TimeEnd: now.Add(3 * time.Hour), AllowTogether: true},
+			},
+			want: true,
+		},
+		{
+			name:      "blackouts are ignored",
+			timeStart: now,
+			timeEnd:   now.Add(5 * time.Hour),
+			events:    []Event{booked},
+			blackouts: []RoomBlackout{{TimeStart: now, TimeEnd: now.Add(2 * time.Hour)}},
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Room{
+				TimeStart: tt.timeStart,
+				TimeEnd:   tt.timeEnd,
+				Events:    tt.events,
+				Blackouts: tt.blackouts,
+			}
+			if got := r.EventsTimeConsistency(); got != tt.want {
+				t.Errorf("r.EventsTimeConsistency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@ type User struct {
 	Icon        string
 	Privileged  bool
 	State       int
+	// Guest traQ 以外のプロバイダ (Google, OIDC) で認証したユーザー
+	Guest bool
 
 	Provider *Provider
 }
@@ -28,6 +30,12 @@ type UserService interface {
 	GetOAuthURL(ctx context.Context) (url, state, codeVerifier string)
 	// LoginUser OAuthによってユーザーを得る
 	LoginUser(ctx context.Context, query, state, codeVerifier string) (*User, error)
+	// GetLoginProviders traQ 以外でログインに使えるプロバイダの名前
+	GetLoginProviders(ctx context.Context) []string
+	// GetProviderOAuthURL provider が設定されていない場合は ErrNotFound
+	GetProviderOAuthURL(ctx context.Context, provider string) (url, state string, err error)
+	// LoginProviderUser provider で認証したゲストユーザーを得る。初回はユーザーを作る
+	LoginProviderUser(ctx context.Context, provider, query, state string) (*User, error)
 
 	GetUser(ctx context.Context, userID uuid.UUID) (*User, error)
 	GetUserMe(ctx context.Context, reqID uuid.UUID) (*User, error)
@@ -40,6 +48,23 @@ type UserService interface {
 	IsPrivilege(ctx context.Context, reqID uuid.UUID) bool
 	GrantPrivilege(ctx context.Context, userID uuid.UUID) error
 	SyncUsers(ctx context.Context, reqID uuid.UUID) error
+	// HandOverAdmins 停止したユーザーが管理する部屋とイベントを後任に引き継ぐ
+	HandOverAdmins(ctx context.Context, reqID uuid.UUID, userID uuid.UUID, successorID uuid.UUID) (*HandOverResult, error)
+	// RefreshExpiringTokens within 以内に期限が切れる traQ のトークンを更新する
+	RefreshExpiringTokens(ctx context.Context, within time.Duration) error
+
+	CreatePersonalAccessToken(ctx context.Context, reqID uuid.UUID, params WritePersonalAccessTokenParams) (*PersonalAccessToken, error)
+	GetMyPersonalAccessTokens(ctx context.Context, reqID uuid.UUID) ([]*PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, reqID uuid.UUID, tokenID uuid.UUID) error
+	// AuthenticatePersonalAccessToken 有効なトークンを返し、使った時刻を記録する
+	// 無効なトークンの場合は ErrUnAuthorized
+	AuthenticatePersonalAccessToken(ctx context.Context, token string) (*PersonalAccessToken, error)
+}
+
+// HandOverResult 引き継いだ部屋とイベントの数
+type HandOverResult struct {
+	RoomCount  int
+	EventCount int
 }
 
 type TokenArgs struct {
@@ -54,11 +79,20 @@ type ProviderArgs struct {
 	Subject string
 }
 
+// ProfileArgs traQ 以外のプロバイダのユーザーの名前とアイコン
+type ProfileArgs struct {
+	Name        string
+	DisplayName string
+	Icon        string
+}
+
 type SaveUserArgs struct {
 	UserID uuid.UUID
 	State  int
 	TokenArgs
 	ProviderArgs
+	// traQ のユーザーの場合は nil
+	Profile *ProfileArgs
 }
 
 type SyncUserArgs struct {
@@ -71,9 +105,25 @@ type UserRepository interface {
 	SaveUser(ctx context.Context, args SaveUserArgs) (*User, error)
 	UpdateiCalSecret(ctx context.Context, userID uuid.UUID, secret string) error
 	GetUser(ctx context.Context, userID uuid.UUID) (*User, error)
+	// GetUserByProvider issuer と subject が一致するユーザー
+	GetUserByProvider(ctx context.Context, issuer, subject string) (*User, error)
 	GetAllUsers(ctx context.Context, onlyActive bool) ([]*User, error)
 	SyncUsers(ctx context.Context, args []SyncUserArgs) error
 	GrantPrivilege(ctx context.Context, userID uuid.UUID) error
 	GetICalSecret(ctx context.Context, userID uuid.UUID) (string, error)
 	GetToken(ctx context.Context, userID uuid.UUID) (*oauth2.Token, error)
+	// UpdateToken 更新された traQ のトークンを保存する
+	UpdateToken(ctx context.Context, userID uuid.UUID, token *oauth2.Token) error
+	// GetExpiringTokenUserIDs from から to の間にトークンの期限が切れるユーザー
+	GetExpiringTokenUserIDs(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+	// TransferAdmins fromID が管理する部屋とイベントの管理者を toID に付け替える
+	TransferAdmins(ctx context.Context, fromID, toID uuid.UUID) (*HandOverResult, error)
+
+	// CreatePersonalAccessToken トークンはハッシュ化して保存する
+	CreatePersonalAccessToken(ctx context.Context, args CreatePersonalAccessTokenArgs) (*PersonalAccessToken, error)
+	GetPersonalAccessToken(ctx context.Context, tokenID uuid.UUID) (*PersonalAccessToken, error)
+	GetPersonalAccessTokenByToken(ctx context.Context, token string) (*PersonalAccessToken, error)
+	GetPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]*PersonalAccessToken, error)
+	UpdatePersonalAccessTokenLastUsedAt(ctx context.Context, tokenID uuid.UUID, lastUsedAt time.Time) error
+	DeletePersonalAccessToken(ctx context.Context, tokenID uuid.UUID) error
 }
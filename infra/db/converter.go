@@ -10,6 +10,7 @@ import (
 
 func ConvCreateRoomParamsToRoom(src domain.CreateRoomArgs) (dst Room) {
 	dst.Verified = src.Verified
+	dst.SeriesID = src.SeriesID
 	dst.CreatedByRefer = src.CreatedBy
 	dst.Place = src.Place
 	dst.TimeStart = src.TimeStart
@@ -18,6 +19,7 @@ func ConvCreateRoomParamsToRoom(src domain.CreateRoomArgs) (dst Room) {
 	for i := range src.Admins {
 		dst.Admins[i] = convuuidUUIDToRoomAdmin(src.Admins[i])
 	}
+	dst.SharedCapacity = src.SharedCapacity
 	return
 }
 func ConvEventAdminToRoomAdmin(src EventAdmin) (dst RoomAdmin) {
@@ -64,6 +66,7 @@ func ConvEventTodomainEvent(src Event) (dst domain.Event) {
 		dst.Attendees[i] = convEventAttendeeTodomainAttendee(src.Attendees[i])
 	}
 	dst.Open = src.Open
+	dst.BlackoutConflict = src.BlackoutConflict
 	dst.CreatedAt = src.CreatedAt
 	dst.UpdatedAt = src.UpdatedAt
 	dst.DeletedAt = new(time.Time)
@@ -84,6 +87,8 @@ func ConvGroupTodomainGroup(src Group) (dst domain.Group) {
 	dst.Name = src.Name
 	dst.Description = src.Description
 	dst.JoinFreely = src.JoinFreely
+	dst.Archived = src.Archived
+	dst.ParentID = src.ParentID
 	dst.Members = make([]domain.User, len(src.Members))
 	for i := range src.Members {
 		dst.Members[i] = convGroupMemberTodomainUser(src.Members[i])
@@ -99,11 +104,88 @@ func ConvGroupTodomainGroup(src Group) (dst domain.Group) {
 	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
 	return
 }
+
+func ConvGroupInvitationTodomainGroupInvitation(src GroupInvitation) (dst domain.GroupInvitation) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.Token = src.Token
+	dst.ExpiresAt = src.ExpiresAt
+	dst.Invitee = src.Invitee
+	dst.CreatedBy = convUserTodomainUser(src.CreatedBy)
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+
+func ConvGroupJoinRequestTodomainGroupJoinRequest(src GroupJoinRequest) (dst domain.GroupJoinRequest) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.User = convUserTodomainUser(src.User)
+	dst.Message = src.Message
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+
+func ConvGroupAuditLogTodomainGroupAuditLog(src GroupAuditLog) (dst domain.GroupAuditLog) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.Action = domain.GroupAuditAction(src.Action)
+	dst.Target = convUserTodomainUser(src.Target)
+	dst.CreatedBy = convUserTodomainUser(src.CreatedBy)
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+
+func ConvGroupICalTokenTodomainGroupICalToken(src GroupICalToken) (dst domain.GroupICalToken) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.Token = src.Token
+	dst.CreatedBy = convUserTodomainUser(src.CreatedBy)
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
 func ConvRoomAdminTodomainUser(src RoomAdmin) (dst domain.User) {
 	dst.ID = src.UserID
 	return
 }
 
+func ConvPlaceAttributeTodomainPlaceAttribute(src PlaceAttribute) (dst domain.PlaceAttribute) {
+	dst.Place = src.Place
+	dst.Capacity = src.Capacity
+	dst.Projector = src.Projector
+	dst.Whiteboard = src.Whiteboard
+	dst.PowerOutlets = src.PowerOutlets
+	dst.Accessible = src.Accessible
+	dst.SharedCapacity = src.SharedCapacity
+	return
+}
+
+func ConvRoomBlackoutTodomainRoomBlackout(src RoomBlackout) (dst domain.RoomBlackout) {
+	dst.ID = src.ID
+	dst.RoomID = src.RoomID
+	dst.Place = src.Place
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Reason = src.Reason
+	dst.CreatedBy = convUserTodomainUser(src.CreatedBy)
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+
 func ConvRoomTodomainRoom(src Room) (dst domain.Room) {
 	dst.ID = src.ID
 	dst.Place = src.Place
@@ -114,6 +196,16 @@ func ConvRoomTodomainRoom(src Room) (dst domain.Room) {
 	for i := range src.Events {
 		dst.Events[i] = convEventTodomainEvent(src.Events[i])
 	}
+	dst.Blackouts = make([]domain.RoomBlackout, len(src.Blackouts))
+	for i := range src.Blackouts {
+		dst.Blackouts[i] = convRoomBlackoutTodomainRoomBlackout(src.Blackouts[i])
+	}
+	dst.SeriesID = src.SeriesID
+	dst.SharedCapacity = src.SharedCapacity
+	if src.Attribute != nil {
+		dst.Attribute = new(domain.PlaceAttribute)
+		(*dst.Attribute) = convPlaceAttributeTodomainPlaceAttribute((*src.Attribute))
+	}
 	dst.Admins = make([]domain.User, len(src.Admins))
 	for i := range src.Admins {
 		dst.Admins[i] = convRoomAdminTodomainUser(src.Admins[i])
@@ -164,6 +256,71 @@ func ConvSPGroupToSPdomainGroup(src []*Group) (dst []*domain.Group) {
 	}
 	return
 }
+
+func ConvSPGroupInvitationToSPdomainGroupInvitation(src []*GroupInvitation) (dst []*domain.GroupInvitation) {
+	dst = make([]*domain.GroupInvitation, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.GroupInvitation)
+			(*dst[i]) = ConvGroupInvitationTodomainGroupInvitation((*src[i]))
+		}
+	}
+	return
+}
+
+func ConvSPGroupJoinRequestToSPdomainGroupJoinRequest(src []*GroupJoinRequest) (dst []*domain.GroupJoinRequest) {
+	dst = make([]*domain.GroupJoinRequest, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.GroupJoinRequest)
+			(*dst[i]) = ConvGroupJoinRequestTodomainGroupJoinRequest((*src[i]))
+		}
+	}
+	return
+}
+
+func ConvSPGroupAuditLogToSPdomainGroupAuditLog(src []*GroupAuditLog) (dst []*domain.GroupAuditLog) {
+	dst = make([]*domain.GroupAuditLog, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.GroupAuditLog)
+			(*dst[i]) = ConvGroupAuditLogTodomainGroupAuditLog((*src[i]))
+		}
+	}
+	return
+}
+
+func ConvSPGroupICalTokenToSPdomainGroupICalToken(src []*GroupICalToken) (dst []*domain.GroupICalToken) {
+	dst = make([]*domain.GroupICalToken, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.GroupICalToken)
+			(*dst[i]) = ConvGroupICalTokenTodomainGroupICalToken((*src[i]))
+		}
+	}
+	return
+}
+func ConvSPPlaceAttributeToSPdomainPlaceAttribute(src []*PlaceAttribute) (dst []*domain.PlaceAttribute) {
+	dst = make([]*domain.PlaceAttribute, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.PlaceAttribute)
+			(*dst[i]) = convPlaceAttributeTodomainPlaceAttribute((*src[i]))
+		}
+	}
+	return
+}
+
+func ConvSPRoomBlackoutToSPdomainRoomBlackout(src []*RoomBlackout) (dst []*domain.RoomBlackout) {
+	dst = make([]*domain.RoomBlackout, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.RoomBlackout)
+			(*dst[i]) = convRoomBlackoutTodomainRoomBlackout((*src[i]))
+		}
+	}
+	return
+}
 func ConvSPRoomToSPdomainRoom(src []*Room) (dst []*domain.Room) {
 	dst = make([]*domain.Room, len(src))
 	for i := range src {
@@ -210,6 +367,7 @@ func ConvUpdateRoomParamsToRoom(src domain.UpdateRoomArgs) (dst Room) {
 	for i := range src.Admins {
 		dst.Admins[i] = convuuidUUIDToRoomAdmin(src.Admins[i])
 	}
+	dst.SharedCapacity = src.SharedCapacity
 	return
 }
 func ConvUserMetaTodomainUser(src User) (dst domain.User) {
@@ -250,6 +408,7 @@ func ConvWriteGroupParamsToGroup(src domain.UpsertGroupArgs) (dst Group) {
 	dst.Name = src.Name
 	dst.Description = src.Description
 	dst.JoinFreely = src.JoinFreely
+	dst.ParentID = src.ParentID
 	dst.Members = make([]GroupMember, len(src.Members))
 	for i := range src.Members {
 		dst.Members[i] = convuuidUUIDToGroupMember(src.Members[i])
@@ -340,6 +499,7 @@ func convEventTodomainEvent(src Event) (dst domain.Event) {
 		dst.Attendees[i] = convEventAttendeeTodomainAttendee(src.Attendees[i])
 	}
 	dst.Open = src.Open
+	dst.BlackoutConflict = src.BlackoutConflict
 	dst.CreatedAt = src.CreatedAt
 	dst.UpdatedAt = src.UpdatedAt
 	dst.DeletedAt = new(time.Time)
@@ -360,6 +520,8 @@ func convGroupTodomainGroup(src Group) (dst domain.Group) {
 	dst.Name = src.Name
 	dst.Description = src.Description
 	dst.JoinFreely = src.JoinFreely
+	dst.Archived = src.Archived
+	dst.ParentID = src.ParentID
 	dst.Members = make([]domain.User, len(src.Members))
 	for i := range src.Members {
 		dst.Members[i] = convGroupMemberTodomainUser(src.Members[i])
@@ -379,6 +541,31 @@ func convRoomAdminTodomainUser(src RoomAdmin) (dst domain.User) {
 	dst.ID = src.UserID
 	return
 }
+func convRoomBlackoutTodomainRoomBlackout(src RoomBlackout) (dst domain.RoomBlackout) {
+	dst.ID = src.ID
+	dst.RoomID = src.RoomID
+	dst.Place = src.Place
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Reason = src.Reason
+	dst.CreatedBy = convUserTodomainUser(src.CreatedBy)
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+func convPlaceAttributeTodomainPlaceAttribute(src PlaceAttribute) (dst domain.PlaceAttribute) {
+	dst.Place = src.Place
+	dst.Capacity = src.Capacity
+	dst.Projector = src.Projector
+	dst.Whiteboard = src.Whiteboard
+	dst.PowerOutlets = src.PowerOutlets
+	dst.Accessible = src.Accessible
+	dst.SharedCapacity = src.SharedCapacity
+	return
+}
+
 func convRoomTodomainRoom(src Room) (dst domain.Room) {
 	dst.ID = src.ID
 	dst.Place = src.Place
@@ -389,6 +576,16 @@ func convRoomTodomainRoom(src Room) (dst domain.Room) {
 	for i := range src.Events {
 		dst.Events[i] = convEventTodomainEvent(src.Events[i])
 	}
+	dst.Blackouts = make([]domain.RoomBlackout, len(src.Blackouts))
+	for i := range src.Blackouts {
+		dst.Blackouts[i] = convRoomBlackoutTodomainRoomBlackout(src.Blackouts[i])
+	}
+	dst.SeriesID = src.SeriesID
+	dst.SharedCapacity = src.SharedCapacity
+	if src.Attribute != nil {
+		dst.Attribute = new(domain.PlaceAttribute)
+		(*dst.Attribute) = convPlaceAttributeTodomainPlaceAttribute((*src.Attribute))
+	}
 	dst.Admins = make([]domain.User, len(src.Admins))
 	for i := range src.Admins {
 		dst.Admins[i] = convRoomAdminTodomainUser(src.Admins[i])
@@ -418,6 +615,11 @@ func convUserTodomainUser(src User) (dst domain.User) {
 		Issuer:  src.Provider.Issuer,
 		Subject: src.Provider.Subject,
 	}
+	if src.Profile != nil {
+		dst.Name = src.Profile.Name
+		dst.DisplayName = src.Profile.DisplayName
+		dst.Icon = src.Profile.Icon
+	}
 	return
 }
 func convdomainEventTagParamsToEventTag(src domain.EventTagParams) (dst EventTag) {
@@ -33,6 +33,7 @@ var (
 	ErrRoomUndefined   = errors.New("invalid room or args")
 	ErrNoAdmins        = errors.New("no admins")
 	ErrDuplicateEntry  = errors.New("duplicate entry")
+	ErrGroupCycle      = errors.New("group cycle")
 
 	ErrRecordNotFound = gorm.ErrRecordNotFound
 )
@@ -15,7 +15,7 @@ import (
 
 func eventFullPreload(tx *gorm.DB) *gorm.DB {
 	return tx.Preload("Group").Preload("Group.Members").Preload("Group.Admins").Preload("Group.CreatedBy").
-		Preload("Room").Preload("Room.Events").Preload("Room.Admins").Preload("Room.CreatedBy").
+		Preload("Room").Preload("Room.Events").Preload("Room.Attribute").Preload("Room.Admins").Preload("Room.CreatedBy").
 		Preload("Admins").Preload("Admins.User").
 		Preload("Tags").Preload("Tags.Tag").
 		Preload("Attendees").Preload("Attendees.User").
@@ -50,6 +50,11 @@ func (repo *gormRepository) DeleteEvent(ctx context.Context, eventID uuid.UUID)
 	return defaultErrorHandling(err)
 }
 
+func (repo *gormRepository) UpdateEventRoom(ctx context.Context, eventID, roomID uuid.UUID) error {
+	err := updateEventRoom(getTx(ctx, repo.db.WithContext(ctx)), eventID, roomID)
+	return defaultErrorHandling(err)
+}
+
 func (repo *gormRepository) DeleteEventTag(ctx context.Context, eventID uuid.UUID, tagName string, deleteLocked bool) error {
 	err := deleteEventTag(getTx(ctx, repo.db.WithContext(ctx)), eventID, tagName, deleteLocked)
 	return defaultErrorHandling(err)
@@ -60,6 +65,11 @@ func (repo *gormRepository) UpsertEventSchedule(ctx context.Context, eventID, us
 	return defaultErrorHandling(err)
 }
 
+func (repo *gormRepository) DeleteEventSchedule(ctx context.Context, eventID, userID uuid.UUID) error {
+	err := deleteEventSchedule(getTx(ctx, repo.db.WithContext(ctx)), eventID, userID)
+	return defaultErrorHandling(err)
+}
+
 func (repo *gormRepository) GetEvent(ctx context.Context, eventID uuid.UUID) (*domain.Event, error) {
 	e, err := getEvent(eventFullPreload(getTx(ctx, repo.db.WithContext(ctx))), eventID)
 	if err != nil {
@@ -190,6 +200,14 @@ func updateEvent(db *gorm.DB, eventID uuid.UUID, args domain.UpsertEventArgs) (*
 	}
 
 	err = validateEvent(db, &event)
+	if err != nil {
+		return nil, err
+	}
+	err = refreshEventBlackoutConflicts(db, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Model(&Event{}).Where("id = ?", event.ID).Select("blackout_conflict").Scan(&event.BlackoutConflict).Error
 	return &event, err
 }
 
@@ -222,6 +240,15 @@ func deleteEvent(db *gorm.DB, eventID uuid.UUID) error {
 	return db.Delete(&Event{ID: eventID}).Error
 }
 
+func updateEventRoom(db *gorm.DB, eventID, roomID uuid.UUID) error {
+	// hooksは発火しない
+	err := db.Model(&Event{}).Where("id = ?", eventID).UpdateColumn("room_id", roomID).Error
+	if err != nil {
+		return err
+	}
+	return refreshEventBlackoutConflicts(db, eventID)
+}
+
 func deleteEventTag(db *gorm.DB, eventID uuid.UUID, tagName string, deleteLocked bool) error {
 	if eventID == uuid.Nil {
 		return NewValueError(gorm.ErrRecordNotFound, "eventID")
@@ -255,6 +282,14 @@ func upsertEventSchedule(tx *gorm.DB, eventID, userID uuid.UUID, schedule domain
 	}).Create(&eventAttendee).Error
 }
 
+func deleteEventSchedule(tx *gorm.DB, eventID, userID uuid.UUID) error {
+	eventAttendee := EventAttendee{
+		UserID:  userID,
+		EventID: eventID,
+	}
+	return tx.Delete(&eventAttendee).Error
+}
+
 func getEvent(db *gorm.DB, eventID uuid.UUID) (*Event, error) {
 	event := Event{}
 	err := db.Take(&event, eventID).Error
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gofrs/uuid"
@@ -12,7 +13,7 @@ import (
 )
 
 func groupFullPreload(tx *gorm.DB) *gorm.DB {
-	return tx.Preload("Members").Preload("Admins").Preload("CreatedBy")
+	return tx.Preload("Members").Preload("Admins").Preload("Roles").Preload("CreatedBy")
 }
 
 func (repo *gormRepository) CreateGroup(ctx context.Context, args domain.UpsertGroupArgs) (*domain.Group, error) {
@@ -20,7 +21,7 @@ func (repo *gormRepository) CreateGroup(ctx context.Context, args domain.UpsertG
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
-	domainGroup := convGroupTodomainGroup(*g)
+	domainGroup := convGroupWithRolesTodomainGroup(*g)
 	return &domainGroup, defaultErrorHandling(err)
 }
 
@@ -29,7 +30,7 @@ func (repo *gormRepository) UpdateGroup(ctx context.Context, groupID uuid.UUID,
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
-	domainGroup := convGroupTodomainGroup(*g)
+	domainGroup := convGroupWithRolesTodomainGroup(*g)
 	return &domainGroup, defaultErrorHandling(err)
 }
 
@@ -43,14 +44,52 @@ func (repo *gormRepository) DeleteGroup(ctx context.Context, groupID uuid.UUID)
 	return defaultErrorHandling(err)
 }
 
+func (repo *gormRepository) UpdateGroupArchived(ctx context.Context, groupID uuid.UUID, archived bool) error {
+	err := updateGroupArchived(getTx(ctx, repo.db.WithContext(ctx)), groupID, archived)
+	return defaultErrorHandling(err)
+}
+
 func (repo *gormRepository) DeleteMemberOfGroup(ctx context.Context, groupID, userID uuid.UUID) error {
 	err := deleteMemberOfGroup(getTx(ctx, repo.db.WithContext(ctx)), groupID, userID)
 	return defaultErrorHandling(err)
 }
 
+func (repo *gormRepository) AddAdminToGroup(ctx context.Context, groupID, userID uuid.UUID) error {
+	err := addAdminToGroup(getTx(ctx, repo.db.WithContext(ctx)), groupID, userID)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) DeleteAdminOfGroup(ctx context.Context, groupID, userID uuid.UUID) error {
+	err := deleteAdminOfGroup(getTx(ctx, repo.db.WithContext(ctx)), groupID, userID)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) UpsertGroupRole(ctx context.Context, groupID, userID uuid.UUID, role domain.GroupRole) error {
+	err := upsertGroupRole(getTx(ctx, repo.db.WithContext(ctx)), groupID, userID, role)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) DeleteGroupRole(ctx context.Context, groupID, userID uuid.UUID, role domain.GroupRole) error {
+	err := deleteGroupRole(getTx(ctx, repo.db.WithContext(ctx)), groupID, userID, role)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) CreateGroupAuditLog(ctx context.Context, args domain.CreateGroupAuditLogArgs) error {
+	err := createGroupAuditLog(getTx(ctx, repo.db.WithContext(ctx)), args)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) GetGroupAuditLogs(ctx context.Context, groupID uuid.UUID) ([]*domain.GroupAuditLog, error) {
+	logs, err := getGroupAuditLogs(getTx(ctx, repo.db.WithContext(ctx)).Preload("Target").Preload("CreatedBy"), groupID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPGroupAuditLogToSPdomainGroupAuditLog(logs), nil
+}
+
 func (repo *gormRepository) GetGroup(ctx context.Context, groupID uuid.UUID) (*domain.Group, error) {
 	g, err := getGroup(groupFullPreload(getTx(ctx, repo.db.WithContext(ctx))), groupID)
-	domainGroup := convGroupTodomainGroup(*g)
+	domainGroup := convGroupWithRolesTodomainGroup(*g)
 	return &domainGroup, defaultErrorHandling(err)
 }
 
@@ -61,11 +100,12 @@ func (repo *gormRepository) GetAllGroups(ctx context.Context) ([]*domain.Group,
 			"LEFT JOIN group_members ON groups.id = group_members.group_id "+
 			"LEFT JOIN group_admins ON groups.id = group_admins.group_id "), "", nil)
 
-	return ConvSPGroupToSPdomainGroup(gs), defaultErrorHandling(err)
+	return convSPGroupWithRolesToSPdomainGroup(gs), defaultErrorHandling(err)
 }
 
 func (repo *gormRepository) GetBelongGroupIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
-	cmd := groupFullPreload(getTx(ctx, repo.db.WithContext(ctx)))
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	cmd := groupFullPreload(tx)
 	filterFormat, filterArgs, err := createGroupFilter(filters.FilterBelongs(userID))
 	if err != nil {
 		return nil, err
@@ -74,8 +114,19 @@ func (repo *gormRepository) GetBelongGroupIDs(ctx context.Context, userID uuid.U
 		"LEFT JOIN events ON groups.id = events.group_id "+
 			"LEFT JOIN group_members ON groups.id = group_members.group_id "+
 			"LEFT JOIN group_admins ON groups.id = group_admins.group_id "), filterFormat, filterArgs)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	groupIDs := convSPGroupToSuuidUUID(gs)
 
-	return convSPGroupToSuuidUUID(gs), defaultErrorHandling(err)
+	// 子グループのメンバーは親グループにも所属する
+	ancestorIDs, err := getAncestorGroupIDs(tx, groupIDs)
+	return append(groupIDs, ancestorIDs...), defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) GetSubgroupIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	groupIDs, err := getSubgroupIDs(getTx(ctx, repo.db.WithContext(ctx)), groupID)
+	return groupIDs, defaultErrorHandling(err)
 }
 
 func (repo *gormRepository) GetAdminGroupIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
@@ -101,6 +152,10 @@ func validateGroup(db *gorm.DB, g *Group) (err error) {
 	if !Dgroup.AdminsValidation() {
 		return NewValueError(ErrNoAdmins, "admins")
 	}
+	err = validateGroupParent(db, group.ID, group.ParentID)
+	if err != nil {
+		return err
+	}
 	group, err = getGroup(groupFullPreload(db), g.ID)
 	if err != nil {
 		return err
@@ -109,6 +164,113 @@ func validateGroup(db *gorm.DB, g *Group) (err error) {
 	return nil
 }
 
+// validateGroupParent 親を辿って存在しないグループや循環がないか確認する
+func validateGroupParent(db *gorm.DB, groupID, parentID uuid.UUID) error {
+	visited := map[uuid.UUID]bool{groupID: true}
+	for parentID != uuid.Nil {
+		if visited[parentID] {
+			return NewValueError(ErrGroupCycle, "parentId")
+		}
+		visited[parentID] = true
+
+		parent := Group{}
+		err := db.Select("id", "parent_id").Take(&parent, parentID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewValueError(ErrInvalidArgs, "parentId")
+		}
+		if err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// getAncestorGroupIDs groupIDs の祖先のグループを取得する。groupIDs 自身は含まない
+func getAncestorGroupIDs(db *gorm.DB, groupIDs []uuid.UUID) ([]uuid.UUID, error) {
+	visited := make(map[uuid.UUID]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		visited[id] = true
+	}
+	ancestorIDs := make([]uuid.UUID, 0)
+	current := groupIDs
+	for len(current) > 0 {
+		parentIDs := make([]uuid.UUID, 0)
+		err := db.Model(&Group{}).Where("id IN ? AND parent_id != ?", current, uuid.Nil).
+			Distinct().Pluck("parent_id", &parentIDs).Error
+		if err != nil {
+			return nil, err
+		}
+		current = current[:0:0]
+		for _, id := range parentIDs {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			ancestorIDs = append(ancestorIDs, id)
+			current = append(current, id)
+		}
+	}
+	return ancestorIDs, nil
+}
+
+// getSubgroupIDs groupID の子孫のグループを取得する。groupID 自身は含まない
+func getSubgroupIDs(db *gorm.DB, groupID uuid.UUID) ([]uuid.UUID, error) {
+	visited := map[uuid.UUID]bool{groupID: true}
+	subgroupIDs := make([]uuid.UUID, 0)
+	current := []uuid.UUID{groupID}
+	for len(current) > 0 {
+		childIDs := make([]uuid.UUID, 0)
+		err := db.Model(&Group{}).Where("parent_id IN ?", current).Pluck("id", &childIDs).Error
+		if err != nil {
+			return nil, err
+		}
+		current = current[:0:0]
+		for _, id := range childIDs {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			subgroupIDs = append(subgroupIDs, id)
+			current = append(current, id)
+		}
+	}
+	return subgroupIDs, nil
+}
+
+// convGroupWithRolesTodomainGroup 生成した変換は Roles を扱わないので、役割ごとに分けて加える
+func convGroupWithRolesTodomainGroup(src Group) (dst domain.Group) {
+	dst = convGroupTodomainGroup(src)
+	dst.Organizers, dst.Viewers = convSGroupUserRoleTodomainUsers(src.Roles)
+	return
+}
+
+func convSPGroupWithRolesToSPdomainGroup(src []*Group) (dst []*domain.Group) {
+	dst = make([]*domain.Group, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.Group)
+			(*dst[i]) = convGroupWithRolesTodomainGroup((*src[i]))
+		}
+	}
+	return
+}
+
+func convSGroupUserRoleTodomainUsers(src []GroupUserRole) (organizers []domain.User, viewers []domain.User) {
+	organizers = make([]domain.User, 0)
+	viewers = make([]domain.User, 0)
+	for _, r := range src {
+		user := domain.User{ID: r.UserID}
+		switch domain.GroupRole(r.Role) {
+		case domain.GroupRoleOrganizer:
+			organizers = append(organizers, user)
+		case domain.GroupRoleViewer:
+			viewers = append(viewers, user)
+		}
+	}
+	return
+}
+
 func convSPGroupToSuuidUUID(src []*Group) (dst []uuid.UUID) {
 	dst = make([]uuid.UUID, len(src))
 	for i := range src {
@@ -160,7 +322,8 @@ func updateGroup(db *gorm.DB, groupID uuid.UUID, args domain.UpsertGroupArgs) (*
 		return nil, err
 	}
 
-	err = db.Omit("CreatedAt").Save(&group).Error
+	// Archived は UpdateGroupArchived でのみ変更する
+	err = db.Omit("CreatedAt", "Archived").Save(&group).Error
 	if err != nil {
 		return nil, err
 	}
@@ -215,17 +378,131 @@ func deleteGroup(db *gorm.DB, groupID uuid.UUID) error {
 	if err != nil {
 		return err
 	}
+	// GroupUserRole を削除
+	err = db.Where("group_id = ?", group.ID).Delete(&GroupUserRole{}).Error
+	if err != nil {
+		return err
+	}
+	// 子グループは親を持たないグループにする
+	err = db.Model(&Group{}).Where("parent_id = ?", group.ID).UpdateColumn("parent_id", uuid.Nil).Error
+	if err != nil {
+		return err
+	}
 	// Group を削除
 	return db.Delete(&group).Error
 }
 
+func upsertGroupRole(db *gorm.DB, groupID, userID uuid.UUID, role domain.GroupRole) error {
+	if role != domain.GroupRoleOrganizer && role != domain.GroupRoleViewer {
+		return NewValueError(ErrInvalidArgs, "role")
+	}
+	groupRole := GroupUserRole{
+		GroupID: groupID,
+		UserID:  userID,
+		Role:    string(role),
+	}
+
+	onConflictClause := clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at", "deleted_at"}),
+	}
+	return db.Clauses(onConflictClause).Create(&groupRole).Error
+}
+
+// deleteGroupRole その役割を持っていない場合は gorm.ErrRecordNotFound
+func deleteGroupRole(db *gorm.DB, groupID, userID uuid.UUID, role domain.GroupRole) error {
+	result := db.Where("group_id = ? AND user_id = ? AND role = ?", groupID, userID, string(role)).
+		Delete(&GroupUserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewValueError(gorm.ErrRecordNotFound, "userID")
+	}
+	return nil
+}
+
+func updateGroupArchived(db *gorm.DB, groupID uuid.UUID, archived bool) error {
+	result := db.Model(&Group{}).Where("id = ?", groupID).UpdateColumn("archived", archived)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// 既に同じ状態の場合も 0 になるので存在を確認する
+		_, err := getGroup(db, groupID)
+		return err
+	}
+	return nil
+}
+
+// deleteMemberOfGroup メンバーでない場合は gorm.ErrRecordNotFound
 func deleteMemberOfGroup(db *gorm.DB, groupID, userID uuid.UUID) error {
 	groupMember := GroupMember{
 		GroupID: groupID,
 		UserID:  userID,
 	}
 	// 1メンバーの削除 hooksは登録なし
-	return db.Delete(&groupMember).Error
+	result := db.Delete(&groupMember)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewValueError(gorm.ErrRecordNotFound, "userID")
+	}
+	return nil
+}
+
+func addAdminToGroup(db *gorm.DB, groupID, userID uuid.UUID) error {
+	groupAdmin := GroupAdmin{
+		GroupID: groupID,
+		UserID:  userID,
+	}
+
+	onConflictClause := clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "deleted_at"}),
+	}
+	return db.Clauses(onConflictClause).Create(&groupAdmin).Error
+}
+
+// deleteAdminOfGroup 管理者でない場合は gorm.ErrRecordNotFound
+func deleteAdminOfGroup(db *gorm.DB, groupID, userID uuid.UUID) error {
+	groupAdmin := GroupAdmin{
+		GroupID: groupID,
+		UserID:  userID,
+	}
+	result := db.Delete(&groupAdmin)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewValueError(gorm.ErrRecordNotFound, "userID")
+	}
+	// 最後の管理者は削除できない
+	group := Group{ID: groupID}
+	return validateGroup(db, &group)
+}
+
+func convCreateGroupAuditLogArgsToGroupAuditLog(src domain.CreateGroupAuditLogArgs) (dst GroupAuditLog) {
+	dst.GroupID = src.GroupID
+	dst.Action = string(src.Action)
+	dst.TargetID = src.TargetID
+	dst.CreatedByRefer = src.CreatedBy
+	return
+}
+
+func createGroupAuditLog(db *gorm.DB, args domain.CreateGroupAuditLogArgs) error {
+	log := convCreateGroupAuditLogArgsToGroupAuditLog(args)
+	var err error
+	log.ID, err = uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	return db.Create(&log).Error
+}
+
+func getGroupAuditLogs(db *gorm.DB, groupID uuid.UUID) ([]*GroupAuditLog, error) {
+	logs := make([]*GroupAuditLog, 0)
+	err := db.Where("group_id = ?", groupID).Order("created_at DESC").Find(&logs).Error
+	return logs, err
 }
 
 func getGroup(db *gorm.DB, groupID uuid.UUID) (*Group, error) {
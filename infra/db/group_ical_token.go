@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func (repo *gormRepository) CreateGroupICalToken(ctx context.Context, args domain.CreateGroupICalTokenArgs) (*domain.GroupICalToken, error) {
+	token, err := createGroupICalToken(getTx(ctx, repo.db.WithContext(ctx)), args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := ConvGroupICalTokenTodomainGroupICalToken(*token)
+	return &t, nil
+}
+
+func (repo *gormRepository) GetGroupICalToken(ctx context.Context, tokenID uuid.UUID) (*domain.GroupICalToken, error) {
+	token, err := getGroupICalToken(getTx(ctx, repo.db.WithContext(ctx)).Preload("CreatedBy"), tokenID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := ConvGroupICalTokenTodomainGroupICalToken(*token)
+	return &t, nil
+}
+
+func (repo *gormRepository) GetGroupICalTokens(ctx context.Context, groupID uuid.UUID) ([]*domain.GroupICalToken, error) {
+	tokens, err := getGroupICalTokens(getTx(ctx, repo.db.WithContext(ctx)).Preload("CreatedBy"), groupID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPGroupICalTokenToSPdomainGroupICalToken(tokens), nil
+}
+
+func (repo *gormRepository) DeleteGroupICalToken(ctx context.Context, tokenID uuid.UUID) error {
+	err := deleteGroupICalToken(getTx(ctx, repo.db.WithContext(ctx)), tokenID)
+	return defaultErrorHandling(err)
+}
+
+func convCreateGroupICalTokenArgsToGroupICalToken(src domain.CreateGroupICalTokenArgs) (dst GroupICalToken) {
+	dst.GroupID = src.GroupID
+	dst.Token = src.Token
+	dst.CreatedByRefer = src.CreatedBy
+	return
+}
+
+func createGroupICalToken(db *gorm.DB, args domain.CreateGroupICalTokenArgs) (*GroupICalToken, error) {
+	token := convCreateGroupICalTokenArgsToGroupICalToken(args)
+	var err error
+	token.ID, err = uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	err = db.Create(&token).Error
+	return &token, err
+}
+
+func getGroupICalToken(db *gorm.DB, tokenID uuid.UUID) (*GroupICalToken, error) {
+	token := GroupICalToken{}
+	err := db.Take(&token, tokenID).Error
+	return &token, err
+}
+
+func getGroupICalTokens(db *gorm.DB, groupID uuid.UUID) ([]*GroupICalToken, error) {
+	tokens := make([]*GroupICalToken, 0)
+	err := db.Where("group_id = ?", groupID).Order("created_at").Find(&tokens).Error
+	return tokens, err
+}
+
+func deleteGroupICalToken(db *gorm.DB, tokenID uuid.UUID) error {
+	token := GroupICalToken{
+		ID: tokenID,
+	}
+	return db.Delete(&token).Error
+}
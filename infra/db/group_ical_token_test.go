@@ -0,0 +1,34 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func Test_groupICalToken(t *testing.T) {
+	r, assert, require, user := setupRepoWithUser(t, common)
+	// traQ のグループのように groups にないグループにも発行できる
+	groupID := mustNewUUIDV4(t)
+
+	token, err := createGroupICalToken(r.db, domain.CreateGroupICalTokenArgs{
+		GroupID:   groupID,
+		Token:     "token",
+		CreatedBy: user.ID,
+	})
+	require.NoError(err)
+
+	t.Run("get tokens", func(_ *testing.T) {
+		tokens, err := getGroupICalTokens(r.db, groupID)
+		require.NoError(err)
+		require.Len(tokens, 1)
+		assert.Equal("token", tokens[0].Token)
+	})
+
+	t.Run("delete token", func(_ *testing.T) {
+		require.NoError(deleteGroupICalToken(r.db, token.ID))
+		_, err := getGroupICalToken(r.db, token.ID)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func (repo *gormRepository) CreateGroupInvitation(ctx context.Context, args domain.CreateGroupInvitationArgs) (*domain.GroupInvitation, error) {
+	invitation, err := createGroupInvitation(getTx(ctx, repo.db.WithContext(ctx)), args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	i := ConvGroupInvitationTodomainGroupInvitation(*invitation)
+	return &i, nil
+}
+
+func (repo *gormRepository) GetGroupInvitation(ctx context.Context, invitationID uuid.UUID) (*domain.GroupInvitation, error) {
+	invitation, err := getGroupInvitation(getTx(ctx, repo.db.WithContext(ctx)).Preload("CreatedBy"), "id = ?", invitationID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	i := ConvGroupInvitationTodomainGroupInvitation(*invitation)
+	return &i, nil
+}
+
+func (repo *gormRepository) GetGroupInvitationByToken(ctx context.Context, token string) (*domain.GroupInvitation, error) {
+	invitation, err := getGroupInvitation(getTx(ctx, repo.db.WithContext(ctx)).Preload("CreatedBy"), "token = ?", token)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	i := ConvGroupInvitationTodomainGroupInvitation(*invitation)
+	return &i, nil
+}
+
+func (repo *gormRepository) GetGroupInvitations(ctx context.Context, groupID uuid.UUID) ([]*domain.GroupInvitation, error) {
+	invitations, err := getGroupInvitations(getTx(ctx, repo.db.WithContext(ctx)).Preload("CreatedBy"), groupID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPGroupInvitationToSPdomainGroupInvitation(invitations), nil
+}
+
+func (repo *gormRepository) DeleteGroupInvitation(ctx context.Context, invitationID uuid.UUID) error {
+	err := deleteGroupInvitation(getTx(ctx, repo.db.WithContext(ctx)), invitationID)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) CreateGroupJoinRequest(ctx context.Context, args domain.CreateGroupJoinRequestArgs) (*domain.GroupJoinRequest, error) {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	request, err := createGroupJoinRequest(tx, args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	request, err = getGroupJoinRequest(tx.Preload("User"), request.ID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	r := ConvGroupJoinRequestTodomainGroupJoinRequest(*request)
+	return &r, nil
+}
+
+func (repo *gormRepository) GetGroupJoinRequest(ctx context.Context, requestID uuid.UUID) (*domain.GroupJoinRequest, error) {
+	request, err := getGroupJoinRequest(getTx(ctx, repo.db.WithContext(ctx)).Preload("User"), requestID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	r := ConvGroupJoinRequestTodomainGroupJoinRequest(*request)
+	return &r, nil
+}
+
+func (repo *gormRepository) GetGroupJoinRequests(ctx context.Context, groupID uuid.UUID) ([]*domain.GroupJoinRequest, error) {
+	requests, err := getGroupJoinRequests(getTx(ctx, repo.db.WithContext(ctx)).Preload("User"), groupID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPGroupJoinRequestToSPdomainGroupJoinRequest(requests), nil
+}
+
+func (repo *gormRepository) DeleteGroupJoinRequest(ctx context.Context, requestID uuid.UUID) error {
+	err := deleteGroupJoinRequest(getTx(ctx, repo.db.WithContext(ctx)), requestID)
+	return defaultErrorHandling(err)
+}
+
+func convCreateGroupInvitationArgsToGroupInvitation(src domain.CreateGroupInvitationArgs) (dst GroupInvitation) {
+	dst.GroupID = src.GroupID
+	dst.Token = src.Token
+	dst.ExpiresAt = src.ExpiresAt
+	dst.Invitee = src.Invitee
+	dst.CreatedByRefer = src.CreatedBy
+	return
+}
+
+func createGroupInvitation(db *gorm.DB, args domain.CreateGroupInvitationArgs) (*GroupInvitation, error) {
+	invitation := convCreateGroupInvitationArgsToGroupInvitation(args)
+	var err error
+	invitation.ID, err = uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	err = db.Create(&invitation).Error
+	return &invitation, err
+}
+
+func getGroupInvitation(db *gorm.DB, query string, arg interface{}) (*GroupInvitation, error) {
+	invitation := GroupInvitation{}
+	err := db.Where(query, arg).Take(&invitation).Error
+	return &invitation, err
+}
+
+func getGroupInvitations(db *gorm.DB, groupID uuid.UUID) ([]*GroupInvitation, error) {
+	invitations := make([]*GroupInvitation, 0)
+	err := db.Where("group_id = ?", groupID).Order("created_at").Find(&invitations).Error
+	return invitations, err
+}
+
+func deleteGroupInvitation(db *gorm.DB, invitationID uuid.UUID) error {
+	invitation := GroupInvitation{
+		ID: invitationID,
+	}
+	return db.Delete(&invitation).Error
+}
+
+func convCreateGroupJoinRequestArgsToGroupJoinRequest(src domain.CreateGroupJoinRequestArgs) (dst GroupJoinRequest) {
+	dst.GroupID = src.GroupID
+	dst.UserID = src.UserID
+	dst.Message = src.Message
+	return
+}
+
+func createGroupJoinRequest(db *gorm.DB, args domain.CreateGroupJoinRequestArgs) (*GroupJoinRequest, error) {
+	request := convCreateGroupJoinRequestArgsToGroupJoinRequest(args)
+	var err error
+	request.ID, err = uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	err = db.Create(&request).Error
+	return &request, err
+}
+
+func getGroupJoinRequest(db *gorm.DB, requestID uuid.UUID) (*GroupJoinRequest, error) {
+	request := GroupJoinRequest{}
+	err := db.Take(&request, requestID).Error
+	return &request, err
+}
+
+func getGroupJoinRequests(db *gorm.DB, groupID uuid.UUID) ([]*GroupJoinRequest, error) {
+	requests := make([]*GroupJoinRequest, 0)
+	err := db.Where("group_id = ?", groupID).Order("created_at").Find(&requests).Error
+	return requests, err
+}
+
+func deleteGroupJoinRequest(db *gorm.DB, requestID uuid.UUID) error {
+	request := GroupJoinRequest{
+		ID: requestID,
+	}
+	return db.Delete(&request).Error
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func Test_groupInvitation(t *testing.T) {
+	r, assert, require, user, group := setupRepoWithUserGroup(t, common)
+
+	invitation, err := createGroupInvitation(r.db, domain.CreateGroupInvitationArgs{
+		GroupID:   group.ID,
+		Token:     "token",
+		CreatedBy: user.ID,
+	})
+	require.NoError(err)
+
+	t.Run("get invitation by token", func(_ *testing.T) {
+		i, err := getGroupInvitation(r.db, "token = ?", "token")
+		require.NoError(err)
+		assert.Equal(invitation.ID, i.ID)
+		assert.Equal(group.ID, i.GroupID)
+	})
+
+	t.Run("duplicate token", func(_ *testing.T) {
+		_, err := createGroupInvitation(r.db, domain.CreateGroupInvitationArgs{
+			GroupID:   group.ID,
+			Token:     "token",
+			CreatedBy: user.ID,
+		})
+		assert.Error(err)
+	})
+
+	t.Run("delete invitation", func(_ *testing.T) {
+		require.NoError(deleteGroupInvitation(r.db, invitation.ID))
+		_, err := getGroupInvitation(r.db, "token = ?", "token")
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func Test_groupJoinRequest(t *testing.T) {
+	r, assert, require, _, group := setupRepoWithUserGroup(t, common)
+	user := mustMakeUser(t, r, false)
+
+	request, err := createGroupJoinRequest(r.db, domain.CreateGroupJoinRequestArgs{
+		GroupID: group.ID,
+		UserID:  user.ID,
+		WriteGroupJoinRequestParams: domain.WriteGroupJoinRequestParams{
+			Message: "hello",
+		},
+	})
+	require.NoError(err)
+
+	t.Run("get requests", func(_ *testing.T) {
+		requests, err := getGroupJoinRequests(r.db.Preload("User"), group.ID)
+		require.NoError(err)
+		require.Len(requests, 1)
+		assert.Equal(user.ID, requests[0].User.ID)
+		assert.Equal("hello", requests[0].Message)
+	})
+
+	t.Run("delete request", func(_ *testing.T) {
+		require.NoError(deleteGroupJoinRequest(r.db, request.ID))
+		requests, err := getGroupJoinRequests(r.db, group.ID)
+		require.NoError(err)
+		assert.Len(requests, 0)
+	})
+}
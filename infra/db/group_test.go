@@ -166,7 +166,7 @@ func Test_deleteMemberOfGroup(t *testing.T) {
 
 	t.Run("delete invalid member", func(t *testing.T) {
 		err := deleteMemberOfGroup(r.db.Debug(), group.ID, mustNewUUIDV4(t))
-		assert.NoError(err)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
 	})
 }
 
@@ -184,3 +184,177 @@ func Test_getGroup(t *testing.T) {
 		assert.ErrorIs(err, gorm.ErrRecordNotFound)
 	})
 }
+
+func Test_updateGroupAdmins(t *testing.T) {
+	r, assert, require, user, group := setupRepoWithUserGroup(t, common)
+	other := mustMakeUser(t, r, false)
+
+	t.Run("add admin", func(_ *testing.T) {
+		require.NoError(addAdminToGroup(r.db, group.ID, other.ID))
+		g, err := getGroup(r.db.Preload("Admins"), group.ID)
+		require.NoError(err)
+		assert.Len(g.Admins, 2)
+	})
+
+	t.Run("delete admin", func(_ *testing.T) {
+		require.NoError(deleteAdminOfGroup(r.db, group.ID, other.ID))
+		g, err := getGroup(r.db.Preload("Admins"), group.ID)
+		require.NoError(err)
+		require.Len(g.Admins, 1)
+		assert.Equal(user.ID, g.Admins[0].UserID)
+	})
+
+	t.Run("delete non-admin", func(_ *testing.T) {
+		err := deleteAdminOfGroup(r.db, group.ID, other.ID)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("delete last admin", func(_ *testing.T) {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			return deleteAdminOfGroup(tx, group.ID, user.ID)
+		})
+		assert.ErrorIs(err, ErrNoAdmins)
+	})
+}
+
+func Test_updateGroupArchived(t *testing.T) {
+	r, assert, require, _, group := setupRepoWithUserGroup(t, common)
+
+	t.Run("archive", func(_ *testing.T) {
+		require.NoError(updateGroupArchived(r.db, group.ID, true))
+		g, err := getGroup(r.db, group.ID)
+		require.NoError(err)
+		assert.True(g.Archived)
+	})
+
+	t.Run("archive twice", func(_ *testing.T) {
+		assert.NoError(updateGroupArchived(r.db, group.ID, true))
+	})
+
+	t.Run("unarchive", func(_ *testing.T) {
+		require.NoError(updateGroupArchived(r.db, group.ID, false))
+		g, err := getGroup(r.db, group.ID)
+		require.NoError(err)
+		assert.False(g.Archived)
+	})
+
+	t.Run("random groupID", func(_ *testing.T) {
+		err := updateGroupArchived(r.db, mustNewUUIDV4(t), true)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func Test_subgroups(t *testing.T) {
+	r, assert, require, user, group := setupRepoWithUserGroup(t, common)
+
+	args := func(name string, parentID uuid.UUID) domain.UpsertGroupArgs {
+		return domain.UpsertGroupArgs{
+			CreatedBy: user.ID,
+			WriteGroupParams: domain.WriteGroupParams{
+				Name:     name,
+				Admins:   []uuid.UUID{user.ID},
+				ParentID: parentID,
+			},
+		}
+	}
+	child, err := createGroup(r.db, args("child", group.ID))
+	require.NoError(err)
+	grandchild, err := createGroup(r.db, args("grandchild", child.ID))
+	require.NoError(err)
+
+	t.Run("get subgroups", func(_ *testing.T) {
+		ids, err := getSubgroupIDs(r.db, group.ID)
+		require.NoError(err)
+		assert.ElementsMatch([]uuid.UUID{child.ID, grandchild.ID}, ids)
+	})
+
+	t.Run("get ancestors", func(_ *testing.T) {
+		ids, err := getAncestorGroupIDs(r.db, []uuid.UUID{grandchild.ID})
+		require.NoError(err)
+		assert.ElementsMatch([]uuid.UUID{child.ID, group.ID}, ids)
+	})
+
+	t.Run("cycle", func(_ *testing.T) {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			_, err := updateGroup(tx, group.ID, args("group", grandchild.ID))
+			return err
+		})
+		assert.ErrorIs(err, ErrGroupCycle)
+	})
+
+	t.Run("self parent", func(_ *testing.T) {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			_, err := updateGroup(tx, group.ID, args("group", group.ID))
+			return err
+		})
+		assert.ErrorIs(err, ErrGroupCycle)
+	})
+
+	t.Run("random parent", func(t *testing.T) {
+		_, err := createGroup(r.db, args("orphan", mustNewUUIDV4(t)))
+		assert.ErrorIs(err, ErrInvalidArgs)
+	})
+}
+
+func Test_groupRole(t *testing.T) {
+	r, assert, require, _, group := setupRepoWithUserGroup(t, common)
+	other := mustMakeUser(t, r, false)
+
+	t.Run("add organizer", func(_ *testing.T) {
+		require.NoError(upsertGroupRole(r.db, group.ID, other.ID, domain.GroupRoleOrganizer))
+		g, err := getGroup(groupFullPreload(r.db), group.ID)
+		require.NoError(err)
+		dg := convGroupWithRolesTodomainGroup(*g)
+		require.Len(dg.Organizers, 1)
+		assert.Equal(other.ID, dg.Organizers[0].ID)
+		assert.Len(dg.Viewers, 0)
+	})
+
+	t.Run("replace with viewer", func(_ *testing.T) {
+		require.NoError(upsertGroupRole(r.db, group.ID, other.ID, domain.GroupRoleViewer))
+		g, err := getGroup(groupFullPreload(r.db), group.ID)
+		require.NoError(err)
+		dg := convGroupWithRolesTodomainGroup(*g)
+		assert.Len(dg.Organizers, 0)
+		require.Len(dg.Viewers, 1)
+		assert.Equal(other.ID, dg.Viewers[0].ID)
+	})
+
+	t.Run("delete other role", func(_ *testing.T) {
+		err := deleteGroupRole(r.db, group.ID, other.ID, domain.GroupRoleOrganizer)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+		g, err := getGroup(groupFullPreload(r.db), group.ID)
+		require.NoError(err)
+		assert.Len(g.Roles, 1)
+	})
+
+	t.Run("delete viewer", func(_ *testing.T) {
+		require.NoError(deleteGroupRole(r.db, group.ID, other.ID, domain.GroupRoleViewer))
+		g, err := getGroup(groupFullPreload(r.db), group.ID)
+		require.NoError(err)
+		assert.Len(g.Roles, 0)
+	})
+
+	t.Run("invalid role", func(_ *testing.T) {
+		err := upsertGroupRole(r.db, group.ID, other.ID, domain.GroupRoleAdmin)
+		assert.ErrorIs(err, ErrInvalidArgs)
+	})
+}
+
+func Test_groupAuditLog(t *testing.T) {
+	r, assert, require, user, group := setupRepoWithUserGroup(t, common)
+	other := mustMakeUser(t, r, false)
+
+	require.NoError(createGroupAuditLog(r.db, domain.CreateGroupAuditLogArgs{
+		GroupID:   group.ID,
+		Action:    domain.GroupAuditAddMember,
+		TargetID:  other.ID,
+		CreatedBy: user.ID,
+	}))
+
+	logs, err := getGroupAuditLogs(r.db, group.ID)
+	require.NoError(err)
+	require.Len(logs, 1)
+	assert.Equal(string(domain.GroupAuditAddMember), logs[0].Action)
+	assert.Equal(other.ID, logs[0].TargetID)
+}
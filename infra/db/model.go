@@ -11,12 +11,26 @@ var tables = []interface{}{
 	User{},
 	Token{},
 	Provider{},
+	UserProfile{},
+	PersonalAccessToken{},
+	OAuthClient{},
+	OAuthAuthorizationCode{},
+	OAuthToken{},
 	Group{},
 	GroupMember{},
 	GroupAdmin{},
+	GroupUserRole{},
+	GroupInvitation{},
+	GroupJoinRequest{},
+	GroupAuditLog{},
+	GroupICalToken{},
 	Tag{},
 	Room{},
 	RoomAdmin{},
+	RoomBlackout{},
+	RoomSeries{},
+	RoomSeriesException{},
+	PlaceAttribute{},
 	Event{},
 	EventTag{}, // Eventより下にないと、overrideされる
 	EventAdmin{},
@@ -33,7 +47,8 @@ type Model struct {
 type Oauth2Token struct {
 	// AccessToken is the token that authorizes and authenticates
 	// the requests.
-	AccessToken string `gorm:"type:varbinary(64)"`
+	// 暗号化して保存する
+	AccessToken string `gorm:"type:varbinary(2048)"`
 
 	// TokenType is the type of token.
 	// The Type method returns either this or "Bearer", the default.
@@ -42,7 +57,8 @@ type Oauth2Token struct {
 	// RefreshToken is a token that's used by the application
 	// (as opposed to the user) to refresh the access token
 	// if it expires.
-	RefreshToken string
+	// 暗号化して保存する
+	RefreshToken string `gorm:"type:varbinary(2048)"`
 
 	// Expiry is the optional expiration time of the access token.
 	//
@@ -63,6 +79,14 @@ type Provider struct {
 	Subject string
 }
 
+// UserProfile traQ 以外のプロバイダで認証したユーザーの名前とアイコン
+type UserProfile struct {
+	UserID      uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Name        string    `gorm:"type:varchar(256); not null"`
+	DisplayName string    `gorm:"type:varchar(256); not null"`
+	Icon        string    `gorm:"type:text"`
+}
+
 type User struct {
 	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
 	// アプリの管理者かどうか
@@ -71,6 +95,63 @@ type User struct {
 	IcalSecret string   `gorm:"not null"`
 	Provider   Provider `gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE;"`
 	Token      Token    `gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	// traQ のユーザーの場合は nil
+	Profile *UserProfile `gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+}
+
+// PersonalAccessToken TokenHash はトークンの SHA-256
+type PersonalAccessToken struct {
+	ID         uuid.UUID `gorm:"type:char(36); primaryKey"`
+	UserID     uuid.UUID `gorm:"type:char(36); not null; index"`
+	User       User      `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	Name       string    `gorm:"type:varchar(64); not null"`
+	TokenHash  string    `gorm:"type:char(64); not null; uniqueIndex"`
+	Scopes     string    `gorm:"type:varchar(128); not null"`
+	ExpiresAt  time.Time `gorm:"type:DATETIME"`
+	LastUsedAt time.Time `gorm:"type:DATETIME"`
+	Model
+}
+
+// OAuthClient SecretHash は client_secret の SHA-256。公開クライアントの場合は空
+// RedirectURIs は改行区切り
+type OAuthClient struct {
+	ID             uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Name           string    `gorm:"type:varchar(64); not null"`
+	Description    string    `gorm:"type:TEXT"`
+	RedirectURIs   string    `gorm:"type:TEXT; not null"`
+	Scopes         string    `gorm:"type:varchar(128); not null"`
+	SecretHash     string    `gorm:"type:char(64)"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);"`
+	CreatedBy      User      `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	Model
+}
+
+// OAuthAuthorizationCode CodeHash は認可コードの SHA-256
+type OAuthAuthorizationCode struct {
+	CodeHash      string      `gorm:"type:char(64); primaryKey"`
+	ClientID      uuid.UUID   `gorm:"type:char(36); not null; index"`
+	Client        OAuthClient `gorm:"->; foreignKey:ClientID; constraint:OnDelete:CASCADE;"`
+	UserID        uuid.UUID   `gorm:"type:char(36); not null"`
+	User          User        `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	RedirectURI   string      `gorm:"type:TEXT; not null"`
+	Scopes        string      `gorm:"type:varchar(128); not null"`
+	CodeChallenge string      `gorm:"type:varchar(128); not null"`
+	ExpiresAt     time.Time   `gorm:"type:DATETIME; not null"`
+	CreatedAt     time.Time
+}
+
+// OAuthToken AccessTokenHash, RefreshTokenHash はトークンの SHA-256
+type OAuthToken struct {
+	ID               uuid.UUID   `gorm:"type:char(36); primaryKey"`
+	ClientID         uuid.UUID   `gorm:"type:char(36); not null; index"`
+	Client           OAuthClient `gorm:"->; foreignKey:ClientID; constraint:OnDelete:CASCADE;"`
+	UserID           uuid.UUID   `gorm:"type:char(36); not null; index"`
+	User             User        `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	AccessTokenHash  string      `gorm:"type:char(64); not null; uniqueIndex"`
+	RefreshTokenHash string      `gorm:"type:char(64); not null; uniqueIndex"`
+	Scopes           string      `gorm:"type:varchar(128); not null"`
+	ExpiresAt        time.Time   `gorm:"type:DATETIME; not null"`
+	Model
 }
 
 type RoomAdmin struct {
@@ -86,15 +167,74 @@ type Room struct {
 	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
 	Place          string    `gorm:"type:varchar(32);"`
 	Verified       bool
+	TimeStart      time.Time       `gorm:"type:DATETIME; index"`
+	TimeEnd        time.Time       `gorm:"type:DATETIME; index"`
+	Events         []Event         `gorm:"->; constraint:-"` // readOnly
+	Blackouts      []RoomBlackout  `gorm:"-"`                // attachRoomBlackouts で埋める
+	SeriesID       uuid.UUID       `gorm:"type:char(36); not null; default:'00000000-0000-0000-0000-000000000000'; index"`
+	SharedCapacity int             `gorm:"not null; default:0"`
+	Attribute      *PlaceAttribute `gorm:"->; foreignKey:Place; references:Place; constraint:-"` // readOnly
+	Admins         []RoomAdmin
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);" cvt:"CreatedBy, <-"`
+	CreatedBy      User      `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;" cvt:"->"`
+	Model          `cvt:"->"`
+}
+
+// PlaceAttribute 場所ごとの設備
+// 部屋の Place と同じ文字列で紐づく
+//
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s PlaceAttribute -d domain.PlaceAttribute -o converter.go .
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*PlaceAttribute -d []*domain.PlaceAttribute -o converter.go .
+type PlaceAttribute struct {
+	Place        string `gorm:"type:varchar(32); primaryKey"`
+	Capacity     int
+	Projector    bool
+	Whiteboard   bool
+	PowerOutlets bool
+	Accessible   bool
+	// SharedCapacity 同時に入れる共用イベントの数。0 は制限なし
+	SharedCapacity int       `gorm:"not null; default:0"`
+	CreatedAt      time.Time `gorm:"<-:create" cvt:"-"`
+	UpdatedAt      time.Time `cvt:"-"`
+}
+
+// RoomBlackout RoomID が uuid.Nil の場合は Place の全ての部屋に適用される
+// 部屋に紐づく場合も Place を保存する
+//
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s RoomBlackout -d domain.RoomBlackout -o converter.go .
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*RoomBlackout -d []*domain.RoomBlackout -o converter.go .
+type RoomBlackout struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	RoomID         uuid.UUID `gorm:"type:char(36); index"`
+	Place          string    `gorm:"type:varchar(32); index"`
 	TimeStart      time.Time `gorm:"type:DATETIME; index"`
 	TimeEnd        time.Time `gorm:"type:DATETIME; index"`
-	Events         []Event   `gorm:"->; constraint:-"` // readOnly
-	Admins         []RoomAdmin
+	Reason         string    `gorm:"type:TEXT"`
 	CreatedByRefer uuid.UUID `gorm:"type:char(36);" cvt:"CreatedBy, <-"`
 	CreatedBy      User      `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;" cvt:"->"`
 	Model          `cvt:"->"`
 }
 
+type RoomSeriesException struct {
+	SeriesID uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Date     time.Time `gorm:"type:DATE; primaryKey"`
+}
+
+// RoomSeries Weekdays は time.Weekday ごとのビットで表す
+type RoomSeries struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Place          string    `gorm:"type:varchar(32);"`
+	Weekdays       int
+	TimeStart      time.Time             `gorm:"type:DATETIME"`
+	TimeEnd        time.Time             `gorm:"type:DATETIME"`
+	Until          time.Time             `gorm:"type:DATE"`
+	Exceptions     []RoomSeriesException `gorm:"foreignKey:SeriesID; constraint:OnDelete:CASCADE;"`
+	Rooms          []Room                `gorm:"->; foreignKey:SeriesID; constraint:-"` // readOnly
+	CreatedByRefer uuid.UUID             `gorm:"type:char(36);"`
+	CreatedBy      User                  `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	Model
+}
+
 //go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*GroupMember -d []uuid.UUID -o converter.go -structTag cvt0 .
 type GroupMember struct {
 	UserID  uuid.UUID `gorm:"type:char(36); primaryKey" cvt0:"<-"`
@@ -110,17 +250,84 @@ type GroupAdmin struct {
 	Model   `cvt:"-"`
 }
 
+// GroupUserRole メンバーと管理者以外の役割
+// Role は domain.GroupRoleOrganizer か domain.GroupRoleViewer
+type GroupUserRole struct {
+	UserID  uuid.UUID `gorm:"type:char(36); primaryKey"`
+	GroupID uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Role    string    `gorm:"type:varchar(16); not null"`
+	User    User      `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;" cvt:"->"`
+	Model   `cvt:"-"`
+}
+
 // Group is user group
 //
 //go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s Group -d domain.Group -o converter.go .
 //go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*Group -d []*domain.Group -o converter.go .
 type Group struct {
-	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
-	Name           string    `gorm:"type:varchar(32);not null"`
-	Description    string    `gorm:"type:TEXT"`
-	JoinFreely     bool
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Name        string    `gorm:"type:varchar(32);not null"`
+	Description string    `gorm:"type:TEXT"`
+	JoinFreely  bool
+	Archived    bool `gorm:"not null; default:false; index"`
+	// ParentID uuid.Nil の場合は親を持たない
+	ParentID       uuid.UUID `gorm:"type:char(36); index"`
 	Members        []GroupMember
 	Admins         []GroupAdmin
+	Roles          []GroupUserRole `cvt:"-"`
+	CreatedByRefer uuid.UUID       `gorm:"type:char(36);" cvt:"CreatedBy, <-"`
+	CreatedBy      User            `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;" cvt:"->"`
+	Model          `cvt:"->"`
+}
+
+// GroupInvitation Token はグループへの招待リンクに使う
+// Invitee が uuid.Nil の場合は誰でも使える
+//
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s GroupInvitation -d domain.GroupInvitation -o converter.go .
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*GroupInvitation -d []*domain.GroupInvitation -o converter.go .
+type GroupInvitation struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID        uuid.UUID `gorm:"type:char(36); not null; index"`
+	Token          string    `gorm:"type:varchar(32); not null; uniqueIndex"`
+	ExpiresAt      time.Time `gorm:"type:DATETIME"`
+	Invitee        uuid.UUID `gorm:"type:char(36)"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);" cvt:"CreatedBy, <-"`
+	CreatedBy      User      `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;" cvt:"->"`
+	Model          `cvt:"->"`
+}
+
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s GroupJoinRequest -d domain.GroupJoinRequest -o converter.go .
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*GroupJoinRequest -d []*domain.GroupJoinRequest -o converter.go .
+type GroupJoinRequest struct {
+	ID      uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID uuid.UUID `gorm:"type:char(36); not null; index"`
+	UserID  uuid.UUID `gorm:"type:char(36); not null; index" cvt:"User, <-"`
+	User    User      `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;" cvt:"->"`
+	Message string    `gorm:"type:TEXT"`
+	Model   `cvt:"->"`
+}
+
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s GroupAuditLog -d domain.GroupAuditLog -o converter.go .
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*GroupAuditLog -d []*domain.GroupAuditLog -o converter.go .
+type GroupAuditLog struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID        uuid.UUID `gorm:"type:char(36); not null; index"`
+	Action         string    `gorm:"type:varchar(32); not null"`
+	TargetID       uuid.UUID `gorm:"type:char(36); not null" cvt:"Target, <-"`
+	Target         User      `gorm:"->; foreignKey:TargetID; constraint:OnDelete:CASCADE;" cvt:"->"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);" cvt:"CreatedBy, <-"`
+	CreatedBy      User      `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;" cvt:"->"`
+	Model          `cvt:"->"`
+}
+
+// GroupICalToken GroupID は traQ のグループの場合もあるので groups を参照しない
+//
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s GroupICalToken -d domain.GroupICalToken -o converter.go .
+//go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*GroupICalToken -d []*domain.GroupICalToken -o converter.go .
+type GroupICalToken struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID        uuid.UUID `gorm:"type:char(36); not null; index"`
+	Token          string    `gorm:"type:varchar(32); not null; uniqueIndex"`
 	CreatedByRefer uuid.UUID `gorm:"type:char(36);" cvt:"CreatedBy, <-"`
 	CreatedBy      User      `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;" cvt:"->"`
 	Model          `cvt:"->"`
@@ -180,5 +387,7 @@ type Event struct {
 	Tags           []EventTag
 	Open           bool
 	Attendees      []EventAttendee
-	Model          `cvt:"->"`
+	// BlackoutConflict refreshEventBlackoutConflicts で更新する
+	BlackoutConflict bool `gorm:"not null; default:false"`
+	Model            `cvt:"->"`
 }
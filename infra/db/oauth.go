@@ -0,0 +1,264 @@
+package db
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/samber/lo"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func (repo *gormRepository) CreateOAuthClient(ctx context.Context, args domain.CreateOAuthClientArgs) (*domain.OAuthClient, error) {
+	client, err := createOAuthClient(getTx(ctx, repo.db.WithContext(ctx)), args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	c := convOAuthClientTodomainOAuthClient(*client)
+	c.Secret = args.Secret
+	return &c, nil
+}
+
+func (repo *gormRepository) GetOAuthClient(ctx context.Context, clientID uuid.UUID) (*domain.OAuthClient, error) {
+	client, err := getOAuthClient(getTx(ctx, repo.db.WithContext(ctx)), clientID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	c := convOAuthClientTodomainOAuthClient(*client)
+	return &c, nil
+}
+
+func (repo *gormRepository) GetAllOAuthClients(ctx context.Context) ([]*domain.OAuthClient, error) {
+	clients, err := getAllOAuthClients(getTx(ctx, repo.db.WithContext(ctx)))
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return lo.Map(clients, func(c *OAuthClient, _ int) *domain.OAuthClient {
+		dc := convOAuthClientTodomainOAuthClient(*c)
+		return &dc
+	}), nil
+}
+
+func (repo *gormRepository) VerifyOAuthClientSecret(ctx context.Context, clientID uuid.UUID, secret string) (bool, error) {
+	client, err := getOAuthClient(getTx(ctx, repo.db.WithContext(ctx)), clientID)
+	if err != nil {
+		return false, defaultErrorHandling(err)
+	}
+	if client.SecretHash == "" {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashToken(secret))) == 1, nil
+}
+
+// DeleteOAuthClient クライアントに発行したトークンも無効にする
+func (repo *gormRepository) DeleteOAuthClient(ctx context.Context, clientID uuid.UUID) error {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		return deleteOAuthClient(tx, clientID)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) CreateOAuthAuthorizationCode(ctx context.Context, args domain.CreateOAuthAuthorizationCodeArgs) error {
+	err := createOAuthAuthorizationCode(getTx(ctx, repo.db.WithContext(ctx)), args)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) TakeOAuthAuthorizationCode(ctx context.Context, code string) (*domain.OAuthAuthorizationCode, error) {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	var c *OAuthAuthorizationCode
+	err := tx.Transaction(func(tx *gorm.DB) (err error) {
+		c, err = takeOAuthAuthorizationCode(tx, code)
+		return err
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	dc := convOAuthAuthorizationCodeTodomainOAuthAuthorizationCode(*c)
+	return &dc, nil
+}
+
+func (repo *gormRepository) CreateOAuthToken(ctx context.Context, args domain.CreateOAuthTokenArgs) (*domain.OAuthToken, error) {
+	token, err := createOAuthToken(getTx(ctx, repo.db.WithContext(ctx)), args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := convOAuthTokenTodomainOAuthToken(*token)
+	t.AccessToken = args.AccessToken
+	t.RefreshToken = args.RefreshToken
+	return &t, nil
+}
+
+func (repo *gormRepository) GetOAuthTokenByAccessToken(ctx context.Context, accessToken string) (*domain.OAuthToken, error) {
+	token, err := getOAuthTokenByHash(getTx(ctx, repo.db.WithContext(ctx)), "access_token_hash", accessToken)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := convOAuthTokenTodomainOAuthToken(*token)
+	return &t, nil
+}
+
+func (repo *gormRepository) GetOAuthTokenByRefreshToken(ctx context.Context, refreshToken string) (*domain.OAuthToken, error) {
+	token, err := getOAuthTokenByHash(getTx(ctx, repo.db.WithContext(ctx)), "refresh_token_hash", refreshToken)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := convOAuthTokenTodomainOAuthToken(*token)
+	return &t, nil
+}
+
+func (repo *gormRepository) DeleteOAuthToken(ctx context.Context, tokenID uuid.UUID) error {
+	err := deleteOAuthToken(getTx(ctx, repo.db.WithContext(ctx)), tokenID)
+	return defaultErrorHandling(err)
+}
+
+func createOAuthClient(db *gorm.DB, args domain.CreateOAuthClientArgs) (*OAuthClient, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	client := OAuthClient{
+		ID:             id,
+		Name:           args.Name,
+		Description:    args.Description,
+		RedirectURIs:   strings.Join(args.RedirectURIs, "\n"),
+		Scopes:         joinScopes(args.Scopes),
+		CreatedByRefer: args.CreatedBy,
+	}
+	if args.Secret != "" {
+		client.SecretHash = hashToken(args.Secret)
+	}
+	err = db.Create(&client).Error
+	return &client, err
+}
+
+func getOAuthClient(db *gorm.DB, clientID uuid.UUID) (*OAuthClient, error) {
+	client := OAuthClient{}
+	err := db.Take(&client, clientID).Error
+	return &client, err
+}
+
+func getAllOAuthClients(db *gorm.DB) ([]*OAuthClient, error) {
+	clients := make([]*OAuthClient, 0)
+	err := db.Order("created_at").Find(&clients).Error
+	return clients, err
+}
+
+func deleteOAuthClient(db *gorm.DB, clientID uuid.UUID) error {
+	err := db.Where("client_id = ?", clientID).Delete(&OAuthAuthorizationCode{}).Error
+	if err != nil {
+		return err
+	}
+	err = db.Where("client_id = ?", clientID).Delete(&OAuthToken{}).Error
+	if err != nil {
+		return err
+	}
+	return db.Delete(&OAuthClient{ID: clientID}).Error
+}
+
+func createOAuthAuthorizationCode(db *gorm.DB, args domain.CreateOAuthAuthorizationCodeArgs) error {
+	code := OAuthAuthorizationCode{
+		CodeHash:      hashToken(args.Code),
+		ClientID:      args.ClientID,
+		UserID:        args.UserID,
+		RedirectURI:   args.RedirectURI,
+		Scopes:        joinScopes(args.Scopes),
+		CodeChallenge: args.CodeChallenge,
+		ExpiresAt:     args.ExpiresAt,
+	}
+	return db.Create(&code).Error
+}
+
+// takeOAuthAuthorizationCode 同時に使われても、削除できた方だけが認可コードを得る
+func takeOAuthAuthorizationCode(db *gorm.DB, code string) (*OAuthAuthorizationCode, error) {
+	c := OAuthAuthorizationCode{}
+	err := db.Where("code_hash = ?", hashToken(code)).Take(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	result := db.Where("code_hash = ?", c.CodeHash).Delete(&OAuthAuthorizationCode{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &c, nil
+}
+
+func createOAuthToken(db *gorm.DB, args domain.CreateOAuthTokenArgs) (*OAuthToken, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	token := OAuthToken{
+		ID:               id,
+		ClientID:         args.ClientID,
+		UserID:           args.UserID,
+		AccessTokenHash:  hashToken(args.AccessToken),
+		RefreshTokenHash: hashToken(args.RefreshToken),
+		Scopes:           joinScopes(args.Scopes),
+		ExpiresAt:        args.ExpiresAt,
+	}
+	err = db.Create(&token).Error
+	return &token, err
+}
+
+func getOAuthTokenByHash(db *gorm.DB, column, token string) (*OAuthToken, error) {
+	t := OAuthToken{}
+	err := db.Where(column+" = ?", hashToken(token)).Take(&t).Error
+	return &t, err
+}
+
+// deleteOAuthToken 同時に削除した場合は後の方が ErrRecordNotFound になる
+func deleteOAuthToken(db *gorm.DB, tokenID uuid.UUID) error {
+	result := db.Where("deleted_at IS NULL").Delete(&OAuthToken{ID: tokenID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewValueError(gorm.ErrRecordNotFound, "tokenID")
+	}
+	return nil
+}
+
+func convOAuthClientTodomainOAuthClient(src OAuthClient) (dst domain.OAuthClient) {
+	dst.ID = src.ID
+	dst.Name = src.Name
+	dst.Description = src.Description
+	dst.RedirectURIs = strings.Split(src.RedirectURIs, "\n")
+	dst.Scopes = splitScopes(src.Scopes)
+	dst.Confidential = src.SecretHash != ""
+	dst.CreatedBy = src.CreatedByRefer
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+
+func convOAuthAuthorizationCodeTodomainOAuthAuthorizationCode(src OAuthAuthorizationCode) (dst domain.OAuthAuthorizationCode) {
+	dst.ClientID = src.ClientID
+	dst.UserID = src.UserID
+	dst.RedirectURI = src.RedirectURI
+	dst.Scopes = splitScopes(src.Scopes)
+	dst.CodeChallenge = src.CodeChallenge
+	dst.ExpiresAt = src.ExpiresAt
+	return
+}
+
+func convOAuthTokenTodomainOAuthToken(src OAuthToken) (dst domain.OAuthToken) {
+	dst.ID = src.ID
+	dst.ClientID = src.ClientID
+	dst.UserID = src.UserID
+	dst.Scopes = splitScopes(src.Scopes)
+	dst.ExpiresAt = src.ExpiresAt
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func Test_oauth(t *testing.T) {
+	r, assert, require, user := setupRepoWithUser(t, common)
+	clientArgs := domain.CreateOAuthClientArgs{
+		WriteOAuthClientParams: domain.WriteOAuthClientParams{
+			Name:         "app",
+			RedirectURIs: []string{"https://example.com/callback", "https://example.com/callback2"},
+			Scopes:       []domain.AccessTokenScope{domain.AccessTokenScopeRSVP},
+			Confidential: true,
+		},
+		CreatedBy: user.ID,
+		Secret:    "secret",
+	}
+	client, err := createOAuthClient(r.db, clientArgs)
+	require.NoError(err)
+
+	t.Run("get client", func(_ *testing.T) {
+		c, err := getOAuthClient(r.db, client.ID)
+		require.NoError(err)
+		dc := convOAuthClientTodomainOAuthClient(*c)
+		assert.Equal(clientArgs.RedirectURIs, dc.RedirectURIs)
+		assert.Equal(clientArgs.Scopes, dc.Scopes)
+		assert.True(dc.Confidential)
+		assert.Equal(user.ID, dc.CreatedBy)
+	})
+
+	t.Run("verify secret", func(_ *testing.T) {
+		ok, err := r.VerifyOAuthClientSecret(t.Context(), client.ID, "secret")
+		require.NoError(err)
+		assert.True(ok)
+		ok, err = r.VerifyOAuthClientSecret(t.Context(), client.ID, "wrong")
+		require.NoError(err)
+		assert.False(ok)
+	})
+
+	t.Run("authorization code can be taken only once", func(_ *testing.T) {
+		err := createOAuthAuthorizationCode(r.db, domain.CreateOAuthAuthorizationCodeArgs{
+			OAuthAuthorizationCode: domain.OAuthAuthorizationCode{
+				ClientID:      client.ID,
+				UserID:        user.ID,
+				Scopes:        clientArgs.Scopes,
+				CodeChallenge: "challenge",
+				ExpiresAt:     time.Now().Add(time.Minute),
+			},
+			Code: "code",
+		})
+		require.NoError(err)
+
+		c, err := takeOAuthAuthorizationCode(r.db, "code")
+		require.NoError(err)
+		assert.Equal("challenge", c.CodeChallenge)
+		_, err = takeOAuthAuthorizationCode(r.db, "code")
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("token", func(_ *testing.T) {
+		args := domain.CreateOAuthTokenArgs{
+			ClientID:     client.ID,
+			UserID:       user.ID,
+			Scopes:       clientArgs.Scopes,
+			AccessToken:  "knoqo_" + mustNewUUIDV4(t).String(),
+			RefreshToken: "knoqr_" + mustNewUUIDV4(t).String(),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}
+		token, err := createOAuthToken(r.db, args)
+		require.NoError(err)
+
+		got, err := getOAuthTokenByHash(r.db, "access_token_hash", args.AccessToken)
+		require.NoError(err)
+		assert.Equal(token.ID, got.ID)
+		got, err = getOAuthTokenByHash(r.db, "refresh_token_hash", args.RefreshToken)
+		require.NoError(err)
+		assert.Equal(token.ID, got.ID)
+		_, err = getOAuthTokenByHash(r.db, "access_token_hash", args.RefreshToken)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		require.NoError(deleteOAuthToken(r.db, token.ID))
+		assert.ErrorIs(deleteOAuthToken(r.db, token.ID), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("delete client revokes tokens", func(_ *testing.T) {
+		args := domain.CreateOAuthTokenArgs{
+			ClientID:     client.ID,
+			UserID:       user.ID,
+			AccessToken:  "knoqo_" + mustNewUUIDV4(t).String(),
+			RefreshToken: "knoqr_" + mustNewUUIDV4(t).String(),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}
+		_, err := createOAuthToken(r.db, args)
+		require.NoError(err)
+
+		require.NoError(deleteOAuthClient(r.db, client.ID))
+		_, err = getOAuthClient(r.db, client.ID)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+		_, err = getOAuthTokenByHash(r.db, "access_token_hash", args.AccessToken)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/samber/lo"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func (repo *gormRepository) CreatePersonalAccessToken(ctx context.Context, args domain.CreatePersonalAccessTokenArgs) (*domain.PersonalAccessToken, error) {
+	token, err := createPersonalAccessToken(getTx(ctx, repo.db.WithContext(ctx)), args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := convPersonalAccessTokenTodomainPersonalAccessToken(*token)
+	t.Token = args.Token
+	return &t, nil
+}
+
+func (repo *gormRepository) GetPersonalAccessToken(ctx context.Context, tokenID uuid.UUID) (*domain.PersonalAccessToken, error) {
+	token, err := getPersonalAccessToken(getTx(ctx, repo.db.WithContext(ctx)), tokenID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := convPersonalAccessTokenTodomainPersonalAccessToken(*token)
+	return &t, nil
+}
+
+func (repo *gormRepository) GetPersonalAccessTokenByToken(ctx context.Context, token string) (*domain.PersonalAccessToken, error) {
+	pat, err := getPersonalAccessTokenByToken(getTx(ctx, repo.db.WithContext(ctx)), token)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	t := convPersonalAccessTokenTodomainPersonalAccessToken(*pat)
+	return &t, nil
+}
+
+func (repo *gormRepository) GetPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]*domain.PersonalAccessToken, error) {
+	tokens, err := getPersonalAccessTokens(getTx(ctx, repo.db.WithContext(ctx)), userID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return lo.Map(tokens, func(t *PersonalAccessToken, _ int) *domain.PersonalAccessToken {
+		dt := convPersonalAccessTokenTodomainPersonalAccessToken(*t)
+		return &dt
+	}), nil
+}
+
+func (repo *gormRepository) UpdatePersonalAccessTokenLastUsedAt(ctx context.Context, tokenID uuid.UUID, lastUsedAt time.Time) error {
+	err := updatePersonalAccessTokenLastUsedAt(getTx(ctx, repo.db.WithContext(ctx)), tokenID, lastUsedAt)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) DeletePersonalAccessToken(ctx context.Context, tokenID uuid.UUID) error {
+	err := deletePersonalAccessToken(getTx(ctx, repo.db.WithContext(ctx)), tokenID)
+	return defaultErrorHandling(err)
+}
+
+// hashToken トークンは十分長いランダムな文字列なので salt は付けない
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func convPersonalAccessTokenTodomainPersonalAccessToken(src PersonalAccessToken) (dst domain.PersonalAccessToken) {
+	dst.ID = src.ID
+	dst.UserID = src.UserID
+	dst.Name = src.Name
+	dst.Scopes = splitScopes(src.Scopes)
+	dst.ExpiresAt = src.ExpiresAt
+	dst.LastUsedAt = src.LastUsedAt
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+
+func splitScopes(src string) []domain.AccessTokenScope {
+	dst := make([]domain.AccessTokenScope, 0)
+	for _, s := range strings.Split(src, ",") {
+		if s != "" {
+			dst = append(dst, domain.AccessTokenScope(s))
+		}
+	}
+	return dst
+}
+
+func joinScopes(scopes []domain.AccessTokenScope) string {
+	return strings.Join(lo.Map(scopes, func(s domain.AccessTokenScope, _ int) string {
+		return string(s)
+	}), ",")
+}
+
+func createPersonalAccessToken(db *gorm.DB, args domain.CreatePersonalAccessTokenArgs) (*PersonalAccessToken, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	token := PersonalAccessToken{
+		ID:        id,
+		UserID:    args.UserID,
+		Name:      args.Name,
+		TokenHash: hashToken(args.Token),
+		Scopes:    joinScopes(args.Scopes),
+		ExpiresAt: args.ExpiresAt,
+	}
+	err = db.Create(&token).Error
+	return &token, err
+}
+
+func getPersonalAccessToken(db *gorm.DB, tokenID uuid.UUID) (*PersonalAccessToken, error) {
+	token := PersonalAccessToken{}
+	err := db.Take(&token, tokenID).Error
+	return &token, err
+}
+
+func getPersonalAccessTokenByToken(db *gorm.DB, token string) (*PersonalAccessToken, error) {
+	pat := PersonalAccessToken{}
+	err := db.Where("token_hash = ?", hashToken(token)).Take(&pat).Error
+	return &pat, err
+}
+
+func getPersonalAccessTokens(db *gorm.DB, userID uuid.UUID) ([]*PersonalAccessToken, error) {
+	tokens := make([]*PersonalAccessToken, 0)
+	err := db.Where("user_id = ?", userID).Order("created_at").Find(&tokens).Error
+	return tokens, err
+}
+
+func updatePersonalAccessTokenLastUsedAt(db *gorm.DB, tokenID uuid.UUID, lastUsedAt time.Time) error {
+	return db.Model(&PersonalAccessToken{ID: tokenID}).Update("last_used_at", lastUsedAt).Error
+}
+
+func deletePersonalAccessToken(db *gorm.DB, tokenID uuid.UUID) error {
+	return db.Delete(&PersonalAccessToken{ID: tokenID}).Error
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func Test_personalAccessToken(t *testing.T) {
+	r, assert, require := setupRepo(t, common)
+	user := mustMakeUser(t, r, false)
+	args := domain.CreatePersonalAccessTokenArgs{
+		WritePersonalAccessTokenParams: domain.WritePersonalAccessTokenParams{
+			Name:   "script",
+			Scopes: []domain.AccessTokenScope{domain.AccessTokenScopeReadEvents, domain.AccessTokenScopeRSVP},
+		},
+		UserID: user.ID,
+		Token:  "knoq_" + mustNewUUIDV4(t).String(),
+	}
+
+	token, err := createPersonalAccessToken(r.db, args)
+	require.NoError(err)
+
+	t.Run("token is stored hashed", func(_ *testing.T) {
+		assert.NotEqual(args.Token, token.TokenHash)
+		assert.Len(token.TokenHash, 64)
+	})
+
+	t.Run("get by token", func(_ *testing.T) {
+		pat, err := getPersonalAccessTokenByToken(r.db, args.Token)
+		require.NoError(err)
+		assert.Equal(token.ID, pat.ID)
+		dt := convPersonalAccessTokenTodomainPersonalAccessToken(*pat)
+		assert.Equal(args.Scopes, dt.Scopes)
+
+		_, err = getPersonalAccessTokenByToken(r.db, "knoq_unknown")
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("update last used at", func(_ *testing.T) {
+		now := time.Now()
+		require.NoError(updatePersonalAccessTokenLastUsedAt(r.db, token.ID, now))
+		pat, err := getPersonalAccessToken(r.db, token.ID)
+		require.NoError(err)
+		assert.WithinDuration(now, pat.LastUsedAt, time.Second)
+	})
+
+	t.Run("revoke", func(_ *testing.T) {
+		require.NoError(deletePersonalAccessToken(r.db, token.ID))
+		_, err := getPersonalAccessTokenByToken(r.db, args.Token)
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+		tokens, err := getPersonalAccessTokens(r.db, user.ID)
+		require.NoError(err)
+		assert.Empty(tokens)
+	})
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (repo *gormRepository) UpsertPlaceAttribute(ctx context.Context, attr domain.PlaceAttribute) (*domain.PlaceAttribute, error) {
+	a, err := upsertPlaceAttribute(getTx(ctx, repo.db.WithContext(ctx)), attr)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	pa := ConvPlaceAttributeTodomainPlaceAttribute(*a)
+	return &pa, nil
+}
+
+func (repo *gormRepository) DeletePlaceAttribute(ctx context.Context, place string) error {
+	err := deletePlaceAttribute(getTx(ctx, repo.db.WithContext(ctx)), place)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) GetPlaceAttributes(ctx context.Context) ([]*domain.PlaceAttribute, error) {
+	attrs, err := getPlaceAttributes(getTx(ctx, repo.db.WithContext(ctx)))
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPPlaceAttributeToSPdomainPlaceAttribute(attrs), nil
+}
+
+func upsertPlaceAttribute(db *gorm.DB, attr domain.PlaceAttribute) (*PlaceAttribute, error) {
+	a := PlaceAttribute{
+		Place:          attr.Place,
+		Capacity:       attr.Capacity,
+		Projector:      attr.Projector,
+		Whiteboard:     attr.Whiteboard,
+		PowerOutlets:   attr.PowerOutlets,
+		Accessible:     attr.Accessible,
+		SharedCapacity: attr.SharedCapacity,
+	}
+	err := db.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(&a).Error
+	return &a, err
+}
+
+func deletePlaceAttribute(db *gorm.DB, place string) error {
+	attr := PlaceAttribute{
+		Place: place,
+	}
+	return db.Delete(&attr).Error
+}
+
+func getPlaceAttributes(db *gorm.DB) ([]*PlaceAttribute, error) {
+	attrs := make([]*PlaceAttribute, 0)
+	err := db.Order("place").Find(&attrs).Error
+	return attrs, err
+}
+
+// filterRoomAttribute 設備の条件に合う場所の部屋に絞り込む
+func filterRoomAttribute(db *gorm.DB, attr domain.RoomAttributeFilter) *gorm.DB {
+	if attr.IsZero() {
+		return db
+	}
+	sub := db.Session(&gorm.Session{NewDB: true}).Model(&PlaceAttribute{}).Select("place")
+	if attr.MinCapacity > 0 {
+		sub = sub.Where("capacity >= ?", attr.MinCapacity)
+	}
+	if attr.Projector {
+		sub = sub.Where("projector = ?", true)
+	}
+	if attr.Whiteboard {
+		sub = sub.Where("whiteboard = ?", true)
+	}
+	if attr.PowerOutlets {
+		sub = sub.Where("power_outlets = ?", true)
+	}
+	if attr.Accessible {
+		sub = sub.Where("accessible = ?", true)
+	}
+	return db.Where("place IN (?)", sub)
+}
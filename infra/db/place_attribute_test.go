@@ -0,0 +1,59 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+func Test_filterRoomAttribute(t *testing.T) {
+	r, assert, require := setupRepo(t, common)
+
+	room, _ := mustMakeRoom(t, r, "attribute")
+	_, err := upsertPlaceAttribute(r.db, domain.PlaceAttribute{
+		Place:     room.Place,
+		Capacity:  30,
+		Projector: true,
+	})
+	require.NoError(err)
+
+	t.Run("update attribute", func(_ *testing.T) {
+		_, err := upsertPlaceAttribute(r.db, domain.PlaceAttribute{
+			Place:     room.Place,
+			Capacity:  40,
+			Projector: true,
+		})
+		require.NoError(err)
+		ro, err := getRoom(r.db.Preload("Attribute"), room.ID)
+		require.NoError(err)
+		require.NotNil(ro.Attribute)
+		assert.Equal(40, ro.Attribute.Capacity)
+	})
+
+	tests := []struct {
+		name  string
+		attr  domain.RoomAttributeFilter
+		found bool
+	}{
+		{"no filter", domain.RoomAttributeFilter{}, true},
+		{"capacity", domain.RoomAttributeFilter{MinCapacity: 40, Projector: true}, true},
+		{"too many", domain.RoomAttributeFilter{MinCapacity: 41}, false},
+		{"whiteboard", domain.RoomAttributeFilter{Whiteboard: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(_ *testing.T) {
+			rooms, err := getAllRooms(filterRoomAttribute(r.db, tt.attr), room.TimeStart, room.TimeEnd, false)
+			require.NoError(err)
+			ids := make([]uuid.UUID, len(rooms))
+			for i := range rooms {
+				ids[i] = rooms[i].ID
+			}
+			if tt.found {
+				assert.Contains(ids, room.ID)
+			} else {
+				assert.NotContains(ids, room.ID)
+			}
+		})
+	}
+}
@@ -2,19 +2,23 @@ package db
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/samber/lo"
 	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/tz"
 	"gorm.io/gorm"
 )
 
 func roomExcludeEventPreload(tx *gorm.DB, excludeEventID uuid.UUID) *gorm.DB {
-	return tx.Preload("Events", "ID != ?", excludeEventID).Preload("Admins").Preload("CreatedBy")
+	return tx.Preload("Events", "ID != ?", excludeEventID).Preload("Attribute").Preload("Admins").Preload("CreatedBy")
 }
 
 func roomFullPreload(tx *gorm.DB) *gorm.DB {
-	return tx.Preload("Events").Preload("Admins").Preload("CreatedBy")
+	return tx.Preload("Events").Preload("Attribute").Preload("Admins").Preload("CreatedBy")
 }
 
 func (repo *gormRepository) CreateRoom(ctx context.Context, args domain.CreateRoomArgs) (*domain.Room, error) {
@@ -55,22 +59,54 @@ func (repo *gormRepository) GetRoom(ctx context.Context, roomID uuid.UUID, exclu
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
+	if err := attachRoomBlackouts(tx, room); err != nil {
+		return nil, defaultErrorHandling(err)
+	}
 	r := ConvRoomTodomainRoom(*room)
 	return &r, nil
 }
 
-func (repo *gormRepository) GetAllRooms(ctx context.Context, start, end time.Time, excludeEventID uuid.UUID, onlyVerified bool) ([]*domain.Room, error) {
+func (repo *gormRepository) GetAllRooms(ctx context.Context, start, end time.Time, excludeEventID uuid.UUID, onlyVerified bool, attr domain.RoomAttributeFilter) ([]*domain.Room, error) {
 	var rooms []*Room
 	var err error
 	tx := getTx(ctx, repo.db.WithContext(ctx))
 	if excludeEventID == uuid.Nil {
-		rooms, err = getAllRooms(roomFullPreload(tx), start, end, onlyVerified)
+		rooms, err = getAllRooms(filterRoomAttribute(roomFullPreload(tx), attr), start, end, onlyVerified)
 	} else {
-		rooms, err = getAllRooms(roomExcludeEventPreload(tx, excludeEventID), start, end, onlyVerified)
+		rooms, err = getAllRooms(filterRoomAttribute(roomExcludeEventPreload(tx, excludeEventID), attr), start, end, onlyVerified)
+	}
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	if err := attachRoomBlackouts(tx, rooms...); err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	r := ConvSPRoomToSPdomainRoom(rooms)
+	return r, nil
+}
+
+func (repo *gormRepository) GetRoomsByPlace(ctx context.Context, place string, start, end time.Time) ([]*domain.Room, error) {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	rooms, err := getAllRooms(roomFullPreload(tx).Where("place = ?", place), start, end, true)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
 	}
+	if err := attachRoomBlackouts(tx, rooms...); err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	r := ConvSPRoomToSPdomainRoom(rooms)
+	return r, nil
+}
+
+func (repo *gormRepository) GetOverlappingRooms(ctx context.Context, start, end time.Time) ([]*domain.Room, error) {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	rooms, err := getOverlappingRooms(roomFullPreload(tx), start, end)
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
+	if err := attachRoomBlackouts(tx, rooms...); err != nil {
+		return nil, defaultErrorHandling(err)
+	}
 	r := ConvSPRoomToSPdomainRoom(rooms)
 	return r, nil
 }
@@ -129,7 +165,7 @@ func updateRoom(db *gorm.DB, roomID uuid.UUID, args domain.UpdateRoomArgs) (*Roo
 	}
 	// Room を更新
 	// 時間整合性は service で確認済み
-	err = db.Omit("verified", "CreatedAt").Save(&room).Error
+	err = db.Omit("verified", "series_id", "CreatedAt").Save(&room).Error
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +179,16 @@ func updateRoom(db *gorm.DB, roomID uuid.UUID, args domain.UpdateRoomArgs) (*Roo
 	if err != nil {
 		return nil, err
 	}
+	// 場所が変わると場所ごとの使えない時間帯との重なりも変わる
+	var eventIDs []uuid.UUID
+	err = db.Model(&Event{}).Where("room_id = ?", room.ID).Pluck("id", &eventIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	err = refreshEventBlackoutConflicts(db, eventIDs...)
+	if err != nil {
+		return nil, err
+	}
 	return &room, err
 }
 
@@ -179,3 +225,199 @@ func getAllRooms(db *gorm.DB, start, end time.Time, onlyVerified bool) ([]*Room,
 	err := db.Debug().Order("time_start").Find(&rooms).Error
 	return rooms, err
 }
+
+// getOverlappingRooms getAllRooms と違い、期間をまたぐ部屋も含める
+func getOverlappingRooms(db *gorm.DB, start, end time.Time) ([]*Room, error) {
+	rooms := make([]*Room, 0)
+	err := db.Where("time_start < ? AND time_end > ?", end, start).
+		Where("verified = ?", true).
+		Order("time_start").Find(&rooms).Error
+	return rooms, err
+}
+
+func (repo *gormRepository) GetRoomStats(ctx context.Context, since, until time.Time, groupBy domain.RoomStatsGroupBy, onlyVerified bool) ([]*domain.RoomStats, error) {
+	rows, err := getRoomStats(getTx(ctx, repo.db.WithContext(ctx)), since, until, groupBy, onlyVerified)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	stats := make([]*domain.RoomStats, len(rows))
+	for i, row := range rows {
+		stats[i] = &domain.RoomStats{
+			Key:              row.Key,
+			RoomCount:        row.RoomCount,
+			ReservedTime:     time.Duration(row.ReservedSeconds) * time.Second,
+			UsedTime:         time.Duration(row.UsedSeconds) * time.Second,
+			EventCount:       row.EventCount,
+			SharedTime:       time.Duration(row.SharedSeconds) * time.Second,
+			SharedEventCount: row.SharedEventCount,
+		}
+	}
+	return stats, nil
+}
+
+type roomStatsRow struct {
+	Key              string
+	TimeStart        time.Time
+	RoomCount        int
+	ReservedSeconds  int64
+	UsedSeconds      int64
+	EventCount       int
+	SharedSeconds    int64
+	SharedEventCount int
+}
+
+// roomEventCoverageSQL 部屋ごとに、確保時間内でイベントが入っていた時間を求める
+// イベント同士が重なる部分は一度だけ数える。
+// 開始時刻順に並べ、それより前のイベントの終了時刻の最大値を超えた部分だけを足し合わせる。
+func roomEventCoverageSQL(onlyShared bool) string {
+	cond := ""
+	if onlyShared {
+		cond = " AND events.allow_together = TRUE"
+	}
+	return `
+SELECT room_id, COUNT(*) AS event_count,
+	SUM(GREATEST(0, TIMESTAMPDIFF(SECOND, GREATEST(clipped_start, COALESCE(prev_end, clipped_start)), clipped_end))) AS covered_seconds
+FROM (
+	SELECT events.room_id,
+		GREATEST(events.time_start, rooms.time_start) AS clipped_start,
+		LEAST(events.time_end, rooms.time_end) AS clipped_end,
+		MAX(LEAST(events.time_end, rooms.time_end)) OVER (
+			PARTITION BY events.room_id
+			ORDER BY GREATEST(events.time_start, rooms.time_start), LEAST(events.time_end, rooms.time_end)
+			ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+		) AS prev_end
+	FROM events
+	JOIN rooms ON rooms.id = events.room_id
+	WHERE events.deleted_at IS NULL
+		AND events.time_start < rooms.time_end AND events.time_end > rooms.time_start` + cond + `
+) AS clipped
+GROUP BY room_id`
+}
+
+const clippedEventSecondsSQL = "TIMESTAMPDIFF(SECOND, GREATEST(events.time_start, rooms.time_start), LEAST(events.time_end, rooms.time_end))"
+
+func getRoomStats(db *gorm.DB, since, until time.Time, groupBy domain.RoomStatsGroupBy, onlyVerified bool) ([]*roomStatsRow, error) {
+	conds := []string{"rooms.deleted_at IS NULL"}
+	args := make([]interface{}, 0, 2)
+	if !since.IsZero() {
+		conds = append(conds, "rooms.time_start >= ?")
+		args = append(args, since)
+	}
+	if !until.IsZero() {
+		conds = append(conds, "rooms.time_end <= ?")
+		args = append(args, until)
+	}
+	if onlyVerified {
+		conds = append(conds, "rooms.verified = TRUE")
+	}
+	where := strings.Join(conds, " AND ")
+
+	var query string
+	switch groupBy {
+	case domain.RoomStatsGroupByPlace, domain.RoomStatsGroupByWeek:
+		key := "rooms.place"
+		if groupBy == domain.RoomStatsGroupByWeek {
+			// DB のタイムゾーンに依らないように、部屋ごとに集計してから JST の週でまとめる
+			key = "rooms.id"
+		}
+		query = `
+SELECT ` + key + ` AS ` + "`key`" + `,
+	MIN(rooms.time_start) AS time_start,
+	COUNT(*) AS room_count,
+	SUM(TIMESTAMPDIFF(SECOND, rooms.time_start, rooms.time_end)) AS reserved_seconds,
+	COALESCE(SUM(used.covered_seconds), 0) AS used_seconds,
+	COALESCE(SUM(used.event_count), 0) AS event_count,
+	COALESCE(SUM(shared.covered_seconds), 0) AS shared_seconds,
+	COALESCE(SUM(shared.event_count), 0) AS shared_event_count
+FROM rooms
+LEFT JOIN (` + roomEventCoverageSQL(false) + `) AS used ON used.room_id = rooms.id
+LEFT JOIN (` + roomEventCoverageSQL(true) + `) AS shared ON shared.room_id = rooms.id
+WHERE ` + where + `
+GROUP BY ` + "`key`" + `
+ORDER BY ` + "`key`"
+	case domain.RoomStatsGroupByGroup:
+		// 同じグループのイベントの重なりは考慮しない
+		query = `
+SELECT events.group_id AS ` + "`key`" + `,
+	COUNT(DISTINCT events.room_id) AS room_count,
+	0 AS reserved_seconds,
+	SUM(` + clippedEventSecondsSQL + `) AS used_seconds,
+	COUNT(*) AS event_count,
+	SUM(CASE WHEN events.allow_together THEN ` + clippedEventSecondsSQL + ` ELSE 0 END) AS shared_seconds,
+	SUM(CASE WHEN events.allow_together THEN 1 ELSE 0 END) AS shared_event_count
+FROM events
+JOIN rooms ON rooms.id = events.room_id
+WHERE events.deleted_at IS NULL
+	AND events.time_start < rooms.time_end AND events.time_end > rooms.time_start
+	AND ` + where + `
+GROUP BY events.group_id
+ORDER BY ` + "`key`"
+	default:
+		return nil, NewValueError(ErrInvalidArgs, "groupBy")
+	}
+
+	rows := make([]*roomStatsRow, 0)
+	err := db.Raw(query, args...).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if groupBy == domain.RoomStatsGroupByWeek {
+		rows = groupRoomStatsByWeek(rows)
+	}
+	return rows, nil
+}
+
+// groupRoomStatsByWeek 部屋ごとの集計を JST の週 (月曜日の日付) ごとにまとめる
+func groupRoomStatsByWeek(rows []*roomStatsRow) []*roomStatsRow {
+	weeks := make(map[string]*roomStatsRow)
+	for _, row := range rows {
+		start := row.TimeStart.In(tz.JST)
+		monday := time.Date(start.Year(), start.Month(), start.Day()-(int(start.Weekday())+6)%7, 0, 0, 0, 0, tz.JST)
+		key := monday.Format("2006-01-02")
+		week, ok := weeks[key]
+		if !ok {
+			week = &roomStatsRow{Key: key, TimeStart: monday}
+			weeks[key] = week
+		}
+		week.RoomCount += row.RoomCount
+		week.ReservedSeconds += row.ReservedSeconds
+		week.UsedSeconds += row.UsedSeconds
+		week.EventCount += row.EventCount
+		week.SharedSeconds += row.SharedSeconds
+		week.SharedEventCount += row.SharedEventCount
+	}
+	result := lo.Values(weeks)
+	slices.SortFunc(result, func(a, b *roomStatsRow) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+func (repo *gormRepository) UpdateRoomAdmins(ctx context.Context, roomID uuid.UUID, admins []uuid.UUID, replace bool) error {
+	err := updateRoomAdmins(getTx(ctx, repo.db.WithContext(ctx)), roomID, admins, replace)
+	return defaultErrorHandling(err)
+}
+
+func updateRoomAdmins(db *gorm.DB, roomID uuid.UUID, admins []uuid.UUID, replace bool) error {
+	room, err := getRoom(db, roomID)
+	if err != nil {
+		return err
+	}
+	if replace {
+		err = db.Where("room_id = ?", room.ID).Delete(&RoomAdmin{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	for _, userID := range admins {
+		admin := RoomAdmin{
+			UserID: userID,
+			RoomID: room.ID,
+		}
+		err = db.Save(&admin).Error
+		if err != nil {
+			return err
+		}
+	}
+	return validateRoom(db, room)
+}
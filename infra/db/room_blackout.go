@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/samber/lo"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+// CreateRoomBlackout 重なるイベントの BlackoutConflict も更新する
+func (repo *gormRepository) CreateRoomBlackout(ctx context.Context, args domain.CreateRoomBlackoutArgs) (*domain.RoomBlackout, error) {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	var blackout *RoomBlackout
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		var err error
+		blackout, err = createRoomBlackout(tx, args)
+		if err != nil {
+			return err
+		}
+		return refreshRoomBlackoutEvents(tx, blackout)
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	b := ConvRoomBlackoutTodomainRoomBlackout(*blackout)
+	return &b, nil
+}
+
+// DeleteRoomBlackout 重なっていたイベントの BlackoutConflict も更新する
+func (repo *gormRepository) DeleteRoomBlackout(ctx context.Context, blackoutID uuid.UUID) error {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		blackout, err := getRoomBlackout(tx, blackoutID)
+		if err != nil {
+			return err
+		}
+		if err := deleteRoomBlackout(tx, blackoutID); err != nil {
+			return err
+		}
+		return refreshRoomBlackoutEvents(tx, blackout)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) GetRoomBlackouts(ctx context.Context, start, end time.Time) ([]*domain.RoomBlackout, error) {
+	blackouts, err := getRoomBlackouts(getTx(ctx, repo.db.WithContext(ctx)).Preload("CreatedBy"), start, end)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPRoomBlackoutToSPdomainRoomBlackout(blackouts), nil
+}
+
+func (repo *gormRepository) GetEventsInRoomBlackout(ctx context.Context, blackoutID uuid.UUID) ([]*domain.Event, error) {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	blackout, err := getRoomBlackout(tx, blackoutID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	events, err := getEventsInRoomBlackout(eventFullPreload(tx), blackout)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPEventToSPdomainEvent(events), nil
+}
+
+func convCreateRoomBlackoutArgsToRoomBlackout(src domain.CreateRoomBlackoutArgs) (dst RoomBlackout) {
+	dst.CreatedByRefer = src.CreatedBy
+	dst.RoomID = src.RoomID
+	dst.Place = src.Place
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Reason = src.Reason
+	return
+}
+
+func createRoomBlackout(db *gorm.DB, args domain.CreateRoomBlackoutArgs) (*RoomBlackout, error) {
+	blackout := convCreateRoomBlackoutArgsToRoomBlackout(args)
+	var err error
+	blackout.ID, err = uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	if blackout.RoomID != uuid.Nil {
+		room, err := getRoom(db, blackout.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		blackout.Place = room.Place
+	}
+	err = db.Create(&blackout).Error
+	return &blackout, err
+}
+
+func deleteRoomBlackout(db *gorm.DB, blackoutID uuid.UUID) error {
+	blackout := RoomBlackout{
+		ID: blackoutID,
+	}
+	result := db.Delete(&blackout)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewValueError(gorm.ErrRecordNotFound, "blackoutID")
+	}
+	return nil
+}
+
+// refreshRoomBlackoutEvents blackout と重なるイベントの BlackoutConflict を更新する
+func refreshRoomBlackoutEvents(db *gorm.DB, blackout *RoomBlackout) error {
+	events, err := getEventsInRoomBlackout(db, blackout)
+	if err != nil {
+		return err
+	}
+	return refreshEventBlackoutConflicts(db, lo.Map(events, func(e *Event, _ int) uuid.UUID {
+		return e.ID
+	})...)
+}
+
+// refreshEventBlackoutConflicts イベントが使えない時間帯と重なっているかを記録し直す
+func refreshEventBlackoutConflicts(db *gorm.DB, eventIDs ...uuid.UUID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	return db.Exec(`
+UPDATE events JOIN rooms ON rooms.id = events.room_id
+SET events.blackout_conflict = EXISTS (
+	SELECT 1 FROM room_blackouts
+	WHERE room_blackouts.deleted_at IS NULL
+		AND room_blackouts.time_start < events.time_end AND room_blackouts.time_end > events.time_start
+		AND (room_blackouts.room_id = events.room_id OR (room_blackouts.room_id = ? AND room_blackouts.place = rooms.place))
+)
+WHERE events.id IN ?`, uuid.Nil, eventIDs).Error
+}
+
+func getRoomBlackout(db *gorm.DB, blackoutID uuid.UUID) (*RoomBlackout, error) {
+	blackout := RoomBlackout{}
+	err := db.Take(&blackout, blackoutID).Error
+	return &blackout, err
+}
+
+// getRoomBlackouts 期間に重なる時間帯を取得する
+func getRoomBlackouts(db *gorm.DB, start, end time.Time) ([]*RoomBlackout, error) {
+	blackouts := make([]*RoomBlackout, 0)
+	if !start.IsZero() {
+		db = db.Where("time_end > ?", start)
+	}
+	if !end.IsZero() {
+		db = db.Where("time_start < ?", end)
+	}
+	err := db.Order("time_start").Find(&blackouts).Error
+	return blackouts, err
+}
+
+func getEventsInRoomBlackout(db *gorm.DB, blackout *RoomBlackout) ([]*Event, error) {
+	events := make([]*Event, 0)
+	db = db.Joins("JOIN rooms ON rooms.id = events.room_id AND rooms.deleted_at IS NULL").
+		Where("events.time_start < ? AND events.time_end > ?", blackout.TimeEnd, blackout.TimeStart)
+	if blackout.RoomID != uuid.Nil {
+		db = db.Where("events.room_id = ?", blackout.RoomID)
+	} else {
+		db = db.Where("rooms.place = ?", blackout.Place)
+	}
+	err := db.Order("events.time_start").Find(&events).Error
+	return events, err
+}
+
+// attachRoomBlackouts 部屋に重なる使えない時間帯を Room.Blackouts に入れる
+func attachRoomBlackouts(db *gorm.DB, rooms ...*Room) error {
+	if len(rooms) == 0 {
+		return nil
+	}
+	roomIDs := make([]uuid.UUID, 0, len(rooms))
+	places := make([]string, 0, len(rooms))
+	start, end := rooms[0].TimeStart, rooms[0].TimeEnd
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+		places = append(places, room.Place)
+		if room.TimeStart.Before(start) {
+			start = room.TimeStart
+		}
+		if room.TimeEnd.After(end) {
+			end = room.TimeEnd
+		}
+	}
+
+	blackouts, err := getRoomBlackouts(
+		db.Where("(room_id IN ? OR (room_id = ? AND place IN ?))", roomIDs, uuid.Nil, places),
+		start, end)
+	if err != nil {
+		return err
+	}
+
+	for _, room := range rooms {
+		room.Blackouts = make([]RoomBlackout, 0)
+		for _, b := range blackouts {
+			if b.RoomID != room.ID && (b.RoomID != uuid.Nil || b.Place != room.Place) {
+				continue
+			}
+			if !b.TimeStart.Before(room.TimeEnd) || !room.TimeStart.Before(b.TimeEnd) {
+				continue
+			}
+			room.Blackouts = append(room.Blackouts, *b)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func Test_createRoomBlackout(t *testing.T) {
+	r, assert, require, user, room := setupRepoWithUserRoom(t, common)
+
+	t.Run("create blackout of room", func(_ *testing.T) {
+		b, err := createRoomBlackout(r.db, domain.CreateRoomBlackoutArgs{
+			CreatedBy: user.ID,
+			WriteRoomBlackoutParams: domain.WriteRoomBlackoutParams{
+				RoomID:    room.ID,
+				TimeStart: room.TimeStart,
+				TimeEnd:   room.TimeStart.Add(10 * time.Minute),
+			},
+		})
+		require.NoError(err)
+		assert.Equal(room.Place, b.Place)
+	})
+
+	t.Run("create blackout of random roomID", func(t *testing.T) {
+		_, err := createRoomBlackout(r.db, domain.CreateRoomBlackoutArgs{
+			CreatedBy: user.ID,
+			WriteRoomBlackoutParams: domain.WriteRoomBlackoutParams{
+				RoomID:    mustNewUUIDV4(t),
+				TimeStart: room.TimeStart,
+				TimeEnd:   room.TimeEnd,
+			},
+		})
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func Test_getEventsInRoomBlackout(t *testing.T) {
+	r, assert, require, user, _, room, event := setupRepoWithUserGroupRoomEvent(t, common)
+
+	b, err := createRoomBlackout(r.db, domain.CreateRoomBlackoutArgs{
+		CreatedBy: user.ID,
+		WriteRoomBlackoutParams: domain.WriteRoomBlackoutParams{
+			Place:     room.Place,
+			TimeStart: event.TimeStart,
+			TimeEnd:   event.TimeEnd,
+			Reason:    "fire drill",
+		},
+	})
+	require.NoError(err)
+
+	t.Run("get events", func(_ *testing.T) {
+		events, err := getEventsInRoomBlackout(r.db, b)
+		require.NoError(err)
+		require.Len(events, 1)
+		assert.Equal(event.ID, events[0].ID)
+	})
+
+	t.Run("attach blackouts", func(_ *testing.T) {
+		ro, err := getRoom(r.db, room.ID)
+		require.NoError(err)
+		require.NoError(attachRoomBlackouts(r.db, ro))
+		require.Len(ro.Blackouts, 1)
+		assert.Equal(b.ID, ro.Blackouts[0].ID)
+		assert.Equal(uuid.Nil, ro.Blackouts[0].RoomID)
+	})
+}
+
+func Test_eventBlackoutConflict(t *testing.T) {
+	r, assert, require, user, _, room, event := setupRepoWithUserGroupRoomEvent(t, common)
+	ctx := t.Context()
+
+	b, err := r.CreateRoomBlackout(ctx, domain.CreateRoomBlackoutArgs{
+		CreatedBy: user.ID,
+		WriteRoomBlackoutParams: domain.WriteRoomBlackoutParams{
+			RoomID:    room.ID,
+			TimeStart: event.TimeStart,
+			TimeEnd:   event.TimeEnd,
+		},
+	})
+	require.NoError(err)
+
+	e, err := getEvent(r.db, event.ID)
+	require.NoError(err)
+	assert.True(e.BlackoutConflict)
+
+	require.NoError(r.DeleteRoomBlackout(ctx, b.ID))
+	e, err = getEvent(r.db, event.ID)
+	require.NoError(err)
+	assert.False(e.BlackoutConflict)
+
+	t.Run("change place of room", func(_ *testing.T) {
+		_, err := r.CreateRoomBlackout(ctx, domain.CreateRoomBlackoutArgs{
+			CreatedBy: user.ID,
+			WriteRoomBlackoutParams: domain.WriteRoomBlackoutParams{
+				Place:     "closed place",
+				TimeStart: event.TimeStart,
+				TimeEnd:   event.TimeEnd,
+			},
+		})
+		require.NoError(err)
+		e, err := getEvent(r.db, event.ID)
+		require.NoError(err)
+		assert.False(e.BlackoutConflict)
+
+		params := domain.UpdateRoomArgs{
+			CreatedBy: user.ID,
+			WriteRoomParams: domain.WriteRoomParams{
+				Place:     "closed place",
+				TimeStart: room.TimeStart,
+				TimeEnd:   room.TimeEnd,
+				Admins:    []uuid.UUID{user.ID},
+			},
+		}
+		_, err = r.UpdateRoom(ctx, room.ID, params)
+		require.NoError(err)
+		e, err = getEvent(r.db, event.ID)
+		require.NoError(err)
+		assert.True(e.BlackoutConflict)
+
+		params.Place = room.Place
+		_, err = r.UpdateRoom(ctx, room.ID, params)
+		require.NoError(err)
+		e, err = getEvent(r.db, event.ID)
+		require.NoError(err)
+		assert.False(e.BlackoutConflict)
+	})
+
+	t.Run("delete unknown blackout", func(t *testing.T) {
+		err := r.DeleteRoomBlackout(ctx, mustNewUUIDV4(t))
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
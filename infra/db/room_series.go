@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func roomSeriesFullPreload(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Exceptions").
+		Preload("Rooms", func(db *gorm.DB) *gorm.DB {
+			return db.Order("time_start")
+		}).
+		Preload("Rooms.Admins").Preload("CreatedBy")
+}
+
+func (repo *gormRepository) CreateRoomSeries(ctx context.Context, args domain.UpsertRoomSeriesArgs) (*domain.RoomSeries, error) {
+	series, err := createRoomSeries(getTx(ctx, repo.db.WithContext(ctx)), args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	s := ConvRoomSeriesTodomainRoomSeries(*series)
+	return &s, nil
+}
+
+func (repo *gormRepository) UpdateRoomSeries(ctx context.Context, seriesID uuid.UUID, args domain.UpsertRoomSeriesArgs) (*domain.RoomSeries, error) {
+	series, err := updateRoomSeries(getTx(ctx, repo.db.WithContext(ctx)), seriesID, args)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	s := ConvRoomSeriesTodomainRoomSeries(*series)
+	return &s, nil
+}
+
+func (repo *gormRepository) DeleteRoomSeries(ctx context.Context, seriesID uuid.UUID) error {
+	err := deleteRoomSeries(getTx(ctx, repo.db.WithContext(ctx)), seriesID)
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) GetRoomSeries(ctx context.Context, seriesID uuid.UUID) (*domain.RoomSeries, error) {
+	series, err := getRoomSeries(roomSeriesFullPreload(getTx(ctx, repo.db.WithContext(ctx))), seriesID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	s := ConvRoomSeriesTodomainRoomSeries(*series)
+	return &s, nil
+}
+
+func (repo *gormRepository) GetAllRoomSeries(ctx context.Context) ([]*domain.RoomSeries, error) {
+	series, err := getAllRoomSeries(getTx(ctx, repo.db.WithContext(ctx)).Preload("Exceptions").Preload("CreatedBy"))
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return ConvSPRoomSeriesToSPdomainRoomSeries(series), nil
+}
+
+func createRoomSeries(db *gorm.DB, args domain.UpsertRoomSeriesArgs) (*RoomSeries, error) {
+	series := ConvUpsertRoomSeriesArgsToRoomSeries(args)
+	var err error
+	series.ID, err = uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	for i := range series.Exceptions {
+		series.Exceptions[i].SeriesID = series.ID
+	}
+	err = db.Create(&series).Error
+	return &series, err
+}
+
+func updateRoomSeries(db *gorm.DB, seriesID uuid.UUID, args domain.UpsertRoomSeriesArgs) (*RoomSeries, error) {
+	// Save は存在しない ID でも作成してしまうため確認する
+	_, err := getRoomSeries(db, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	series := ConvUpsertRoomSeriesArgsToRoomSeries(args)
+	series.ID = seriesID
+	for i := range series.Exceptions {
+		series.Exceptions[i].SeriesID = series.ID
+	}
+
+	err = db.Where("series_id = ?", seriesID).Delete(&RoomSeriesException{}).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Omit("CreatedAt").Save(&series).Error
+	return &series, err
+}
+
+func deleteRoomSeries(db *gorm.DB, seriesID uuid.UUID) error {
+	series := RoomSeries{
+		ID: seriesID,
+	}
+	return db.Delete(&series).Error
+}
+
+func getRoomSeries(db *gorm.DB, seriesID uuid.UUID) (*RoomSeries, error) {
+	series := RoomSeries{}
+	err := db.Take(&series, seriesID).Error
+	return &series, err
+}
+
+func getAllRoomSeries(db *gorm.DB) ([]*RoomSeries, error) {
+	series := make([]*RoomSeries, 0)
+	err := db.Order("time_start").Find(&series).Error
+	return series, err
+}
+
+// Weekdays は time.Weekday ごとのビットで保存する
+func weekdaysToBits(weekdays []time.Weekday) (bits int) {
+	for _, w := range weekdays {
+		bits |= 1 << uint(w)
+	}
+	return
+}
+
+func bitsToWeekdays(bits int) []time.Weekday {
+	weekdays := make([]time.Weekday, 0, 7)
+	for w := time.Sunday; w <= time.Saturday; w++ {
+		if bits&(1<<uint(w)) != 0 {
+			weekdays = append(weekdays, w)
+		}
+	}
+	return weekdays
+}
+
+// TODO: Weekdays の変換のため手動で書いている
+func ConvUpsertRoomSeriesArgsToRoomSeries(src domain.UpsertRoomSeriesArgs) (dst RoomSeries) {
+	dst.Place = src.Place
+	dst.Weekdays = weekdaysToBits(src.Weekdays)
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Until = src.Until
+	dst.Exceptions = make([]RoomSeriesException, len(src.Exceptions))
+	for i := range src.Exceptions {
+		dst.Exceptions[i].Date = src.Exceptions[i]
+	}
+	dst.CreatedByRefer = src.CreatedBy
+	return
+}
+
+func ConvRoomSeriesTodomainRoomSeries(src RoomSeries) (dst domain.RoomSeries) {
+	dst.ID = src.ID
+	dst.Place = src.Place
+	dst.Weekdays = bitsToWeekdays(src.Weekdays)
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Until = src.Until
+	dst.Exceptions = make([]time.Time, len(src.Exceptions))
+	for i := range src.Exceptions {
+		dst.Exceptions[i] = src.Exceptions[i].Date
+	}
+	dst.Rooms = make([]domain.Room, len(src.Rooms))
+	for i := range src.Rooms {
+		dst.Rooms[i] = convRoomTodomainRoom(src.Rooms[i])
+	}
+	dst.CreatedBy = convUserTodomainUser(src.CreatedBy)
+	dst.CreatedAt = src.CreatedAt
+	dst.UpdatedAt = src.UpdatedAt
+	dst.DeletedAt = new(time.Time)
+	(*dst.DeletedAt) = convgormDeletedAtTotimeTime(src.DeletedAt)
+	return
+}
+
+func ConvSPRoomSeriesToSPdomainRoomSeries(src []*RoomSeries) (dst []*domain.RoomSeries) {
+	dst = make([]*domain.RoomSeries, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst[i] = new(domain.RoomSeries)
+			(*dst[i]) = ConvRoomSeriesTodomainRoomSeries(*src[i])
+		}
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/tz"
+	"gorm.io/gorm"
+)
+
+func Test_updateRoomSeries(t *testing.T) {
+	r, assert, require, user := setupRepoWithUser(t, common)
+
+	start := time.Date(2024, 4, 2, 18, 0, 0, 0, tz.JST)
+	params := domain.WriteRoomSeriesParams{
+		Place:      "room series",
+		Weekdays:   []time.Weekday{time.Tuesday},
+		TimeStart:  start,
+		TimeEnd:    start.Add(2 * time.Hour),
+		Until:      time.Date(2024, 4, 30, 0, 0, 0, 0, tz.JST),
+		Exceptions: []time.Time{time.Date(2024, 4, 9, 0, 0, 0, 0, tz.JST)},
+	}
+	s, err := createRoomSeries(r.db, domain.UpsertRoomSeriesArgs{
+		WriteRoomSeriesParams: params,
+		CreatedBy:             user.ID,
+	})
+	require.NoError(err)
+
+	t.Run("update weekdays and exceptions", func(_ *testing.T) {
+		params.Weekdays = []time.Weekday{time.Tuesday, time.Thursday}
+		params.Exceptions = []time.Time{time.Date(2024, 4, 11, 0, 0, 0, 0, tz.JST)}
+		_, err := updateRoomSeries(r.db, s.ID, domain.UpsertRoomSeriesArgs{
+			WriteRoomSeriesParams: params,
+			CreatedBy:             user.ID,
+		})
+		require.NoError(err)
+
+		got, err := getRoomSeries(r.db.Preload("Exceptions"), s.ID)
+		require.NoError(err)
+		assert.Equal([]time.Weekday{time.Tuesday, time.Thursday}, bitsToWeekdays(got.Weekdays))
+		require.Len(got.Exceptions, 1)
+		assert.Equal(11, got.Exceptions[0].Date.Day())
+	})
+
+	t.Run("update random seriesID", func(t *testing.T) {
+		_, err := updateRoomSeries(r.db, mustNewUUIDV4(t), domain.UpsertRoomSeriesArgs{
+			WriteRoomSeriesParams: params,
+			CreatedBy:             user.ID,
+		})
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
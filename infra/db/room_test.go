@@ -8,6 +8,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/jinzhu/copier"
 	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/tz"
 )
 
 func Test_createRoom(t *testing.T) {
@@ -76,3 +77,137 @@ func Test_updateRoom(t *testing.T) {
 		assert.Equal(uint16(1452), me.Number)
 	})
 }
+
+func Test_getRoomStats(t *testing.T) {
+	r, assert, require, user := setupRepoWithUser(t, common)
+
+	start := time.Date(2021, 4, 7, 10, 0, 0, 0, time.Local)
+	place := "stats room"
+	room, err := createRoom(r.db, domain.CreateRoomArgs{
+		CreatedBy: user.ID,
+		Verified:  true,
+		WriteRoomParams: domain.WriteRoomParams{
+			Place:     place,
+			TimeStart: start,
+			TimeEnd:   start.Add(3 * time.Hour),
+			Admins:    []uuid.UUID{user.ID},
+		},
+	})
+	require.NoError(err)
+
+	groupID := mustNewUUIDV4(t)
+	for _, e := range []struct {
+		start, end    time.Duration
+		allowTogether bool
+	}{
+		{0, time.Hour, false},
+		{30 * time.Minute, 90 * time.Minute, true},
+		// 部屋の確保時間からはみ出す部分は数えない
+		{150 * time.Minute, 4 * time.Hour, true},
+	} {
+		_, err := createEvent(r.db, domain.UpsertEventArgs{
+			CreatedBy: user.ID,
+			WriteEventParams: domain.WriteEventParams{
+				Name:          "stats event",
+				GroupID:       groupID,
+				RoomID:        room.ID,
+				TimeStart:     start.Add(e.start),
+				TimeEnd:       start.Add(e.end),
+				AllowTogether: e.allowTogether,
+				Admins:        []uuid.UUID{user.ID},
+			},
+		})
+		require.NoError(err)
+	}
+
+	since := start.Add(-time.Hour)
+	until := start.Add(4 * time.Hour)
+
+	t.Run("group by place", func(_ *testing.T) {
+		rows, err := getRoomStats(r.db, since, until, domain.RoomStatsGroupByPlace, true)
+		require.NoError(err)
+		require.Len(rows, 1)
+		assert.Equal(place, rows[0].Key)
+		assert.Equal(1, rows[0].RoomCount)
+		assert.Equal(int64((3 * time.Hour).Seconds()), rows[0].ReservedSeconds)
+		assert.Equal(int64((2 * time.Hour).Seconds()), rows[0].UsedSeconds)
+		assert.Equal(3, rows[0].EventCount)
+		assert.Equal(int64((90 * time.Minute).Seconds()), rows[0].SharedSeconds)
+		assert.Equal(2, rows[0].SharedEventCount)
+	})
+
+	t.Run("group by week", func(_ *testing.T) {
+		rows, err := getRoomStats(r.db, since, until, domain.RoomStatsGroupByWeek, true)
+		require.NoError(err)
+		require.Len(rows, 1)
+		assert.Equal("2021-04-05", rows[0].Key)
+	})
+
+	t.Run("group by week in JST", func(_ *testing.T) {
+		rows := groupRoomStatsByWeek([]*roomStatsRow{
+			// 月曜日 00:30 JST は UTC では日曜日
+			{TimeStart: time.Date(2021, 4, 5, 0, 30, 0, 0, tz.JST), RoomCount: 1, ReservedSeconds: 60},
+			{TimeStart: time.Date(2021, 4, 11, 23, 0, 0, 0, tz.JST), RoomCount: 1, ReservedSeconds: 60},
+			{TimeStart: time.Date(2021, 4, 4, 23, 0, 0, 0, tz.JST), RoomCount: 1, ReservedSeconds: 60},
+		})
+		require.Len(rows, 2)
+		assert.Equal("2021-03-29", rows[0].Key)
+		assert.Equal("2021-04-05", rows[1].Key)
+		assert.Equal(2, rows[1].RoomCount)
+		assert.Equal(int64(120), rows[1].ReservedSeconds)
+	})
+
+	t.Run("group by group", func(_ *testing.T) {
+		rows, err := getRoomStats(r.db, since, until, domain.RoomStatsGroupByGroup, true)
+		require.NoError(err)
+		require.Len(rows, 1)
+		assert.Equal(groupID.String(), rows[0].Key)
+		assert.Equal(int64(0), rows[0].ReservedSeconds)
+		assert.Equal(int64((150 * time.Minute).Seconds()), rows[0].UsedSeconds)
+	})
+
+	t.Run("invalid groupBy", func(_ *testing.T) {
+		_, err := getRoomStats(r.db, since, until, domain.RoomStatsGroupBy(-1), true)
+		assert.ErrorIs(err, ErrInvalidArgs)
+	})
+}
+
+func Test_getOverlappingRooms(t *testing.T) {
+	r, assert, require, user := setupRepoWithUser(t, common)
+
+	day := time.Date(2031, 4, 2, 0, 0, 0, 0, tz.JST)
+	room, err := createRoom(r.db, domain.CreateRoomArgs{
+		CreatedBy: user.ID,
+		Verified:  true,
+		WriteRoomParams: domain.WriteRoomParams{
+			Place:     "overlapping room",
+			TimeStart: day.Add(-2 * time.Hour),
+			TimeEnd:   day.Add(2 * time.Hour),
+			Admins:    []uuid.UUID{user.ID},
+		},
+	})
+	require.NoError(err)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"crosses start", day, day.AddDate(0, 0, 1), true},
+		{"crosses end", day.AddDate(0, 0, -1), day, true},
+		{"touches end", day.Add(2 * time.Hour), day.AddDate(0, 0, 1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(_ *testing.T) {
+			rooms, err := getOverlappingRooms(r.db, tt.start, tt.end)
+			require.NoError(err)
+			found := false
+			for _, got := range rooms {
+				if got.ID == room.ID {
+					found = true
+				}
+			}
+			assert.Equal(tt.want, found)
+		})
+	}
+}
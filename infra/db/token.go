@@ -5,6 +5,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"golang.org/x/oauth2"
@@ -15,6 +16,33 @@ func (repo *gormRepository) GetToken(ctx context.Context, userID uuid.UUID) (*oa
 	return getToken(getTx(ctx, repo.db.WithContext(ctx)), userID)
 }
 
+func (repo *gormRepository) UpdateToken(ctx context.Context, userID uuid.UUID, token *oauth2.Token) error {
+	err := saveToken(getTx(ctx, repo.db.WithContext(ctx)), &Token{
+		UserID: userID,
+		Oauth2Token: &Oauth2Token{
+			AccessToken:  token.AccessToken,
+			TokenType:    token.TokenType,
+			RefreshToken: token.RefreshToken,
+			Expiry:       token.Expiry,
+		},
+	})
+	return defaultErrorHandling(err)
+}
+
+func (repo *gormRepository) GetExpiringTokenUserIDs(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	userIDs, err := getExpiringTokenUserIDs(getTx(ctx, repo.db.WithContext(ctx)), from, to)
+	return userIDs, defaultErrorHandling(err)
+}
+
+// getExpiringTokenUserIDs from から to の間に期限が切れ、refresh token を持つトークンのユーザー
+func getExpiringTokenUserIDs(db *gorm.DB, from, to time.Time) ([]uuid.UUID, error) {
+	userIDs := make([]uuid.UUID, 0)
+	err := db.Model(&Token{}).
+		Where("expiry BETWEEN ? AND ? AND refresh_token <> ''", from, to).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
 func getToken(db *gorm.DB, userID uuid.UUID) (*oauth2.Token, error) {
 	token := Token{}
 	err := db.Take(&token, userID).Error
@@ -29,6 +57,12 @@ func getToken(db *gorm.DB, userID uuid.UUID) (*oauth2.Token, error) {
 			return nil, defaultErrorHandling(err)
 		}
 	}
+	if token.RefreshToken != "" {
+		token.RefreshToken, err = decryptByGCM(tokenKey, []byte(token.RefreshToken))
+		if err != nil {
+			return nil, defaultErrorHandling(err)
+		}
+	}
 	return &oauth2.Token{
 		AccessToken:  token.AccessToken,
 		TokenType:    token.TokenType,
@@ -90,5 +124,12 @@ func saveToken(db *gorm.DB, token *Token) error {
 			token.AccessToken = string(cipherText)
 		}
 	}
+	if token.RefreshToken != "" {
+		cipherText, err := encryptByGCM(tokenKey, token.RefreshToken)
+		if err != nil {
+			return err
+		}
+		token.RefreshToken = string(cipherText)
+	}
 	return db.Save(token).Error
 }
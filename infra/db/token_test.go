@@ -0,0 +1,69 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_saveToken(t *testing.T) {
+	r, assert, require := setupRepo(t, common)
+	user := mustMakeUser(t, r, false)
+	expiry := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+
+	t.Run("tokens are encrypted", func(_ *testing.T) {
+		err := saveToken(r.db, &Token{
+			UserID: user.ID,
+			Oauth2Token: &Oauth2Token{
+				AccessToken:  "new-access",
+				TokenType:    "Bearer",
+				RefreshToken: "new-refresh",
+				Expiry:       expiry,
+			},
+		})
+		require.NoError(err)
+
+		raw := Token{}
+		require.NoError(r.db.Take(&raw, user.ID).Error)
+		assert.NotEqual("new-access", raw.AccessToken)
+		assert.NotEqual("new-refresh", raw.RefreshToken)
+
+		token, err := getToken(r.db, user.ID)
+		require.NoError(err)
+		assert.Equal("new-access", token.AccessToken)
+		assert.Equal("new-refresh", token.RefreshToken)
+		assert.WithinDuration(expiry, token.Expiry, time.Second)
+	})
+
+	t.Run("long tokens", func(_ *testing.T) {
+		// OIDC の JWT など traQ より長いトークン
+		access := strings.Repeat("a", 1500)
+		refresh := strings.Repeat("r", 1500)
+		err := saveToken(r.db, &Token{
+			UserID: user.ID,
+			Oauth2Token: &Oauth2Token{
+				AccessToken:  access,
+				TokenType:    "Bearer",
+				RefreshToken: refresh,
+				Expiry:       expiry,
+			},
+		})
+		require.NoError(err)
+
+		token, err := getToken(r.db, user.ID)
+		require.NoError(err)
+		assert.Equal(access, token.AccessToken)
+		assert.Equal(refresh, token.RefreshToken)
+	})
+
+	t.Run("get expiring token users", func(_ *testing.T) {
+		now := time.Now()
+		userIDs, err := getExpiringTokenUserIDs(r.db, now, now.Add(30*time.Minute))
+		require.NoError(err)
+		assert.Contains(userIDs, user.ID)
+
+		userIDs, err = getExpiringTokenUserIDs(r.db, now, now.Add(time.Minute))
+		require.NoError(err)
+		assert.NotContains(userIDs, user.ID)
+	})
+}
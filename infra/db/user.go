@@ -11,7 +11,7 @@ import (
 )
 
 func userPreload(tx *gorm.DB) *gorm.DB {
-	return tx.Preload("Provider")
+	return tx.Preload("Provider").Preload("Profile")
 }
 
 // LoginUser のときのみ，プロバイダ，トークン情報が含まれる
@@ -34,6 +34,14 @@ func (repo *gormRepository) SaveUser(ctx context.Context, args domain.SaveUserAr
 			Subject: args.Subject,
 		},
 	}
+	if args.Profile != nil {
+		user.Profile = &UserProfile{
+			UserID:      args.UserID,
+			Name:        args.Profile.Name,
+			DisplayName: args.Profile.DisplayName,
+			Icon:        args.Profile.Icon,
+		}
+	}
 	u, err := saveUser(getTx(ctx, repo.db.WithContext(ctx)), &user)
 	if err != nil {
 		return nil, defaultErrorHandling(err)
@@ -53,18 +61,20 @@ func (repo *gormRepository) GetUser(ctx context.Context, userID uuid.UUID) (*dom
 	return &du, defaultErrorHandling(err)
 }
 
+func (repo *gormRepository) GetUserByProvider(ctx context.Context, issuer, subject string) (*domain.User, error) {
+	u, err := getUserByProvider(userPreload(getTx(ctx, repo.db.WithContext(ctx))), issuer, subject)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	du := convUserTodomainUser(*u)
+	return &du, nil
+}
+
 func (repo *gormRepository) GetAllUsers(ctx context.Context, onlyActive bool) ([]*domain.User, error) {
 	us, err := getAllUsers(userPreload(getTx(ctx, repo.db.WithContext(ctx))), onlyActive)
 	dus := lo.Map(us, func(u *User, _ int) *domain.User {
-		return &domain.User{
-			ID:         u.ID,
-			Privileged: u.Privilege,
-			State:      u.State,
-			Provider: &domain.Provider{
-				Issuer:  u.Provider.Issuer,
-				Subject: u.Provider.Subject,
-			},
-		}
+		du := convUserTodomainUser(*u)
+		return &du
 	})
 	return dus, defaultErrorHandling(err)
 }
@@ -116,7 +126,7 @@ func (repo *gormRepository) SyncUsers(ctx context.Context, args []domain.SyncUse
 }
 
 // saveUser user.IcalSecret == "" の時、値は更新されません。
-// また、user.Provider, user.Tokenは空の時、更新されません。
+// また、user.Provider, user.Tokenは空の時、user.Profile は nil の時、更新されません。
 // user.Privilegeは常に更新されません。
 func saveUser(db *gorm.DB, user *User) (*User, error) {
 	err := db.Transaction(func(tx *gorm.DB) error {
@@ -167,6 +177,12 @@ func saveUser(db *gorm.DB, user *User) (*User, error) {
 				return err
 			}
 		}
+		if user.Profile != nil {
+			err = tx.Save(user.Profile).Error
+			if err != nil {
+				return err
+			}
+		}
 		return tx.Updates(user).Error
 		// return tx.Session(&gorm.Session{FullSaveAssociations: true}).Updates(user).Error
 	})
@@ -184,6 +200,17 @@ func getUser(db *gorm.DB, userID uuid.UUID) (*User, error) {
 	return &user, err
 }
 
+func getUserByProvider(db *gorm.DB, issuer, subject string) (*User, error) {
+	provider := Provider{}
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Where("issuer = ? AND subject = ?", issuer, subject).
+		Take(&provider).Error
+	if err != nil {
+		return nil, err
+	}
+	return getUser(db, provider.UserID)
+}
+
 func getAllUsers(db *gorm.DB, onlyActive bool) ([]*User, error) {
 	users := make([]*User, 0)
 	if onlyActive {
@@ -212,3 +239,52 @@ func (repo *gormRepository) GetICalSecret(ctx context.Context, userID uuid.UUID)
 
 	return u.IcalSecret, nil
 }
+
+func (repo *gormRepository) TransferAdmins(ctx context.Context, fromID, toID uuid.UUID) (*domain.HandOverResult, error) {
+	tx := getTx(ctx, repo.db.WithContext(ctx))
+	var result domain.HandOverResult
+	err := tx.Transaction(func(tx *gorm.DB) (err error) {
+		result.RoomCount, err = transferRoomAdmins(tx, fromID, toID)
+		if err != nil {
+			return err
+		}
+		result.EventCount, err = transferEventAdmins(tx, fromID, toID)
+		return err
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return &result, nil
+}
+
+func transferRoomAdmins(db *gorm.DB, fromID, toID uuid.UUID) (int, error) {
+	var roomIDs []uuid.UUID
+	err := db.Model(&RoomAdmin{}).Where("user_id = ?", fromID).Pluck("room_id", &roomIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, roomID := range roomIDs {
+		err = db.Save(&RoomAdmin{UserID: toID, RoomID: roomID}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = db.Where("user_id = ?", fromID).Delete(&RoomAdmin{}).Error
+	return len(roomIDs), err
+}
+
+func transferEventAdmins(db *gorm.DB, fromID, toID uuid.UUID) (int, error) {
+	var eventIDs []uuid.UUID
+	err := db.Model(&EventAdmin{}).Where("user_id = ?", fromID).Pluck("event_id", &eventIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, eventID := range eventIDs {
+		err = db.Save(&EventAdmin{UserID: toID, EventID: eventID}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = db.Where("user_id = ?", fromID).Delete(&EventAdmin{}).Error
+	return len(eventIDs), err
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"testing"
+
+	"gorm.io/gorm"
 )
 
 func Test_saveUser(t *testing.T) {
@@ -84,6 +86,60 @@ func Test_saveUser(t *testing.T) {
 	})
 }
 
+func Test_saveGuestUser(t *testing.T) {
+	r, assert, require := setupRepo(t, common)
+	id := mustNewUUIDV4(t)
+	subject := id.String()
+
+	t.Run("save guest user with profile", func(_ *testing.T) {
+		_, err := saveUser(r.db, &User{
+			ID:    id,
+			State: 1,
+			Provider: Provider{
+				UserID:  id,
+				Issuer:  "google",
+				Subject: subject,
+			},
+			Profile: &UserProfile{
+				UserID:      id,
+				Name:        "guest@example.com",
+				DisplayName: "guest",
+			},
+		})
+		require.NoError(err)
+
+		u, err := getUserByProvider(userPreload(r.db), "google", subject)
+		require.NoError(err)
+		assert.Equal(id, u.ID)
+		require.NotNil(u.Profile)
+		assert.Equal("guest", u.Profile.DisplayName)
+	})
+
+	t.Run("update profile", func(_ *testing.T) {
+		_, err := saveUser(r.db, &User{
+			ID:    id,
+			State: 1,
+			Profile: &UserProfile{
+				UserID:      id,
+				Name:        "guest@example.com",
+				DisplayName: "new name",
+			},
+		})
+		require.NoError(err)
+
+		u, err := getUser(userPreload(r.db), id)
+		require.NoError(err)
+		require.NotNil(u.Profile)
+		assert.Equal("new name", u.Profile.DisplayName)
+		assert.Equal("google", u.Provider.Issuer)
+	})
+
+	t.Run("unknown subject", func(_ *testing.T) {
+		_, err := getUserByProvider(r.db, "google", mustNewUUIDV4(t).String())
+		assert.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
 func Test_getUser(t *testing.T) {
 	r, assert, _, user := setupRepoWithUser(t, common)
 
@@ -92,3 +148,32 @@ func Test_getUser(t *testing.T) {
 		assert.NoError(err)
 	})
 }
+
+func Test_transferAdmins(t *testing.T) {
+	r, assert, require := setupRepo(t, common)
+	event, _, _, user := mustMakeEvent(t, r, "transfer")
+	room, roomAdmin := mustMakeRoom(t, r, "transfer")
+	successor := mustMakeUser(t, r, false)
+
+	t.Run("transfer event admins", func(_ *testing.T) {
+		n, err := transferEventAdmins(r.db, user.ID, successor.ID)
+		require.NoError(err)
+		assert.Equal(1, n)
+
+		e, err := getEvent(r.db.Preload("Admins"), event.ID)
+		require.NoError(err)
+		require.Len(e.Admins, 1)
+		assert.Equal(successor.ID, e.Admins[0].UserID)
+	})
+
+	t.Run("transfer room admins", func(_ *testing.T) {
+		n, err := transferRoomAdmins(r.db, roomAdmin.ID, successor.ID)
+		require.NoError(err)
+		assert.Equal(1, n)
+
+		ro, err := getRoom(r.db.Preload("Admins"), room.ID)
+		require.NoError(err)
+		require.Len(ro.Admins, 1)
+		assert.Equal(successor.ID, ro.Admins[0].UserID)
+	})
+}
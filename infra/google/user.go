@@ -2,12 +2,47 @@ package google
 
 import (
 	"context"
+	"errors"
 
+	"github.com/traPtitech/knoQ/domain"
 	"golang.org/x/oauth2"
 	"google.golang.org/api/idtoken"
 )
 
+// IssuerName domain.Provider.Issuer に保存する値
+const IssuerName = "google"
+
 func (repo *Repository) GetUser(token *oauth2.Token) (*idtoken.Payload, error) {
-	idToken := token.Extra("id_token").(string)
-	return idtoken.Validate(context.TODO(), idToken, "")
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("id_token is not found")
+	}
+	return idtoken.Validate(context.TODO(), idToken, repo.Config.ClientID)
+}
+
+func (repo *Repository) Issuer() string {
+	return IssuerName
+}
+
+// Authenticate 名前は subject、表示名は Google のアカウント名にする
+// 名前は他のユーザーにも見えるのでメールアドレスは使わない
+func (repo *Repository) Authenticate(query, state string) (string, *domain.ProfileArgs, error) {
+	token, err := repo.GetOAuthToken(query, state)
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := repo.GetUser(token)
+	if err != nil {
+		return "", nil, err
+	}
+	name, _ := payload.Claims["name"].(string)
+	if name == "" {
+		name = payload.Subject
+	}
+	picture, _ := payload.Claims["picture"].(string)
+	return payload.Subject, &domain.ProfileArgs{
+		Name:        payload.Subject,
+		DisplayName: name,
+		Icon:        picture,
+	}, nil
 }
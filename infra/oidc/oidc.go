@@ -0,0 +1,185 @@
+// Package oidc OpenID Connect の issuer でログインする
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/random"
+	"golang.org/x/oauth2"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+type Provider struct {
+	Config *oauth2.Config
+	// HTTPClient discovery とトークンの取得に使う。nil の場合は http.DefaultClient
+	HTTPClient *http.Client
+	issuer     string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// NewProvider discoveryURL から認可とトークンの URL を得る
+// discoveryURL は issuer でもよい
+func NewProvider(discoveryURL, clientID, clientSecret, redirectURL string, httpClient *http.Client) (*Provider, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if !strings.HasSuffix(discoveryURL, discoveryPath) {
+		discoveryURL = strings.TrimSuffix(discoveryURL, "/") + discoveryPath
+	}
+	resp, err := httpClient.Get(discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", discoveryURL, resp.Status)
+	}
+	var d discovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	if d.Issuer == "" || d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" {
+		return nil, fmt.Errorf("invalid discovery document: %s", discoveryURL)
+	}
+
+	return &Provider{
+		Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"openid", "profile"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  d.AuthorizationEndpoint,
+				TokenURL: d.TokenEndpoint,
+			},
+		},
+		HTTPClient: httpClient,
+		issuer:     d.Issuer,
+	}, nil
+}
+
+func (p *Provider) Issuer() string {
+	return p.issuer
+}
+
+func (p *Provider) GetOAuthURL() (url, state string) {
+	state = random.AlphaNumeric(10, true)
+	url = p.Config.AuthCodeURL(state)
+	return
+}
+
+// Authenticate 名前は preferred_username、無ければ subject にする
+// 名前は他のユーザーにも見えるのでメールアドレスは使わない
+func (p *Provider) Authenticate(query, state string) (string, *domain.ProfileArgs, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", nil, err
+	}
+	if state != values.Get("state") {
+		return "", nil, errors.New("state error")
+	}
+	ctx := context.WithValue(context.TODO(), oauth2.HTTPClient, p.HTTPClient)
+	token, err := p.Config.Exchange(ctx, values.Get("code"))
+	if err != nil {
+		return "", nil, err
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: id_token is not found", ErrInvalidIDToken)
+	}
+	c, err := p.parseIDToken(rawIDToken, time.Now())
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := c.PreferredUsername
+	if name == "" {
+		name = c.Subject
+	}
+	displayName := c.Name
+	if displayName == "" {
+		displayName = name
+	}
+	return c.Subject, &domain.ProfileArgs{
+		Name:        name,
+		DisplayName: displayName,
+		Icon:        c.Picture,
+	}, nil
+}
+
+type claims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	Expiry            int64    `json:"exp"`
+	PreferredUsername string   `json:"preferred_username"`
+	Name              string   `json:"name"`
+	Picture           string   `json:"picture"`
+}
+
+// audience 文字列と文字列の配列のどちらもある
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*a = ss
+	return nil
+}
+
+// parseIDToken トークンエンドポイントから TLS で直接受け取った ID トークンなので署名は確かめない
+// (OpenID Connect Core 1.0 3.1.3.7)
+func (p *Provider) parseIDToken(rawIDToken string, now time.Time) (*claims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed jwt", ErrInvalidIDToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+	if c.Issuer != p.issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %s", ErrInvalidIDToken, c.Issuer)
+	}
+	if !slices.Contains(c.Audience, p.Config.ClientID) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidIDToken)
+	}
+	if now.After(time.Unix(c.Expiry, 0)) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	}
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: sub is empty", ErrInvalidIDToken)
+	}
+	return &c, nil
+}
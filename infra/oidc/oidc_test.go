@@ -0,0 +1,128 @@
+package oidc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIDToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// setupIssuer code を受け取ると claims の ID トークンを返す issuer
+func setupIssuer(t *testing.T, claims func(issuer string) map[string]any) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("GET "+discoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(discovery{
+			Issuer:                server.URL,
+			AuthorizationEndpoint: server.URL + "/auth",
+			TokenEndpoint:         server.URL + "/token",
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     newIDToken(t, claims(server.URL)),
+		})
+	})
+	return server
+}
+
+func TestProvider_Authenticate(t *testing.T) {
+	tests := []struct {
+		name    string
+		claims  func(issuer string) map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "success",
+			claims: func(issuer string) map[string]any {
+				return map[string]any{
+					"iss": issuer, "sub": "subject", "aud": "client", "exp": time.Now().Add(time.Hour).Unix(),
+					"preferred_username": "guest", "name": "Guest",
+				}
+			},
+			want: "subject",
+		},
+		{
+			name: "audience array",
+			claims: func(issuer string) map[string]any {
+				return map[string]any{
+					"iss": issuer, "sub": "subject", "aud": []string{"other", "client"}, "exp": time.Now().Add(time.Hour).Unix(),
+				}
+			},
+			want: "subject",
+		},
+		{
+			name: "wrong issuer",
+			claims: func(_ string) map[string]any {
+				return map[string]any{
+					"iss": "https://example.com", "sub": "subject", "aud": "client", "exp": time.Now().Add(time.Hour).Unix(),
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			claims: func(issuer string) map[string]any {
+				return map[string]any{
+					"iss": issuer, "sub": "subject", "aud": "other", "exp": time.Now().Add(time.Hour).Unix(),
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			claims: func(issuer string) map[string]any {
+				return map[string]any{
+					"iss": issuer, "sub": "subject", "aud": "client", "exp": time.Now().Add(-time.Hour).Unix(),
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupIssuer(t, tt.claims)
+			p, err := NewProvider(server.URL, "client", "secret", "http://localhost:3000/api/callback/test", server.Client())
+			require.NoError(t, err)
+			assert.Equal(t, server.URL, p.Issuer())
+
+			_, state := p.GetOAuthURL()
+			subject, profile, err := p.Authenticate("code=code&state="+state, state)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidIDToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, subject)
+			assert.NotEmpty(t, profile.Name)
+		})
+	}
+
+	t.Run("state mismatch", func(t *testing.T) {
+		server := setupIssuer(t, tests[0].claims)
+		p, err := NewProvider(server.URL+discoveryPath, "client", "secret", "", server.Client())
+		require.NoError(t, err)
+		_, _, err = p.Authenticate("code=code&state=foo", "bar")
+		assert.Error(t, err)
+	})
+}
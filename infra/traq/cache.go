@@ -0,0 +1,189 @@
+package traq
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/traPtitech/go-traq"
+)
+
+const (
+	cacheKeyUser   = "user:"
+	cacheKeyUsers  = "users:"
+	cacheKeyGroup  = "group:"
+	cacheKeyGroups = "groups"
+)
+
+// Cache traQ API の結果を ttl の間キャッシュする
+// ttl を過ぎても stale の間は古い値を返し、裏で取得し直す
+type Cache struct {
+	ttl   time.Duration
+	store *cache.Cache
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+	// generation Invalidate 前に始まった取得結果を捨てるために使う
+	generation uint64
+
+	hits      atomic.Uint64
+	staleHits atomic.Uint64
+	misses    atomic.Uint64
+}
+
+type cacheEntry struct {
+	value     any
+	fetchedAt time.Time
+}
+
+// CacheStats キャッシュのヒット率
+type CacheStats struct {
+	Hits      uint64  `json:"hits"`
+	StaleHits uint64  `json:"staleHits"`
+	Misses    uint64  `json:"misses"`
+	HitRate   float64 `json:"hitRate"`
+}
+
+func NewCache(ttl, stale time.Duration) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		store:      cache.New(ttl+stale, 2*(ttl+stale)),
+		refreshing: make(map[string]bool),
+	}
+}
+
+func (c *Cache) Stats() CacheStats {
+	stats := CacheStats{
+		Hits:      c.hits.Load(),
+		StaleHits: c.staleHits.Load(),
+		Misses:    c.misses.Load(),
+	}
+	if total := stats.Hits + stats.StaleHits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits+stats.StaleHits) / float64(total)
+	}
+	return stats
+}
+
+// invalidate prefix から始まるキーを消す
+func (c *Cache) invalidate(prefix string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.generation++
+	c.mu.Unlock()
+	for key := range c.store.Items() {
+		if strings.HasPrefix(key, prefix) {
+			c.store.Delete(key)
+		}
+	}
+}
+
+func (c *Cache) get(key string, fetch func() (any, error)) (any, error) {
+	if v, ok := c.store.Get(key); ok {
+		e := v.(cacheEntry)
+		if time.Since(e.fetchedAt) < c.ttl {
+			c.hits.Add(1)
+		} else {
+			c.staleHits.Add(1)
+			c.refresh(key, fetch)
+		}
+		return e.value, nil
+	}
+
+	c.misses.Add(1)
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.set(key, value, generation)
+	return value, nil
+}
+
+// refresh 同じキーの取得は同時に一つだけ行う
+func (c *Cache) refresh(key string, fetch func() (any, error)) {
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	generation := c.generation
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+		value, err := fetch()
+		// 失敗した場合は stale の間古い値を返し続ける
+		if err != nil {
+			return
+		}
+		c.set(key, value, generation)
+	}()
+}
+
+func (c *Cache) set(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.store.SetDefault(key, cacheEntry{value: value, fetchedAt: time.Now()})
+}
+
+// cached c が nil の場合はキャッシュせずに毎回 fetch する
+// 呼び出し側が書き換えてもキャッシュが壊れないよう clone したものを返す
+func cached[T any](c *Cache, key string, fetch func() (T, error), clone func(T) T) (T, error) {
+	if c == nil {
+		return fetch()
+	}
+	v, err := c.get(key, func() (any, error) { return fetch() })
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return clone(v.(T)), nil
+}
+
+func cloneUser(u *traq.User) *traq.User {
+	if u == nil {
+		return nil
+	}
+	res := *u
+	return &res
+}
+
+func cloneUsers(users []traq.User) []traq.User {
+	return slices.Clone(users)
+}
+
+func cloneGroup(g *traq.UserGroup) *traq.UserGroup {
+	if g == nil {
+		return nil
+	}
+	res := *g
+	res.Members = slices.Clone(g.Members)
+	res.Admins = slices.Clone(g.Admins)
+	return &res
+}
+
+func cloneGroups(groups []traq.UserGroup) []traq.UserGroup {
+	if groups == nil {
+		return nil
+	}
+	res := make([]traq.UserGroup, len(groups))
+	for i := range groups {
+		res[i] = *cloneGroup(&groups[i])
+	}
+	return res
+}
@@ -0,0 +1,105 @@
+package traq
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/go-traq"
+)
+
+func TestCache(t *testing.T) {
+	same := func(v int) int { return v }
+
+	t.Run("hit", func(t *testing.T) {
+		c := NewCache(time.Minute, time.Minute)
+		var calls atomic.Int32
+		fetch := func() (int, error) { return int(calls.Add(1)), nil }
+
+		v, err := cached(c, "key", fetch, same)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		v, err = cached(c, "key", fetch, same)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, CacheStats{Hits: 1, Misses: 1, HitRate: 0.5}, c.Stats())
+	})
+
+	t.Run("stale while revalidate", func(t *testing.T) {
+		c := NewCache(time.Millisecond, time.Minute)
+		var calls atomic.Int32
+		fetch := func() (int, error) { return int(calls.Add(1)), nil }
+
+		_, err := cached(c, "key", fetch, same)
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+
+		// 古い値を返し、裏で取得し直す
+		v, err := cached(c, "key", fetch, same)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Eventually(t, func() bool {
+			v, _ := cached(c, "key", func() (int, error) { return 0, errors.New("not called") }, same)
+			return v == 2
+		}, time.Second, time.Millisecond)
+		assert.NotZero(t, c.Stats().StaleHits)
+	})
+
+	t.Run("error is not cached", func(t *testing.T) {
+		c := NewCache(time.Minute, time.Minute)
+		_, err := cached(c, "key", func() (int, error) { return 0, ErrNotFound }, same)
+		assert.ErrorIs(t, err, ErrNotFound)
+		v, err := cached(c, "key", func() (int, error) { return 1, nil }, same)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		c := NewCache(time.Minute, time.Minute)
+		_, _ = cached(c, cacheKeyUser+"a", func() (int, error) { return 1, nil }, same)
+		_, _ = cached(c, cacheKeyGroups, func() (int, error) { return 1, nil }, same)
+		c.invalidate(cacheKeyUser)
+
+		v, _ := cached(c, cacheKeyUser+"a", func() (int, error) { return 2, nil }, same)
+		assert.Equal(t, 2, v)
+		v, _ = cached(c, cacheKeyGroups, func() (int, error) { return 2, nil }, same)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var c *Cache
+		v, err := cached(c, "key", func() (int, error) { return 1, nil }, same)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		c.invalidate(cacheKeyUser)
+	})
+
+	t.Run("returns copies", func(t *testing.T) {
+		c := NewCache(time.Minute, time.Minute)
+		fetch := func() (*traq.UserGroup, error) {
+			return &traq.UserGroup{Name: "a", Members: []traq.UserGroupMember{{Id: "m"}}, Admins: []string{"m"}}, nil
+		}
+
+		g, err := cached(c, cacheKeyGroup+"a", fetch, cloneGroup)
+		require.NoError(t, err)
+		g.Name = "b"
+		g.Members[0].Id = "x"
+		g.Admins = append(g.Admins[:0], "x")
+
+		g, err = cached(c, cacheKeyGroup+"a", fetch, cloneGroup)
+		require.NoError(t, err)
+		assert.Equal(t, "a", g.Name)
+		assert.Equal(t, []traq.UserGroupMember{{Id: "m"}}, g.Members)
+		assert.Equal(t, []string{"m"}, g.Admins)
+
+		users, err := cached(c, cacheKeyUsers, func() ([]traq.User, error) { return []traq.User{{Name: "a"}}, nil }, cloneUsers)
+		require.NoError(t, err)
+		users[0].Name = "b"
+		users, err = cached(c, cacheKeyUsers, nil, cloneUsers)
+		require.NoError(t, err)
+		assert.Equal(t, "a", users[0].Name)
+	})
+}
@@ -10,38 +10,46 @@ import (
 )
 
 func (repo *TraQRepository) GetGroup(groupID uuid.UUID) (*traq.UserGroup, error) {
-	ctx := context.WithValue(context.TODO(), traq.ContextAccessToken, repo.ServerAccessToken)
-	apiClient := traq.NewAPIClient(traq.NewConfiguration())
-	// TODO: 一定期間キャッシュする
-	group, resp, err := apiClient.GroupAPI.GetUserGroup(ctx, groupID.String()).Execute()
-	if err != nil {
-		return nil, err
-	}
-	err = handleStatusCode(resp.StatusCode)
-	if err != nil {
-		return nil, err
-	}
-	return group, err
+	return cached(repo.Cache, cacheKeyGroup+groupID.String(), func() (*traq.UserGroup, error) {
+		ctx, err := repo.serverContext()
+		if err != nil {
+			return nil, err
+		}
+		apiClient := repo.apiClient()
+		group, resp, err := apiClient.GroupAPI.GetUserGroup(ctx, groupID.String()).Execute()
+		if err != nil {
+			return nil, err
+		}
+		err = handleStatusCode(resp.StatusCode)
+		if err != nil {
+			return nil, err
+		}
+		return group, err
+	}, cloneGroup)
 }
 
 func (repo *TraQRepository) GetAllGroups() ([]traq.UserGroup, error) {
-	ctx := context.WithValue(context.TODO(), traq.ContextAccessToken, repo.ServerAccessToken)
-	apiClient := traq.NewAPIClient(traq.NewConfiguration())
-	// TODO: 一定期間キャッシュする
-	groups, resp, err := apiClient.GroupAPI.GetUserGroups(ctx).Execute()
-	if err != nil {
-		return nil, err
-	}
-	err = handleStatusCode(resp.StatusCode)
-	if err != nil {
-		return nil, err
-	}
-	return groups, err
+	return cached(repo.Cache, cacheKeyGroups, func() ([]traq.UserGroup, error) {
+		ctx, err := repo.serverContext()
+		if err != nil {
+			return nil, err
+		}
+		apiClient := repo.apiClient()
+		groups, resp, err := apiClient.GroupAPI.GetUserGroups(ctx).Execute()
+		if err != nil {
+			return nil, err
+		}
+		err = handleStatusCode(resp.StatusCode)
+		if err != nil {
+			return nil, err
+		}
+		return groups, err
+	}, cloneGroups)
 }
 
 func (repo *TraQRepository) GetUserBelongingGroupIDs(token *oauth2.Token, userID uuid.UUID) ([]uuid.UUID, error) {
 	ctx := context.WithValue(context.TODO(), traq.ContextAccessToken, token.AccessToken)
-	apiClient := traq.NewAPIClient(traq.NewConfiguration())
+	apiClient := repo.apiClient()
 	user, resp, err := apiClient.UserAPI.GetUser(ctx, userID.String()).Execute()
 	if err != nil {
 		return nil, err
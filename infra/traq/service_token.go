@@ -0,0 +1,38 @@
+package traq
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/traPtitech/go-traq"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// serviceTokenEarlyExpiry 期限が切れるこの時間前にトークンを取り直す
+const serviceTokenEarlyExpiry = time.Minute
+
+// NewServiceTokenSource Client Credentials Flow でサーバーのトークンを取得する
+// トークンは期限が切れるまで使い回し、期限が近づくと取り直す
+func NewServiceTokenSource(config *clientcredentials.Config, httpClient *http.Client) oauth2.TokenSource {
+	ctx := context.Background()
+	if httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	}
+	return oauth2.ReuseTokenSourceWithExpiry(nil, config.TokenSource(ctx), serviceTokenEarlyExpiry)
+}
+
+// serverContext サーバーのトークンで traQ API を呼ぶための context
+// ServiceTokenSource が無い場合は ServerAccessToken を使う
+func (repo *TraQRepository) serverContext() (context.Context, error) {
+	accessToken := repo.ServerAccessToken
+	if repo.ServiceTokenSource != nil {
+		token, err := repo.ServiceTokenSource.Token()
+		if err != nil {
+			return nil, err
+		}
+		accessToken = token.AccessToken
+	}
+	return context.WithValue(context.TODO(), traq.ContextAccessToken, accessToken), nil
+}
@@ -0,0 +1,40 @@
+package traq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/go-traq"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func TestTraQRepository_ServiceToken(t *testing.T) {
+	server, repo := setupFakeTraQ(t)
+	server.AddUser(newFakeUser("user", traq.USERACCOUNTSTATE_active), "")
+	server.AddClient("client", "secret")
+
+	t.Run("token is reused until it expires", func(t *testing.T) {
+		repo.ServiceTokenSource = NewServiceTokenSource(&clientcredentials.Config{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     server.APIURL() + "/oauth2/token",
+		}, server.Client())
+		for range 2 {
+			users, err := repo.GetUsers(false)
+			require.NoError(t, err)
+			assert.Len(t, users, 1)
+		}
+		assert.Equal(t, 1, server.Requests("/oauth2/token"))
+	})
+
+	t.Run("invalid client", func(t *testing.T) {
+		repo.ServiceTokenSource = NewServiceTokenSource(&clientcredentials.Config{
+			ClientID:     "client",
+			ClientSecret: "wrong",
+			TokenURL:     server.APIURL() + "/oauth2/token",
+		}, server.Client())
+		_, err := repo.GetUsers(false)
+		assert.Error(t, err)
+	})
+}
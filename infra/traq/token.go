@@ -0,0 +1,29 @@
+package traq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// RefreshToken 期限が切れている場合は refresh token で取得し直す
+// force が true の場合は期限に関わらず取得し直す
+func (repo *TraQRepository) RefreshToken(token *oauth2.Token, force bool) (*oauth2.Token, error) {
+	t := *token
+	if force {
+		t.Expiry = time.Now().Add(-time.Minute)
+	}
+	newToken, err := repo.Config.TokenSource(repo.oauthContext(context.TODO()), &t).Token()
+	if err != nil {
+		// refresh token が無い、または無効になっている場合はログインし直すしかない
+		var retrieveErr *oauth2.RetrieveError
+		if t.RefreshToken == "" || errors.As(err, &retrieveErr) {
+			return nil, fmt.Errorf("%w: %w", ErrUnAuthorized, err)
+		}
+		return nil, err
+	}
+	return newToken, nil
+}
@@ -0,0 +1,60 @@
+package traq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/go-traq"
+	"golang.org/x/oauth2"
+)
+
+func TestTraQRepository_RefreshToken(t *testing.T) {
+	server, repo := setupFakeTraQ(t)
+	user := newFakeUser("user", traq.USERACCOUNTSTATE_active)
+	server.AddUser(user, "token")
+	server.AddRefreshToken(user.Id, "refresh")
+
+	t.Run("valid token is returned as is", func(t *testing.T) {
+		token := &oauth2.Token{AccessToken: "token", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+		newToken, err := repo.RefreshToken(token, false)
+		require.NoError(t, err)
+		assert.Equal(t, "token", newToken.AccessToken)
+		assert.Equal(t, 0, server.Requests("/oauth2/token"))
+	})
+
+	var refreshed *oauth2.Token
+	t.Run("expired token is refreshed", func(t *testing.T) {
+		token := &oauth2.Token{AccessToken: "token", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}
+		newToken, err := repo.RefreshToken(token, false)
+		require.NoError(t, err)
+		assert.NotEqual(t, "token", newToken.AccessToken)
+		assert.NotEqual(t, "refresh", newToken.RefreshToken)
+		assert.True(t, newToken.Expiry.After(time.Now()))
+
+		me, err := repo.GetUserMe(newToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.Id, me.Id)
+		refreshed = newToken
+	})
+
+	t.Run("force refresh", func(t *testing.T) {
+		require.NotNil(t, refreshed)
+		newToken, err := repo.RefreshToken(refreshed, true)
+		require.NoError(t, err)
+		assert.NotEqual(t, refreshed.AccessToken, newToken.AccessToken)
+	})
+
+	t.Run("used refresh token", func(t *testing.T) {
+		token := &oauth2.Token{AccessToken: "token", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}
+		_, err := repo.RefreshToken(token, false)
+		assert.ErrorIs(t, err, ErrUnAuthorized)
+	})
+
+	t.Run("no refresh token", func(t *testing.T) {
+		token := &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(-time.Hour)}
+		_, err := repo.RefreshToken(token, false)
+		assert.ErrorIs(t, err, ErrUnAuthorized)
+	})
+}
@@ -5,26 +5,56 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
+	"net/http"
 	"net/url"
 
+	"github.com/traPtitech/go-traq"
+	"github.com/traPtitech/knoQ/utils"
 	"github.com/traPtitech/knoQ/utils/random"
 	"golang.org/x/oauth2"
 )
 
+// IssuerName domain.Provider.Issuer に保存する値
+const IssuerName = "traQ"
+
 // TraQRepository is traq
 type TraQRepository struct { //nolint:revive
-	Config            *oauth2.Config
-	URL               string
-	ServerAccessToken string
+	Config *oauth2.Config
+	// URL traQ API の接続先。空の場合は https://q.trap.jp/api/v3
+	URL string
+	// HTTPClient traQ API と OAuth の通信に使う。nil の場合は http.DefaultClient
+	HTTPClient *http.Client
+	// ServiceTokenSource サーバーのトークン。nil の場合は ServerAccessToken を使う
+	ServiceTokenSource oauth2.TokenSource
+	ServerAccessToken  string
+	// Cache nil の場合はキャッシュしない
+	Cache *Cache
 }
 
-func newPKCE() (pkceOptions []oauth2.AuthCodeOption, codeVerifier string) {
-	codeVerifier = random.AlphaNumeric(43, true)
+func (repo *TraQRepository) apiClient() *traq.APIClient {
+	return utils.TraQAPI{URL: repo.URL, HTTPClient: repo.HTTPClient}.NewAPIClient()
+}
+
+// oauthContext oauth2 のトークン取得に HTTPClient を使わせる
+func (repo *TraQRepository) oauthContext(ctx context.Context) context.Context {
+	if repo.HTTPClient == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, repo.HTTPClient)
+}
+
+// PKCEChallenge code_challenge_method が S256 の code_challenge
+func PKCEChallenge(codeVerifier string) string {
 	result := sha256.Sum256([]byte(codeVerifier))
 	enc := base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_").WithPadding(base64.NoPadding)
+	return enc.EncodeToString(result[:])
+}
+
+func newPKCE() (pkceOptions []oauth2.AuthCodeOption, codeVerifier string) {
+	codeVerifier = random.AlphaNumeric(43, true)
 
 	return []oauth2.AuthCodeOption{
-			oauth2.SetAuthURLParam("code_challenge", enc.EncodeToString(result[:])),
+			oauth2.SetAuthURLParam("code_challenge", PKCEChallenge(codeVerifier)),
 			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
 		},
 		codeVerifier
@@ -40,7 +70,7 @@ func (repo *TraQRepository) GetOAuthURL() (url, state, codeVerifier string) {
 }
 
 func (repo *TraQRepository) GetOAuthToken(query, state, codeVerifier string) (*oauth2.Token, error) {
-	ctx := context.TODO()
+	ctx := repo.oauthContext(context.TODO())
 	values, err := url.ParseQuery(query)
 	if err != nil {
 		return nil, err
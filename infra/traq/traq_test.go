@@ -0,0 +1,136 @@
+package traq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/go-traq"
+	"github.com/traPtitech/knoQ/infra/traq/traqtest"
+	"golang.org/x/oauth2"
+)
+
+func setupFakeTraQ(t *testing.T) (*traqtest.Server, *TraQRepository) {
+	t.Helper()
+	server := traqtest.NewServer()
+	t.Cleanup(server.Close)
+	repo := &TraQRepository{
+		Config: &oauth2.Config{
+			Endpoint: oauth2.Endpoint{
+				TokenURL:  server.APIURL() + "/oauth2/token",
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+		},
+		URL:               server.APIURL(),
+		HTTPClient:        server.Client(),
+		ServerAccessToken: "server",
+	}
+	return server, repo
+}
+
+func newFakeUser(name string, state traq.UserAccountState) traq.User {
+	return traq.User{
+		Id:          uuid.Must(uuid.NewV4()).String(),
+		Name:        name,
+		DisplayName: name,
+		IconFileId:  uuid.Must(uuid.NewV4()).String(),
+		State:       state,
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func TestTraQRepository_User(t *testing.T) {
+	server, repo := setupFakeTraQ(t)
+	active := newFakeUser("active", traq.USERACCOUNTSTATE_active)
+	suspended := newFakeUser("suspended", traq.USERACCOUNTSTATE_suspended)
+	groupID := uuid.Must(uuid.NewV4())
+	server.AddUser(active, "token", groupID.String())
+	server.AddUser(suspended, "")
+
+	t.Run("get user", func(t *testing.T) {
+		user, err := repo.GetUser(uuid.FromStringOrNil(active.Id))
+		require.NoError(t, err)
+		assert.Equal(t, active.Name, user.Name)
+	})
+
+	t.Run("get users", func(t *testing.T) {
+		users, err := repo.GetUsers(false)
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+		users, err = repo.GetUsers(true)
+		require.NoError(t, err)
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("get me", func(t *testing.T) {
+		user, err := repo.GetUserMe(&oauth2.Token{AccessToken: "token"})
+		require.NoError(t, err)
+		assert.Equal(t, active.Id, user.Id)
+	})
+
+	t.Run("get belonging groups", func(t *testing.T) {
+		groupIDs, err := repo.GetUserBelongingGroupIDs(&oauth2.Token{AccessToken: "token"}, uuid.FromStringOrNil(active.Id))
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{groupID}, groupIDs)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		_, err := repo.GetUser(uuid.Must(uuid.NewV4()))
+		assert.Error(t, err)
+	})
+}
+
+func TestTraQRepository_Group(t *testing.T) {
+	server, repo := setupFakeTraQ(t)
+	user := newFakeUser("user", traq.USERACCOUNTSTATE_active)
+	group := traq.UserGroup{
+		Id:        uuid.Must(uuid.NewV4()).String(),
+		Name:      "group",
+		Type:      "grade",
+		Icon:      uuid.Must(uuid.NewV4()).String(),
+		Members:   []traq.UserGroupMember{{Id: user.Id, Role: ""}},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Admins:    []string{user.Id},
+	}
+	server.AddGroup(group)
+
+	t.Run("get group", func(t *testing.T) {
+		g, err := repo.GetGroup(uuid.FromStringOrNil(group.Id))
+		require.NoError(t, err)
+		assert.Equal(t, group.Name, g.Name)
+		assert.Equal(t, group.Admins, g.Admins)
+	})
+
+	t.Run("get all groups", func(t *testing.T) {
+		groups, err := repo.GetAllGroups()
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, group.Id, groups[0].Id)
+	})
+}
+
+func TestTraQRepository_Cache(t *testing.T) {
+	server, repo := setupFakeTraQ(t)
+	repo.Cache = NewCache(time.Minute, time.Minute)
+	user := newFakeUser("user", traq.USERACCOUNTSTATE_active)
+	server.AddUser(user, "")
+
+	for range 3 {
+		_, err := repo.GetUsers(true)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, server.Requests("/users"))
+
+	repo.InvalidateUsers()
+	_, err := repo.GetUsers(true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.Requests("/users"))
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	// RFC 7636 Appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
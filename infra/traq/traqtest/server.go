@@ -0,0 +1,287 @@
+// Package traqtest テスト用に traQ API の一部を真似る httptest のサーバー
+package traqtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/traPtitech/go-traq"
+)
+
+// Webhook サーバーが受け取った Webhook
+type Webhook struct {
+	WebhookID string
+	ChannelID string
+	Signature string
+	Body      string
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	users         map[string]traq.UserDetail
+	groups        map[string]traq.UserGroup
+	tokens        map[string]string
+	refreshTokens map[string]string
+	clients       map[string]string
+	issued        int
+	webhooks      []Webhook
+	requests      map[string]int
+}
+
+// NewServer Close で止める
+func NewServer() *Server {
+	s := &Server{
+		users:         make(map[string]traq.UserDetail),
+		groups:        make(map[string]traq.UserGroup),
+		tokens:        make(map[string]string),
+		refreshTokens: make(map[string]string),
+		clients:       make(map[string]string),
+		requests:      make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/users", s.handleGetUsers)
+	mux.HandleFunc("GET /api/v3/users/me", s.handleGetMe)
+	mux.HandleFunc("GET /api/v3/users/{userID}", s.handleGetUser)
+	mux.HandleFunc("GET /api/v3/groups", s.handleGetGroups)
+	mux.HandleFunc("GET /api/v3/groups/{groupID}", s.handleGetGroup)
+	mux.HandleFunc("POST /api/v3/webhooks/{webhookID}", s.handlePostWebhook)
+	mux.HandleFunc("POST /api/v3/oauth2/token", s.handlePostToken)
+	s.Server = httptest.NewServer(s.count(mux))
+	return s
+}
+
+// APIURL TraQRepository.URL などに渡す
+func (s *Server) APIURL() string {
+	return s.URL + "/api/v3"
+}
+
+// AddUser token が空でない場合は、その token で /users/me を取得できる
+func (s *Server) AddUser(user traq.User, token string, groupIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.Id] = traq.UserDetail{
+		Id:          user.Id,
+		State:       user.State,
+		Bot:         user.Bot,
+		IconFileId:  user.IconFileId,
+		DisplayName: user.DisplayName,
+		Name:        user.Name,
+		UpdatedAt:   user.UpdatedAt,
+		Tags:        []traq.UserTag{},
+		Groups:      groupIDs,
+	}
+	if token != "" {
+		s.tokens[token] = user.Id
+	}
+}
+
+// AddRefreshToken refreshToken で userID のトークンを取得し直せるようにする
+// 一度使った refresh token は使えなくなる
+func (s *Server) AddRefreshToken(userID, refreshToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshTokens[refreshToken] = userID
+}
+
+// AddClient clientID と clientSecret で Client Credentials Flow のトークンを取得できるようにする
+func (s *Server) AddClient(clientID, clientSecret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[clientID] = clientSecret
+}
+
+func (s *Server) AddGroup(group traq.UserGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups[group.Id] = group
+}
+
+// Webhooks 受け取った順に返す
+func (s *Server) Webhooks() []Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Webhook{}, s.webhooks...)
+}
+
+// Requests path へのリクエストの回数
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[strings.TrimPrefix(r.URL.Path, "/api/v3")]++
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleGetUsers(w http.ResponseWriter, r *http.Request) {
+	includeSuspended := r.URL.Query().Get("include-suspended") == "true"
+	s.mu.Lock()
+	users := make([]traq.User, 0, len(s.users))
+	for _, u := range s.users {
+		if u.State != traq.USERACCOUNTSTATE_active && !includeSuspended {
+			continue
+		}
+		users = append(users, traq.User{
+			Id:          u.Id,
+			Name:        u.Name,
+			DisplayName: u.DisplayName,
+			IconFileId:  u.IconFileId,
+			Bot:         u.Bot,
+			State:       u.State,
+			UpdatedAt:   u.UpdatedAt,
+		})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) handleGetMe(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	user, ok := s.users[s.tokens[token]]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, traq.MyUserDetail{
+		Id:          user.Id,
+		Bio:         user.Bio,
+		Groups:      user.Groups,
+		Tags:        user.Tags,
+		UpdatedAt:   user.UpdatedAt,
+		LastOnline:  user.LastOnline,
+		TwitterId:   user.TwitterId,
+		Name:        user.Name,
+		DisplayName: user.DisplayName,
+		IconFileId:  user.IconFileId,
+		Bot:         user.Bot,
+		State:       user.State,
+		Permissions: []traq.UserPermission{},
+		HomeChannel: user.HomeChannel,
+	})
+}
+
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.users[r.PathValue("userID")]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handleGetGroups(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	groups := make([]traq.UserGroup, 0, len(s.groups))
+	for _, g := range s.groups {
+		groups = append(groups, g)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, groups)
+}
+
+func (s *Server) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.groups[r.PathValue("groupID")]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, group)
+}
+
+func (s *Server) handlePostWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.webhooks = append(s.webhooks, Webhook{
+		WebhookID: r.PathValue("webhookID"),
+		ChannelID: r.Header.Get("X-TRAQ-Channel-Id"),
+		Signature: r.Header.Get("X-TRAQ-Signature"),
+		Body:      string(body),
+	})
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handlePostToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.PostForm.Get("grant_type") {
+	case "refresh_token":
+		s.refreshToken(w, r)
+	case "client_credentials":
+		s.clientCredentials(w, r)
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+	}
+}
+
+func (s *Server) clientCredentials(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if secret, ok := s.clients[clientID]; !ok || secret != clientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	s.issued++
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": fmt.Sprintf("service-%d", s.issued),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (s *Server) refreshToken(w http.ResponseWriter, r *http.Request) {
+	refreshToken := r.PostForm.Get("refresh_token")
+	userID, ok := s.refreshTokens[refreshToken]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	delete(s.refreshTokens, refreshToken)
+	s.issued++
+	accessToken := fmt.Sprintf("access-%d", s.issued)
+	newRefreshToken := fmt.Sprintf("refresh-%d", s.issued)
+	s.tokens[accessToken] = userID
+	s.refreshTokens[newRefreshToken] = userID
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  accessToken,
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": newRefreshToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
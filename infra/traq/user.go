@@ -2,6 +2,7 @@ package traq
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/go-traq"
@@ -9,47 +10,61 @@ import (
 )
 
 func (repo *TraQRepository) GetUser(userID uuid.UUID) (*traq.User, error) {
-	ctx := context.WithValue(context.TODO(), traq.ContextAccessToken, repo.ServerAccessToken)
-	apiClient := traq.NewAPIClient(traq.NewConfiguration())
-	// TODO: 一定期間キャッシュする
-	userDetail, resp, err := apiClient.UserAPI.GetUser(ctx, userID.String()).Execute()
-	if err != nil {
-		return nil, err
-	}
-	err = handleStatusCode(resp.StatusCode)
-	if err != nil {
-		return nil, err
-	}
-	user := traq.User{
-		Id:          userDetail.Id,
-		Name:        userDetail.Name,
-		DisplayName: userDetail.DisplayName,
-		IconFileId:  userDetail.IconFileId,
-		Bot:         userDetail.Bot,
-		State:       userDetail.State,
-		UpdatedAt:   userDetail.UpdatedAt,
-	}
-	return &user, err
+	return cached(repo.Cache, cacheKeyUser+userID.String(), func() (*traq.User, error) {
+		ctx, err := repo.serverContext()
+		if err != nil {
+			return nil, err
+		}
+		apiClient := repo.apiClient()
+		userDetail, resp, err := apiClient.UserAPI.GetUser(ctx, userID.String()).Execute()
+		if err != nil {
+			return nil, err
+		}
+		err = handleStatusCode(resp.StatusCode)
+		if err != nil {
+			return nil, err
+		}
+		user := traq.User{
+			Id:          userDetail.Id,
+			Name:        userDetail.Name,
+			DisplayName: userDetail.DisplayName,
+			IconFileId:  userDetail.IconFileId,
+			Bot:         userDetail.Bot,
+			State:       userDetail.State,
+			UpdatedAt:   userDetail.UpdatedAt,
+		}
+		return &user, err
+	}, cloneUser)
 }
 
 func (repo *TraQRepository) GetUsers(includeSuspended bool) ([]traq.User, error) {
-	ctx := context.WithValue(context.TODO(), traq.ContextAccessToken, repo.ServerAccessToken)
-	apiClient := traq.NewAPIClient(traq.NewConfiguration())
-	// TODO: 一定期間キャッシュする
-	users, resp, err := apiClient.UserAPI.GetUsers(ctx).IncludeSuspended(includeSuspended).Execute()
-	if err != nil {
-		return nil, err
-	}
-	err = handleStatusCode(resp.StatusCode)
-	if err != nil {
-		return nil, err
-	}
-	return users, err
+	return cached(repo.Cache, cacheKeyUsers+strconv.FormatBool(includeSuspended), func() ([]traq.User, error) {
+		ctx, err := repo.serverContext()
+		if err != nil {
+			return nil, err
+		}
+		apiClient := repo.apiClient()
+		users, resp, err := apiClient.UserAPI.GetUsers(ctx).IncludeSuspended(includeSuspended).Execute()
+		if err != nil {
+			return nil, err
+		}
+		err = handleStatusCode(resp.StatusCode)
+		if err != nil {
+			return nil, err
+		}
+		return users, err
+	}, cloneUsers)
+}
+
+// InvalidateUsers キャッシュしたユーザーを消し、次は traQ から取得する
+func (repo *TraQRepository) InvalidateUsers() {
+	repo.Cache.invalidate(cacheKeyUser)
+	repo.Cache.invalidate(cacheKeyUsers)
 }
 
 func (repo *TraQRepository) GetUserMe(token *oauth2.Token) (*traq.User, error) {
 	ctx := context.WithValue(context.TODO(), traq.ContextAccessToken, token.AccessToken)
-	apiClient := traq.NewAPIClient(traq.NewConfiguration())
+	apiClient := repo.apiClient()
 	userDetail, resp, err := apiClient.MeAPI.GetMe(ctx).Execute()
 	if err != nil {
 		return nil, err
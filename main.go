@@ -2,19 +2,25 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/traPtitech/knoQ/domain"
 	"github.com/traPtitech/knoQ/infra/db"
+	"github.com/traPtitech/knoQ/infra/google"
+	"github.com/traPtitech/knoQ/infra/oidc"
 	"github.com/traPtitech/knoQ/infra/traq"
 	"github.com/traPtitech/knoQ/service"
 	"github.com/traPtitech/knoQ/utils"
 	"github.com/traPtitech/knoQ/utils/tz"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	googleoauth "golang.org/x/oauth2/google"
 
 	"github.com/traPtitech/knoQ/router"
 
@@ -43,10 +49,21 @@ var (
 	webhookSecret     = getenv("WEBHOOK_SECRET", "")
 	activityChannelID = getenv("ACTIVITY_CHANNEL_ID", "")
 	dailyChannelID    = getenv("DAILY_CHANNEL_ID", "")
+	timetablePeriods  = getenv("TIMETABLE_PERIODS", "")
 
-	// TODO: traQにClient Credential Flowが実装されたら定期的に取得するように変更する
-	// Issue: https://github.com/traPtitech/traQ/issues/2403
-	traqAccessToken = getenv("TRAQ_ACCESS_TOKEN", "")
+	// TRAQ_CLIENT_SECRET がある場合は Client Credentials Flow で取得したトークンを使う
+	// 無い場合は TRAQ_ACCESS_TOKEN を使う
+	traqClientSecret = getenv("TRAQ_CLIENT_SECRET", "")
+	traqAccessToken  = getenv("TRAQ_ACCESS_TOKEN", "")
+	traqAPIURL       = getenv("TRAQ_API_URL", "https://q.trap.jp/api/v3")
+	traqCacheTTL     = getenv("TRAQ_CACHE_TTL", "1m")
+	traqCacheStale   = getenv("TRAQ_CACHE_STALE", "10m")
+
+	// 両方ある場合は Google でゲストユーザーがログインできる
+	googleClientID     = getenv("GOOGLE_CLIENT_ID", "")
+	googleClientSecret = getenv("GOOGLE_CLIENT_SECRET", "")
+	// カンマ区切りの名前。名前ごとに OIDC_<NAME>_DISCOVERY_URL などを読む
+	oidcProviders = getenv("OIDC_PROVIDERS", "")
 )
 
 func main() {
@@ -59,20 +76,44 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	cacheTTL, err := time.ParseDuration(traqCacheTTL)
+	if err != nil {
+		panic(err)
+	}
+	cacheStale, err := time.ParseDuration(traqCacheStale)
+	if err != nil {
+		panic(err)
+	}
 	traqRepo := traq.TraQRepository{
 		Config: &oauth2.Config{
 			ClientID:    clientID,
 			RedirectURL: origin + "/api/callback",
 			Scopes:      []string{"read"},
 			Endpoint: oauth2.Endpoint{
-				AuthURL:  "https://q.trap.jp/api/v3/oauth2/authorize",
-				TokenURL: "https://q.trap.jp/api/v3/oauth2/token",
+				AuthURL:  traqAPIURL + "/oauth2/authorize",
+				TokenURL: traqAPIURL + "/oauth2/token",
 			},
 		},
-		URL:               "https://q.trap.jp/api/v3",
+		URL:               traqAPIURL,
 		ServerAccessToken: traqAccessToken,
+		Cache:             traq.NewCache(cacheTTL, cacheStale),
+	}
+	if traqClientSecret != "" {
+		traqRepo.ServiceTokenSource = traq.NewServiceTokenSource(&clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: traqClientSecret,
+			TokenURL:     traqRepo.Config.Endpoint.TokenURL,
+			Scopes:       []string{"read"},
+		}, nil)
+	}
+	// /api/debug/vars で確認できる
+	expvar.Publish("traqCache", expvar.Func(func() any { return traqRepo.Cache.Stats() }))
+	providers := newProviderRegistry(logger)
+	s := service.NewService(gormRepo, &traqRepo, providers, txManager)
+	periods, err := utils.ParsePeriods(timetablePeriods)
+	if err != nil {
+		panic(err)
 	}
-	s := service.NewService(gormRepo, &traqRepo, txManager)
 	handler := &router.Handlers{
 		Service:    s,
 		Logger:     logger,
@@ -89,6 +130,8 @@ func main() {
 		ActivityChannelID: activityChannelID,
 		DailyChannelID:    dailyChannelID,
 		Origin:            origin,
+		TraQAPI:           utils.TraQAPI{URL: traqAPIURL},
+		Periods:           periods,
 	}
 
 	e := handler.SetupRoute()
@@ -99,6 +142,8 @@ func main() {
 		"0 8 * * *",
 		utils.InitPostEventToTraQ(
 			gormRepo,
+			handler.TraQAPI,
+			handler.Periods,
 			handler.WebhookSecret,
 			handler.DailyChannelID,
 			handler.WebhookID,
@@ -108,6 +153,30 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	// traQ のグループはメンバーの変更を知れないので定期的に参加者を合わせる
+	_, err = c.AddFunc(
+		"0 * * * *",
+		func() {
+			if err := s.SyncTraQGroupAttendees(context.Background()); err != nil {
+				logger.Error("failed to sync traQ group attendees", zap.Error(err))
+			}
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+	// 期限が近い traQ のトークンを使われる前に更新しておく
+	_, err = c.AddFunc(
+		"*/10 * * * *",
+		func() {
+			if err := s.RefreshExpiringTokens(context.Background(), 30*time.Minute); err != nil {
+				logger.Error("failed to refresh traQ tokens", zap.Error(err))
+			}
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
 	c.Start()
 
 	// サーバースタート
@@ -126,6 +195,43 @@ func main() {
 	}
 }
 
+// newProviderRegistry ログインに使うプロバイダを環境変数から作る
+// OIDC の discovery に失敗したプロバイダはログに残して使わない
+func newProviderRegistry(logger *zap.Logger) *service.ProviderRegistry {
+	providers := service.NewProviderRegistry(traq.IssuerName)
+	if googleClientID != "" && googleClientSecret != "" {
+		providers.Register("google", &google.Repository{
+			Config: &oauth2.Config{
+				ClientID:     googleClientID,
+				ClientSecret: googleClientSecret,
+				RedirectURL:  origin + "/api/callback/google",
+				Scopes:       []string{"openid", "profile"},
+				Endpoint:     googleoauth.Endpoint,
+			},
+		})
+	}
+	for _, name := range strings.Split(oidcProviders, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		p, err := oidc.NewProvider(
+			getenv(prefix+"DISCOVERY_URL", ""),
+			getenv(prefix+"CLIENT_ID", ""),
+			getenv(prefix+"CLIENT_SECRET", ""),
+			origin+"/api/callback/"+name,
+			&http.Client{Timeout: 10 * time.Second},
+		)
+		if err != nil {
+			logger.Error("failed to set up oidc provider", zap.String("provider", name), zap.Error(err))
+			continue
+		}
+		providers.Register(name, p)
+	}
+	return providers
+}
+
 func getenv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -19,5 +19,22 @@ func Migrations() []*gormigrate.Migration {
 		v10(),
 		v11(),
 		v12(),
+		v13(),
+		v14(),
+		v15(),
+		v16(),
+		v17(),
+		v18(),
+		v19(),
+		v20(),
+		v21(),
+		v22(),
+		v23(),
+		v24(),
+		v25(),
+		v26(),
+		v27(),
+		v28(),
+		v29(),
 	}
 }
@@ -0,0 +1,45 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v13User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v13User) TableName() string {
+	return "users"
+}
+
+type v13RoomBlackout struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	RoomID         uuid.UUID `gorm:"type:char(36); index"`
+	Place          string    `gorm:"type:varchar(32); index"`
+	TimeStart      time.Time `gorm:"type:DATETIME; index"`
+	TimeEnd        time.Time `gorm:"type:DATETIME; index"`
+	Reason         string    `gorm:"type:TEXT"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);"`
+	CreatedBy      v13User   `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v13RoomBlackout) TableName() string {
+	return "room_blackouts"
+}
+
+// v13 部屋が使えなくなる時間帯
+func v13() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "13",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&v13RoomBlackout{})
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v14Room struct {
+	ID       uuid.UUID `gorm:"type:char(36);primaryKey"`
+	SeriesID uuid.UUID `gorm:"type:char(36); not null; default:'00000000-0000-0000-0000-000000000000'; index"`
+}
+
+func (*v14Room) TableName() string {
+	return "rooms"
+}
+
+type v14User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v14User) TableName() string {
+	return "users"
+}
+
+type v14RoomSeriesException struct {
+	SeriesID uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Date     time.Time `gorm:"type:DATE; primaryKey"`
+}
+
+func (*v14RoomSeriesException) TableName() string {
+	return "room_series_exceptions"
+}
+
+type v14RoomSeries struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Place          string    `gorm:"type:varchar(32);"`
+	Weekdays       int
+	TimeStart      time.Time                `gorm:"type:DATETIME"`
+	TimeEnd        time.Time                `gorm:"type:DATETIME"`
+	Until          time.Time                `gorm:"type:DATE"`
+	Exceptions     []v14RoomSeriesException `gorm:"foreignKey:SeriesID; constraint:OnDelete:CASCADE;"`
+	CreatedByRefer uuid.UUID                `gorm:"type:char(36);"`
+	CreatedBy      v14User                  `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v14RoomSeries) TableName() string {
+	return "room_series"
+}
+
+// v14 部屋の繰り返し確保
+func v14() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "14",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Migrator().AddColumn(&v14Room{}, "series_id")
+			if err != nil {
+				return err
+			}
+			err = db.Migrator().CreateIndex(&v14Room{}, "SeriesID")
+			if err != nil {
+				return err
+			}
+			err = db.Migrator().CreateTable(&v14RoomSeries{})
+			if err != nil {
+				return err
+			}
+			return db.Migrator().CreateTable(&v14RoomSeriesException{})
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v15PlaceAttribute struct {
+	Place        string `gorm:"type:varchar(32); primaryKey"`
+	Capacity     int
+	Projector    bool
+	Whiteboard   bool
+	PowerOutlets bool
+	Accessible   bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (*v15PlaceAttribute) TableName() string {
+	return "place_attributes"
+}
+
+// v15 場所ごとの設備
+func v15() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "15",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&v15PlaceAttribute{})
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v16PlaceAttribute struct {
+	Place          string `gorm:"type:varchar(32); primaryKey"`
+	SharedCapacity int    `gorm:"not null; default:0"`
+}
+
+func (*v16PlaceAttribute) TableName() string {
+	return "place_attributes"
+}
+
+// v16 同時に入れる共用イベントの数
+func v16() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "16",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().AddColumn(&v16PlaceAttribute{}, "shared_capacity")
+		},
+	}
+}
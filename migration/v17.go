@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v17User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v17User) TableName() string {
+	return "users"
+}
+
+type v17GroupInvitation struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID        uuid.UUID `gorm:"type:char(36); not null; index"`
+	Token          string    `gorm:"type:varchar(32); not null; uniqueIndex"`
+	ExpiresAt      time.Time `gorm:"type:DATETIME"`
+	Invitee        uuid.UUID `gorm:"type:char(36)"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);"`
+	CreatedBy      v17User   `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v17GroupInvitation) TableName() string {
+	return "group_invitations"
+}
+
+type v17GroupJoinRequest struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID   uuid.UUID `gorm:"type:char(36); not null; index"`
+	UserID    uuid.UUID `gorm:"type:char(36); not null; index"`
+	User      v17User   `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	Message   string    `gorm:"type:TEXT"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v17GroupJoinRequest) TableName() string {
+	return "group_join_requests"
+}
+
+// v17 グループへの招待と参加申請
+func v17() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "17",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Migrator().CreateTable(&v17GroupInvitation{})
+			if err != nil {
+				return err
+			}
+			return db.Migrator().CreateTable(&v17GroupJoinRequest{})
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v18User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v18User) TableName() string {
+	return "users"
+}
+
+type v18GroupAuditLog struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID        uuid.UUID `gorm:"type:char(36); not null; index"`
+	Action         string    `gorm:"type:varchar(32); not null"`
+	TargetID       uuid.UUID `gorm:"type:char(36); not null"`
+	Target         v18User   `gorm:"->; foreignKey:TargetID; constraint:OnDelete:CASCADE;"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);"`
+	CreatedBy      v18User   `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v18GroupAuditLog) TableName() string {
+	return "group_audit_logs"
+}
+
+// v18 グループのメンバーと管理者の変更履歴
+func v18() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "18",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&v18GroupAuditLog{})
+		},
+	}
+}
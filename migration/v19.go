@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v19User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v19User) TableName() string {
+	return "users"
+}
+
+type v19GroupICalToken struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	GroupID        uuid.UUID `gorm:"type:char(36); not null; index"`
+	Token          string    `gorm:"type:varchar(32); not null; uniqueIndex"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);"`
+	CreatedBy      v19User   `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v19GroupICalToken) TableName() string {
+	return "group_ical_tokens"
+}
+
+// v19 グループの iCal のトークン
+func v19() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "19",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&v19GroupICalToken{})
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v20Group struct {
+	ID       uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Archived bool      `gorm:"not null; default:false; index"`
+}
+
+func (*v20Group) TableName() string {
+	return "groups"
+}
+
+// v20 グループのアーカイブ
+func v20() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "20",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Migrator().AddColumn(&v20Group{}, "archived")
+			if err != nil {
+				return err
+			}
+			return db.Migrator().CreateIndex(&v20Group{}, "Archived")
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v21Group struct {
+	ID       uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ParentID uuid.UUID `gorm:"type:char(36); index"`
+}
+
+func (*v21Group) TableName() string {
+	return "groups"
+}
+
+// v21 グループの親子関係
+func v21() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "21",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Migrator().AddColumn(&v21Group{}, "parent_id")
+			if err != nil {
+				return err
+			}
+			return db.Migrator().CreateIndex(&v21Group{}, "ParentID")
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v22User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v22User) TableName() string {
+	return "users"
+}
+
+type v22GroupUserRole struct {
+	UserID    uuid.UUID `gorm:"type:char(36); primaryKey"`
+	GroupID   uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Role      string    `gorm:"type:varchar(16); not null"`
+	User      v22User   `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v22GroupUserRole) TableName() string {
+	return "group_user_roles"
+}
+
+// v22 グループの organizer と viewer
+func v22() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "22",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&v22GroupUserRole{})
+		},
+	}
+}
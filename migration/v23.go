@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v23User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v23User) TableName() string {
+	return "users"
+}
+
+type v23UserProfile struct {
+	UserID      uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Name        string    `gorm:"type:varchar(256); not null"`
+	DisplayName string    `gorm:"type:varchar(256); not null"`
+	Icon        string    `gorm:"type:text"`
+	User        v23User   `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+}
+
+func (*v23UserProfile) TableName() string {
+	return "user_profiles"
+}
+
+// v23 traQ 以外のプロバイダで認証したユーザーのプロフィール
+func v23() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "23",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&v23UserProfile{})
+		},
+	}
+}
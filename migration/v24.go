@@ -0,0 +1,45 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v24User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v24User) TableName() string {
+	return "users"
+}
+
+type v24PersonalAccessToken struct {
+	ID         uuid.UUID `gorm:"type:char(36); primaryKey"`
+	UserID     uuid.UUID `gorm:"type:char(36); not null; index"`
+	User       v24User   `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	Name       string    `gorm:"type:varchar(64); not null"`
+	TokenHash  string    `gorm:"type:char(64); not null; uniqueIndex"`
+	Scopes     string    `gorm:"type:varchar(128); not null"`
+	ExpiresAt  time.Time `gorm:"type:DATETIME"`
+	LastUsedAt time.Time `gorm:"type:DATETIME"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v24PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// v24 個人用アクセストークン
+func v24() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "24",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&v24PersonalAccessToken{})
+		},
+	}
+}
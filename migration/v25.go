@@ -0,0 +1,85 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v25User struct {
+	ID uuid.UUID `gorm:"type:char(36); primaryKey"`
+}
+
+func (*v25User) TableName() string {
+	return "users"
+}
+
+type v25OAuthClient struct {
+	ID             uuid.UUID `gorm:"type:char(36); primaryKey"`
+	Name           string    `gorm:"type:varchar(64); not null"`
+	Description    string    `gorm:"type:TEXT"`
+	RedirectURIs   string    `gorm:"type:TEXT; not null"`
+	Scopes         string    `gorm:"type:varchar(128); not null"`
+	SecretHash     string    `gorm:"type:char(64)"`
+	CreatedByRefer uuid.UUID `gorm:"type:char(36);"`
+	CreatedBy      v25User   `gorm:"->; foreignKey:CreatedByRefer; constraint:OnDelete:CASCADE;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v25OAuthClient) TableName() string {
+	return "o_auth_clients"
+}
+
+type v25OAuthAuthorizationCode struct {
+	CodeHash      string         `gorm:"type:char(64); primaryKey"`
+	ClientID      uuid.UUID      `gorm:"type:char(36); not null; index"`
+	Client        v25OAuthClient `gorm:"->; foreignKey:ClientID; constraint:OnDelete:CASCADE;"`
+	UserID        uuid.UUID      `gorm:"type:char(36); not null"`
+	User          v25User        `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	RedirectURI   string         `gorm:"type:TEXT; not null"`
+	Scopes        string         `gorm:"type:varchar(128); not null"`
+	CodeChallenge string         `gorm:"type:varchar(128); not null"`
+	ExpiresAt     time.Time      `gorm:"type:DATETIME; not null"`
+	CreatedAt     time.Time
+}
+
+func (*v25OAuthAuthorizationCode) TableName() string {
+	return "o_auth_authorization_codes"
+}
+
+type v25OAuthToken struct {
+	ID               uuid.UUID      `gorm:"type:char(36); primaryKey"`
+	ClientID         uuid.UUID      `gorm:"type:char(36); not null; index"`
+	Client           v25OAuthClient `gorm:"->; foreignKey:ClientID; constraint:OnDelete:CASCADE;"`
+	UserID           uuid.UUID      `gorm:"type:char(36); not null; index"`
+	User             v25User        `gorm:"->; foreignKey:UserID; constraint:OnDelete:CASCADE;"`
+	AccessTokenHash  string         `gorm:"type:char(64); not null; uniqueIndex"`
+	RefreshTokenHash string         `gorm:"type:char(64); not null; uniqueIndex"`
+	Scopes           string         `gorm:"type:varchar(128); not null"`
+	ExpiresAt        time.Time      `gorm:"type:DATETIME; not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
+}
+
+func (*v25OAuthToken) TableName() string {
+	return "o_auth_tokens"
+}
+
+// v25 OAuth2 の認可サーバー
+func v25() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "25",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(
+				&v25OAuthClient{},
+				&v25OAuthAuthorizationCode{},
+				&v25OAuthToken{},
+			)
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v26Event struct {
+	ID               uuid.UUID `gorm:"type:char(36); primaryKey"`
+	BlackoutConflict bool      `gorm:"not null; default:false"`
+}
+
+func (*v26Event) TableName() string {
+	return "events"
+}
+
+// v26 使えない時間帯と重なるイベントの記録
+func v26() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "26",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Migrator().AddColumn(&v26Event{}, "blackout_conflict")
+			if err != nil {
+				return err
+			}
+			// 既にある使えない時間帯と重なるイベント
+			return db.Exec(`
+UPDATE events JOIN rooms ON rooms.id = events.room_id
+SET events.blackout_conflict = TRUE
+WHERE events.deleted_at IS NULL AND EXISTS (
+	SELECT 1 FROM room_blackouts
+	WHERE room_blackouts.deleted_at IS NULL
+		AND room_blackouts.time_start < events.time_end AND room_blackouts.time_end > events.time_start
+		AND (room_blackouts.room_id = events.room_id OR (room_blackouts.room_id = ? AND room_blackouts.place = rooms.place))
+)`, uuid.Nil).Error
+		},
+	}
+}
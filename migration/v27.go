@@ -0,0 +1,26 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type v27Room struct {
+	ID             uuid.UUID `gorm:"type:char(36); primaryKey"`
+	SharedCapacity int       `gorm:"not null; default:0"`
+}
+
+func (*v27Room) TableName() string {
+	return "rooms"
+}
+
+// v27 部屋ごとの同時に入れる共用イベントの数
+func v27() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "27",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().AddColumn(&v27Room{}, "shared_capacity")
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v28Oauth2Token 暗号化したトークンは nonce 12byte + トークン + tag 16byte になる
+type v28Oauth2Token struct {
+	AccessToken  string `gorm:"type:varbinary(2048)"`
+	RefreshToken string `gorm:"type:varbinary(2048)"`
+}
+
+type v28Token struct {
+	UserID uuid.UUID `gorm:"type:char(36); primaryKey"`
+
+	*v28Oauth2Token
+}
+
+func (*v28Token) TableName() string {
+	return "tokens"
+}
+
+// v28 refresh token も暗号化して保存する
+func v28() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "28",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Migrator().AlterColumn(&v28Token{}, "access_token")
+			if err != nil {
+				return err
+			}
+			err = db.Migrator().AlterColumn(&v28Token{}, "refresh_token")
+			if err != nil {
+				return err
+			}
+			// 平文で保存された refresh token は復号できないので消し、次のログインで取得し直す
+			return db.Exec("UPDATE tokens SET refresh_token = ''").Error
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v29 ゲストユーザーの名前にしていたメールアドレスを subject に置き換える
+func v29() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "29",
+		Migrate: func(db *gorm.DB) error {
+			// SET は左から順に評価されるので display_name は置き換える前の name と比べる
+			return db.Exec(`
+UPDATE user_profiles JOIN providers ON providers.user_id = user_profiles.user_id
+SET user_profiles.display_name = IF(user_profiles.display_name = user_profiles.name, providers.subject, user_profiles.display_name),
+	user_profiles.name = providers.subject
+WHERE user_profiles.name LIKE '%@%'`).Error
+		},
+	}
+}
@@ -12,8 +12,9 @@ import (
 )
 
 var (
-	verifierCache = cache.New(5*time.Minute, 10*time.Minute)
-	stateCache    = cache.New(5*time.Minute, 10*time.Minute)
+	verifierCache      = cache.New(5*time.Minute, 10*time.Minute)
+	stateCache         = cache.New(5*time.Minute, 10*time.Minute)
+	providerStateCache = cache.New(5*time.Minute, 10*time.Minute)
 )
 
 type AuthParams struct {
@@ -84,3 +85,68 @@ func (h *Handlers) HandleCallback(c echo.Context) error {
 	}
 	return c.Redirect(http.StatusFound, "/callback")
 }
+
+// HandleGetLoginProviders traQ 以外でログインに使えるプロバイダ
+func (h *Handlers) HandleGetLoginProviders(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Service.GetLoginProviders(c.Request().Context()))
+}
+
+// HandlePostProviderAuthParams traQ 以外のプロバイダでログインするゲストユーザー用
+func (h *Handlers) HandlePostProviderAuthParams(c echo.Context) error {
+	ctx := c.Request().Context()
+	url, state, err := h.Service.GetProviderOAuthURL(ctx, c.Param("provider"))
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	sess, err := session.Get("session", c)
+	if err != nil {
+		setMaxAgeMinus(c)
+		return unauthorized(err, needAuthorization(true),
+			message("please try again"))
+	}
+
+	sessionID, ok := sess.Values["ID"].(string)
+	if !ok {
+		sessionID = random.AlphaNumeric(10, true)
+		sess.Values["ID"] = sessionID
+		sess.Options = &h.SessionOption
+		_ = sess.Save(c.Request(), c.Response())
+	}
+	providerStateCache.Set(sessionID, state, cache.DefaultExpiration)
+
+	return c.JSON(http.StatusCreated, &AuthParams{
+		URL: url,
+	})
+}
+
+func (h *Handlers) HandleProviderCallback(c echo.Context) error {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		setMaxAgeMinus(c)
+		return unauthorized(err, needAuthorization(true),
+			message("please try again"))
+	}
+	sessionID, ok := sess.Values["ID"].(string)
+	if !ok {
+		return internalServerError(errors.New("session error"))
+	}
+	state, ok := providerStateCache.Get(sessionID)
+	if !ok {
+		return internalServerError(errors.New("state is not cached"))
+	}
+
+	ctx := c.Request().Context()
+	user, err := h.Service.LoginProviderUser(ctx, c.Param("provider"), c.QueryString(), state.(string))
+	if err != nil {
+		return internalServerError(err)
+	}
+
+	sess.Values["userID"] = user.ID.String()
+	sess.Options = &h.SessionOption
+	err = sess.Save(c.Request(), c.Response())
+	if err != nil {
+		return internalServerError(err)
+	}
+	return c.Redirect(http.StatusFound, "/callback")
+}
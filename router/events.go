@@ -2,7 +2,12 @@ package router
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/traPtitech/knoQ/domain"
 	"github.com/traPtitech/knoQ/domain/filters"
@@ -78,6 +83,14 @@ func (h *Handlers) HandleGetEvent(c echo.Context) error {
 	if err != nil {
 		return judgeErrorResponse(err)
 	}
+	// ゲストユーザーは公開されているか、参加者かグループの viewer 以上であるイベントだけ見られる
+	if isGuest(c) && !event.Open {
+		reqID := c.Get(userIDKey).(uuid.UUID)
+		if !slices.ContainsFunc(event.Attendees, func(a domain.Attendee) bool { return a.UserID == reqID }) &&
+			!h.Service.HasGroupRole(c.Request().Context(), reqID, event.Group.ID, domain.GroupRoleViewer) {
+			return notFound(errors.New("event not found"))
+		}
+	}
 	return c.JSON(http.StatusOK, presentation.ConvdomainEventToEventDetailRes(*event))
 }
 
@@ -116,7 +129,16 @@ func (h *Handlers) HandleGetEventsByGroupID(c echo.Context) error {
 
 	values := c.QueryParams()
 
-	groupExpr := filters.FilterGroupIDs(groupID)
+	groupIDs := []uuid.UUID{groupID}
+	// includeSubgroups=true で子孫のグループのイベントも含める
+	if includeSubgroups, _ := strconv.ParseBool(values.Get("includeSubgroups")); includeSubgroups {
+		subgroupIDs, err := h.Service.GetSubgroupIDs(c.Request().Context(), groupID)
+		if err != nil {
+			return judgeErrorResponse(err)
+		}
+		groupIDs = append(groupIDs, subgroupIDs...)
+	}
+	groupExpr := filters.FilterGroupIDs(groupIDs...)
 
 	durationExpr, err := getDurationFilter(values)
 	if err != nil {
@@ -193,21 +215,18 @@ func (h *Handlers) HandleUpsertMeEventSchedule(c echo.Context) error {
 }
 
 func (h *Handlers) HandleGetMeEvents(c echo.Context) error {
-	userID, err := getRequestUserID(c)
-	if err != nil {
-		return notFound(err)
-	}
+	// 個人用アクセストークンでも使えるように session ではなく認証したユーザーを使う
+	reqID := c.Get(userIDKey).(uuid.UUID)
 
 	values := c.QueryParams()
 
-	relationExpr := getUserRelationFilter(values, userID)
+	relationExpr := getUserRelationFilter(values, reqID)
 
 	durationExpr, err := getDurationFilter(values)
 	if err != nil {
 		return badRequest(err, message("filter duration error"))
 	}
 
-	reqID := c.Get(userIDKey).(uuid.UUID)
 	combinedExpr := filters.AddAnd(relationExpr, durationExpr)
 
 	events, err := h.Service.GetEvents(c.Request().Context(), reqID, combinedExpr)
@@ -254,6 +273,10 @@ func (h *Handlers) HandleGetEventsByRoomID(c echo.Context) error {
 	}
 
 	values := c.QueryParams()
+	expr, err := parsing.Parse(values.Get("q"))
+	if err != nil {
+		return badRequest(err, message("parse error"))
+	}
 
 	roomExpr := filters.FilterRoomIDs(roomID)
 
@@ -264,6 +287,11 @@ func (h *Handlers) HandleGetEventsByRoomID(c echo.Context) error {
 
 	reqID := c.Get(userIDKey).(uuid.UUID)
 	combinedExpr := filters.AddAnd(roomExpr, durationExpr)
+	combinedExpr = filters.AddAnd(combinedExpr, expr)
+	// relation が指定されたときだけ、リクエストしたユーザーとの関係で絞り込む
+	if values.Has("relation") {
+		combinedExpr = filters.AddAnd(combinedExpr, getUserRelationFilter(values, reqID))
+	}
 
 	events, err := h.Service.GetEvents(
 		c.Request().Context(),
@@ -321,7 +349,81 @@ func (h *Handlers) HandleGetiCalByPrivateID(c echo.Context) error {
 		userMap[user.ID] = user
 	}
 
-	cal := presentation.ICalFormat(events, h.Origin, userMap)
+	cal := presentation.ICalFormat(events, nil, h.Origin, userMap)
+	var buf bytes.Buffer
+	_ = cal.SerializeTo(&buf)
+	return c.Blob(http.StatusOK, "text/calendar", buf.Bytes())
+}
+
+// HandleGetiCalByGroupID グループのイベント
+// sessionを持たないリクエストが想定されている
+func (h *Handlers) HandleGetiCalByGroupID(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+
+	ctx := c.Request().Context()
+	events, err := h.Service.GetGroupICalEvents(ctx, groupID, c.QueryParam("token"))
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	users, err := h.Service.GetAllUsers(ctx, false, true)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	cal := presentation.ICalFormat(events, nil, h.Origin, createUserMap(users))
+	var buf bytes.Buffer
+	_ = cal.SerializeTo(&buf)
+	return c.Blob(http.StatusOK, "text/calendar", buf.Bytes())
+}
+
+// HandleGetiCalByRoomPlace 場所ごとの部屋の確保時間とイベント
+// sessionを持たないリクエストが想定されている
+// 期間を指定しない場合は 1ヶ月前から 3ヶ月後まで
+func (h *Handlers) HandleGetiCalByRoomPlace(c echo.Context) error {
+	place, err := url.PathUnescape(c.Param("roomPlace"))
+	if err != nil || place == "" {
+		return notFound(err)
+	}
+	start, end, err := presentation.GetTimeRange(c.QueryParams())
+	if err != nil {
+		return badRequest(err)
+	}
+	now := time.Now()
+	if start.IsZero() {
+		start = now.AddDate(0, -1, 0)
+	}
+	if end.IsZero() {
+		end = now.AddDate(0, 3, 0)
+	}
+
+	ctx := c.Request().Context()
+	rooms, err := h.Service.GetRoomsByPlace(ctx, place, start, end)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	events := []*domain.Event{}
+	if len(rooms) > 0 {
+		roomIDs := make([]uuid.UUID, len(rooms))
+		for i, room := range rooms {
+			roomIDs[i] = room.ID
+		}
+		events, err = h.Service.GetEventsWithGroup(ctx, uuid.Nil, filters.FilterRoomIDs(roomIDs...))
+		if err != nil {
+			return judgeErrorResponse(err)
+		}
+	}
+
+	users, err := h.Service.GetAllUsers(ctx, false, true)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	cal := presentation.ICalPlaceFormat(events, rooms, h.Origin, createUserMap(users))
 	var buf bytes.Buffer
 	_ = cal.SerializeTo(&buf)
 	return c.Blob(http.StatusOK, "text/calendar", buf.Bytes())
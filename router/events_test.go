@@ -0,0 +1,129 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/domain/filters"
+)
+
+type fakeEventService struct {
+	domain.Service
+	event *domain.Event
+	group *domain.Group
+}
+
+func (s *fakeEventService) GetEvent(_ context.Context, eventID uuid.UUID) (*domain.Event, error) {
+	if eventID != s.event.ID {
+		return nil, domain.ErrNotFound
+	}
+	return s.event, nil
+}
+
+func (s *fakeEventService) HasGroupRole(_ context.Context, reqID uuid.UUID, groupID uuid.UUID, role domain.GroupRole) bool {
+	return groupID == s.group.ID && s.group.HasRole(reqID, role)
+}
+
+func TestHandleGetEvent_guest(t *testing.T) {
+	viewer := uuid.Must(uuid.NewV4())
+	attendee := uuid.Must(uuid.NewV4())
+	other := uuid.Must(uuid.NewV4())
+	group := &domain.Group{ID: uuid.Must(uuid.NewV4()), Viewers: []domain.User{{ID: viewer}}}
+
+	tests := []struct {
+		name  string
+		open  bool
+		reqID uuid.UUID
+		want  int
+	}{
+		{"open", true, other, http.StatusOK},
+		{"attendee", false, attendee, http.StatusOK},
+		{"viewer", false, viewer, http.StatusOK},
+		{"other guest", false, other, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.Event{
+				ID:        uuid.Must(uuid.NewV4()),
+				Group:     *group,
+				Open:      tt.open,
+				Attendees: []domain.Attendee{{UserID: attendee, Schedule: domain.Pending}},
+			}
+			h := &Handlers{Service: &fakeEventService{event: event, group: group}}
+			e := echo.New()
+			e.HTTPErrorHandler = HTTPErrorHandler
+			e.GET("/api/events/:eventid", h.HandleGetEvent, func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.Set(userIDKey, tt.reqID)
+					c.Set(guestKey, true)
+					return next(c)
+				}
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+event.ID.String(), nil))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+type fakePlaceICalService struct {
+	domain.Service
+	room       *domain.Room
+	events     []*domain.Event
+	start, end time.Time
+}
+
+func (s *fakePlaceICalService) GetRoomsByPlace(_ context.Context, _ string, start, end time.Time) ([]*domain.Room, error) {
+	s.start, s.end = start, end
+	return []*domain.Room{s.room}, nil
+}
+
+func (s *fakePlaceICalService) GetEventsWithGroup(_ context.Context, _ uuid.UUID, _ filters.Expr) ([]*domain.Event, error) {
+	return s.events, nil
+}
+
+func (s *fakePlaceICalService) GetAllUsers(_ context.Context, _, _ bool) ([]*domain.User, error) {
+	return []*domain.User{}, nil
+}
+
+func TestHandleGetiCalByRoomPlace(t *testing.T) {
+	now := time.Now()
+	room := &domain.Room{ID: uuid.Must(uuid.NewV4()), Place: "S516", TimeStart: now, TimeEnd: now.Add(2 * time.Hour)}
+	event := func(name string, open bool) *domain.Event {
+		return &domain.Event{
+			ID:          uuid.Must(uuid.NewV4()),
+			Name:        name,
+			Description: name + " description",
+			Room:        *room,
+			TimeStart:   now,
+			TimeEnd:     now.Add(time.Hour),
+			Open:        open,
+		}
+	}
+	s := &fakePlaceICalService{room: room, events: []*domain.Event{event("open event", true), event("private event", false)}}
+	h := &Handlers{Service: s}
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.GET("/api/ical/v1/rooms/:roomPlace", h.HandleGetiCalByRoomPlace)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/ical/v1/rooms/S516", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "SUMMARY:open event")
+	assert.NotContains(t, body, "private event")
+	assert.Contains(t, body, "予定あり")
+
+	// 期間を指定しない場合も範囲を限る
+	assert.WithinDuration(t, now.AddDate(0, -1, 0), s.start, time.Minute)
+	assert.WithinDuration(t, now.AddDate(0, 3, 0), s.end, time.Minute)
+}
@@ -0,0 +1,187 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/router/presentation"
+	"github.com/traPtitech/knoQ/utils/tz"
+)
+
+// HandlePostGroupInvitation グループへの招待を作成
+// 宛先がある場合はそのユーザーに知らせる
+func (h *Handlers) HandlePostGroupInvitation(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	var req presentation.GroupInvitationReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err, message(err.Error()))
+	}
+
+	params := presentation.ConvGroupInvitationReqTodomainWriteGroupInvitationParams(req)
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	invitation, err := h.Service.CreateGroupInvitation(ctx, reqID, groupID, params)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	if invitation.Invitee != uuid.Nil {
+		details := []string{
+			fmt.Sprintf("[参加する](%s/groups/join?token=%s)", h.Origin, invitation.Token),
+		}
+		if !invitation.ExpiresAt.IsZero() {
+			details = append(details, fmt.Sprintf("期限: %s", invitation.ExpiresAt.In(tz.JST).Format("01/02(Mon) 15:04")))
+		}
+		h.notifyGroup(ctx, "グループに招待されました", groupID, details, []uuid.UUID{invitation.Invitee})
+	}
+
+	return c.JSON(http.StatusCreated, presentation.ConvdomainGroupInvitationToGroupInvitationRes(*invitation))
+}
+
+// HandleGetGroupInvitations グループへの招待を取得
+func (h *Handlers) HandleGetGroupInvitations(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	invitations, err := h.Service.GetGroupInvitations(ctx, reqID, groupID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainGroupInvitationToSGroupInvitationRes(invitations))
+}
+
+// HandleDeleteGroupInvitation グループへの招待を取り消す
+func (h *Handlers) HandleDeleteGroupInvitation(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	invitationID, err := getPathInvitationID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	if err := h.Service.DeleteGroupInvitation(ctx, reqID, groupID, invitationID); err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleAcceptGroupInvitation 招待を使ってグループに参加
+func (h *Handlers) HandleAcceptGroupInvitation(c echo.Context) error {
+	var req presentation.AcceptGroupInvitationReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err, message(err.Error()))
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	group, err := h.Service.AcceptGroupInvitation(ctx, reqID, req.Token)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainGroupToGroupRes(*group))
+}
+
+// HandlePostGroupJoinRequest グループへの参加を申請し、管理者に知らせる
+func (h *Handlers) HandlePostGroupJoinRequest(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	var req presentation.GroupJoinRequestReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err, message(err.Error()))
+	}
+
+	params := presentation.ConvGroupJoinRequestReqTodomainWriteGroupJoinRequestParams(req)
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	request, err := h.Service.CreateGroupJoinRequest(ctx, reqID, groupID, params)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	group, err := h.Service.GetGroup(ctx, groupID)
+	if err == nil {
+		details := make([]string, 0, 2)
+		if user, err := h.Service.GetUser(ctx, reqID); err == nil {
+			details = append(details, fmt.Sprintf("申請者: %s", user.Name))
+		}
+		if request.Message != "" {
+			details = append(details, fmt.Sprintf("メッセージ: %s", request.Message))
+		}
+		adminIDs := make([]uuid.UUID, 0, len(group.Admins))
+		for _, admin := range group.Admins {
+			adminIDs = append(adminIDs, admin.ID)
+		}
+		h.notifyGroupUsers(ctx, "グループへの参加申請があります", group, details, adminIDs)
+	}
+
+	return c.JSON(http.StatusCreated, presentation.ConvdomainGroupJoinRequestToGroupJoinRequestRes(*request))
+}
+
+// HandleGetGroupJoinRequests グループへの参加申請を取得
+func (h *Handlers) HandleGetGroupJoinRequests(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	requests, err := h.Service.GetGroupJoinRequests(ctx, reqID, groupID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainGroupJoinRequestToSGroupJoinRequestRes(requests))
+}
+
+// HandleApproveGroupJoinRequest 参加申請を承認し、申請者に知らせる
+func (h *Handlers) HandleApproveGroupJoinRequest(c echo.Context) error {
+	return h.handleResolveGroupJoinRequest(c, h.Service.ApproveGroupJoinRequest, "グループへの参加申請が承認されました")
+}
+
+// HandleRejectGroupJoinRequest 参加申請を却下し、申請者に知らせる
+func (h *Handlers) HandleRejectGroupJoinRequest(c echo.Context) error {
+	return h.handleResolveGroupJoinRequest(c, h.Service.RejectGroupJoinRequest, "グループへの参加申請が却下されました")
+}
+
+func (h *Handlers) handleResolveGroupJoinRequest(c echo.Context, resolve func(ctx context.Context, reqID, groupID, requestID uuid.UUID) (*domain.GroupJoinRequest, error), title string) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	requestID, err := getPathRequestID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	request, err := resolve(ctx, reqID, groupID, requestID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	h.notifyGroup(ctx, title, groupID, nil, []uuid.UUID{request.User.ID})
+
+	return c.JSON(http.StatusOK, presentation.ConvdomainGroupJoinRequestToGroupJoinRequestRes(*request))
+}
+
+// notifyGroup グループを取得して notifyGroupUsers で知らせる
+func (h *Handlers) notifyGroup(ctx context.Context, title string, groupID uuid.UUID, details []string, userIDs []uuid.UUID) {
+	group, err := h.Service.GetGroup(ctx, groupID)
+	if err != nil {
+		return
+	}
+	h.notifyGroupUsers(ctx, title, group, details, userIDs)
+}
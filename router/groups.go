@@ -1,9 +1,12 @@
 package router
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
 	"github.com/traPtitech/knoQ/router/presentation"
 
 	"github.com/labstack/echo/v4"
@@ -44,9 +47,11 @@ func (h *Handlers) HandleGetGroup(c echo.Context) error {
 }
 
 // HandleGetGroups グループを取得
+// include-archived=true でアーカイブされたグループも含める
 func (h *Handlers) HandleGetGroups(c echo.Context) error {
+	includeArchived, _ := strconv.ParseBool(c.QueryParam("include-archived"))
 	ctx := c.Request().Context()
-	groups, err := h.Service.GetAllGroups(ctx)
+	groups, err := h.Service.GetAllGroups(ctx, includeArchived)
 	if err != nil {
 		return judgeErrorResponse(err)
 	}
@@ -69,6 +74,30 @@ func (h *Handlers) HandleDeleteGroup(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// HandleArchiveGroup グループをアーカイブ
+func (h *Handlers) HandleArchiveGroup(c echo.Context) error {
+	return h.handleUpdateGroupArchived(c, h.Service.ArchiveGroup)
+}
+
+// HandleUnArchiveGroup グループのアーカイブを解除
+func (h *Handlers) HandleUnArchiveGroup(c echo.Context) error {
+	return h.handleUpdateGroupArchived(c, h.Service.UnArchiveGroup)
+}
+
+func (h *Handlers) handleUpdateGroupArchived(c echo.Context, update func(ctx context.Context, reqID, groupID uuid.UUID) (*domain.Group, error)) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	group, err := update(ctx, reqID, groupID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainGroupToGroupRes(*group))
+}
+
 // HandleUpdateGroup 変更できるものはpostと同等
 func (h *Handlers) HandleUpdateGroup(c echo.Context) error {
 	groupID, err := getPathGroupID(c)
@@ -121,6 +150,133 @@ func (h *Handlers) HandleDeleteMeGroup(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// HandleAddMemberToGroup メンバーを一人追加
+func (h *Handlers) HandleAddMemberToGroup(c echo.Context) error {
+	return h.handleUpdateGroupMembership(c, h.Service.AddMemberToGroup)
+}
+
+// HandleDeleteMemberOfGroup メンバーを一人削除
+func (h *Handlers) HandleDeleteMemberOfGroup(c echo.Context) error {
+	return h.handleUpdateGroupMembership(c, h.Service.DeleteMemberOfGroup)
+}
+
+// HandleAddAdminToGroup 管理者を一人追加
+func (h *Handlers) HandleAddAdminToGroup(c echo.Context) error {
+	return h.handleUpdateGroupMembership(c, h.Service.AddAdminToGroup)
+}
+
+// HandleDeleteAdminOfGroup 管理者を一人削除
+func (h *Handlers) HandleDeleteAdminOfGroup(c echo.Context) error {
+	return h.handleUpdateGroupMembership(c, h.Service.DeleteAdminOfGroup)
+}
+
+// HandleAddOrganizerToGroup organizer を一人追加
+func (h *Handlers) HandleAddOrganizerToGroup(c echo.Context) error {
+	return h.handleUpdateGroupRole(c, domain.GroupRoleOrganizer, h.Service.AddRoleToGroup)
+}
+
+// HandleDeleteOrganizerOfGroup organizer を一人削除
+func (h *Handlers) HandleDeleteOrganizerOfGroup(c echo.Context) error {
+	return h.handleUpdateGroupRole(c, domain.GroupRoleOrganizer, h.Service.DeleteRoleOfGroup)
+}
+
+// HandleAddViewerToGroup viewer を一人追加
+func (h *Handlers) HandleAddViewerToGroup(c echo.Context) error {
+	return h.handleUpdateGroupRole(c, domain.GroupRoleViewer, h.Service.AddRoleToGroup)
+}
+
+// HandleDeleteViewerOfGroup viewer を一人削除
+func (h *Handlers) HandleDeleteViewerOfGroup(c echo.Context) error {
+	return h.handleUpdateGroupRole(c, domain.GroupRoleViewer, h.Service.DeleteRoleOfGroup)
+}
+
+func (h *Handlers) handleUpdateGroupRole(c echo.Context, role domain.GroupRole, update func(ctx context.Context, reqID, groupID, userID uuid.UUID, role domain.GroupRole) (*domain.Group, error)) error {
+	return h.handleUpdateGroupMembership(c, func(ctx context.Context, reqID, groupID, userID uuid.UUID) (*domain.Group, error) {
+		return update(ctx, reqID, groupID, userID, role)
+	})
+}
+
+func (h *Handlers) handleUpdateGroupMembership(c echo.Context, update func(ctx context.Context, reqID, groupID, userID uuid.UUID) (*domain.Group, error)) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	userID, err := getPathUserID(c)
+	if err != nil {
+		return notFound(err, message(err.Error()))
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	group, err := update(ctx, reqID, groupID, userID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainGroupToGroupRes(*group))
+}
+
+// HandleGetGroupAuditLogs メンバーと管理者の変更履歴を取得
+func (h *Handlers) HandleGetGroupAuditLogs(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	logs, err := h.Service.GetGroupAuditLogs(ctx, reqID, groupID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainGroupAuditLogToSGroupAuditLogRes(logs))
+}
+
+// HandleGetGroupICalTokens グループの iCal のトークンを取得
+func (h *Handlers) HandleGetGroupICalTokens(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	tokens, err := h.Service.GetGroupICalTokens(ctx, reqID, groupID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainGroupICalTokenToSGroupICalTokenRes(tokens))
+}
+
+// HandlePostGroupICalToken グループの iCal のトークンを発行
+func (h *Handlers) HandlePostGroupICalToken(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	token, err := h.Service.CreateGroupICalToken(ctx, reqID, groupID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusCreated, presentation.ConvdomainGroupICalTokenToGroupICalTokenRes(*token))
+}
+
+// HandleDeleteGroupICalToken グループの iCal のトークンを無効にする
+func (h *Handlers) HandleDeleteGroupICalToken(c echo.Context) error {
+	groupID, err := getPathGroupID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	tokenID, err := getPathTokenID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	if err := h.Service.DeleteGroupICalToken(ctx, reqID, groupID, tokenID); err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (h *Handlers) HandleGetMeGroupIDs(c echo.Context) error {
 	userID, _ := getRequestUserID(c)
 
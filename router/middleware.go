@@ -1,10 +1,13 @@
 package router
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/traPtitech/knoQ/domain"
@@ -82,32 +85,105 @@ func ServerVersionMiddleware(version string) echo.MiddlewareFunc {
 
 const (
 	userIDKey string = "userID"
+	guestKey  string = "guest"
 )
 
 func setUserID(c echo.Context, userID uuid.UUID) {
 	c.Set(userIDKey, userID)
 }
 
-// TraQUserMiddleware traQユーザーか判定するミドルウェア
-// TODO funcname fix
-func (h *Handlers) TraQUserMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		userID, err := getRequestUserID(c)
+// isGuest リクエストしたユーザーがゲストユーザーか
+func isGuest(c echo.Context) bool {
+	guest, _ := c.Get(guestKey).(bool)
+	return guest
+}
+
+// authenticateUser session かアクセストークンのユーザーを確認して userID を設定する
+func (h *Handlers) authenticateUser(c echo.Context) (*domain.User, error) {
+	var userID uuid.UUID
+	if token, ok := getBearerToken(c); ok {
+		var t scopedToken
+		var err error
+		userID, t, err = h.authenticateBearerToken(c.Request().Context(), token)
+		if err != nil {
+			return nil, judgeErrorResponse(err)
+		}
+		scope, ok := accessTokenScopes[c.Request().Method+" "+c.Path()]
+		if !ok || !t.HasScope(scope) {
+			return nil, forbidden(
+				errors.New("insufficient scope"),
+				message("This token cannot request."),
+				specification(fmt.Sprintf("Scope %q is required.", scope)),
+			)
+		}
+	} else {
+		var err error
+		userID, err = getRequestUserID(c)
 		if err != nil || userID == uuid.Nil {
-			return unauthorized(err, needAuthorization(true))
+			return nil, unauthorized(err, needAuthorization(true))
 		}
+	}
 
-		setUserID(c, userID)
+	setUserID(c, userID)
 
-		ctx := c.Request().Context()
-		user, err := h.Service.GetUserMe(ctx, userID)
-		if err != nil {
-			return internalServerError(err)
+	ctx := c.Request().Context()
+	user, err := h.Service.GetUserMe(ctx, userID)
+	if err != nil {
+		return nil, internalServerError(err)
+	}
+
+	// state check
+	if user.State != 1 {
+		return nil, forbidden(errors.New("invalid user"))
+	}
+	c.Set(guestKey, user.Guest)
+	return user, nil
+}
+
+// scopedToken 個人用アクセストークンか OAuth のアクセストークン
+type scopedToken interface {
+	HasScope(scope domain.AccessTokenScope) bool
+}
+
+// authenticateBearerToken 個人用アクセストークンでなければ OAuth のアクセストークンとして確認する
+func (h *Handlers) authenticateBearerToken(ctx context.Context, token string) (uuid.UUID, scopedToken, error) {
+	pat, err := h.Service.AuthenticatePersonalAccessToken(ctx, token)
+	if err == nil {
+		return pat.UserID, pat, nil
+	}
+	if !errors.Is(err, domain.ErrUnAuthorized) {
+		return uuid.Nil, nil, err
+	}
+	t, err := h.Service.AuthenticateOAuthToken(ctx, token)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return t.UserID, t, nil
+}
+
+// UserMiddleware ログインしているユーザーか判定するミドルウェア。ゲストユーザーも通す
+func (h *Handlers) UserMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, err := h.authenticateUser(c); err != nil {
+			return err
 		}
+		return next(c)
+	}
+}
 
-		// state check
-		if user.State != 1 {
-			return forbidden(errors.New("invalid user"))
+// TraQUserMiddleware traQユーザーか判定するミドルウェア
+// TODO funcname fix
+func (h *Handlers) TraQUserMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, err := h.authenticateUser(c)
+		if err != nil {
+			return err
+		}
+		if user.Guest {
+			return forbidden(
+				errors.New("guest user"),
+				message("Guest users cannot request."),
+			)
 		}
 
 		return next(c)
@@ -132,23 +208,25 @@ func (h *Handlers) PrivilegeUserMiddleware(next echo.HandlerFunc) echo.HandlerFu
 	}
 }
 
-// GroupAdminsMiddleware グループ管理ユーザーか判定するミドルウェア
-func (h *Handlers) GroupAdminsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		groupID, err := getPathGroupID(c)
-		if err != nil {
-			return notFound(err)
-		}
-		ctx := c.Request().Context()
-		reqID := c.Get(userIDKey).(uuid.UUID)
-		if !h.Service.IsGroupAdmins(ctx, reqID, groupID) {
-			return forbidden(
-				errors.New("not createdBy"),
-				message("You are not user by whom this group is created."),
-				specification("Only the author can request."),
-			)
+// GroupRoleMiddleware グループで role 以上の役割を持つか判定するミドルウェア
+func (h *Handlers) GroupRoleMiddleware(role domain.GroupRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			groupID, err := getPathGroupID(c)
+			if err != nil {
+				return notFound(err)
+			}
+			ctx := c.Request().Context()
+			reqID := c.Get(userIDKey).(uuid.UUID)
+			if !h.Service.HasGroupRole(ctx, reqID, groupID, role) {
+				return forbidden(
+					errors.New("not "+string(role)),
+					message(fmt.Sprintf("You are not %s of this group.", role)),
+					specification(fmt.Sprintf("Only %s or higher role can request.", role)),
+				)
+			}
+			return next(c)
 		}
-		return next(c)
 	}
 }
 
@@ -234,7 +312,8 @@ func (h *Handlers) WebhookEventHandler(c echo.Context, _, resBody []byte) {
 		for _, attendee := range e.Attendees {
 			if attendee.Schedule == presentation.Pending {
 				user, ok := usersMap[attendee.ID]
-				if ok {
+				// ゲストユーザーは traQ でメンションできない
+				if ok && !user.Guest {
 					notificationTargets = append(notificationTargets, user.Name)
 				}
 			}
@@ -243,7 +322,13 @@ func (h *Handlers) WebhookEventHandler(c echo.Context, _, resBody []byte) {
 
 	content := presentation.GenerateEventWebhookContent(c.Request().Method, e, notificationTargets, h.Origin, !domain.DEVELOPMENT)
 
-	_ = utils.RequestWebhook(content, h.WebhookSecret, h.ActivityChannelID, h.WebhookID, 1)
+	_ = utils.RequestWebhook(h.TraQAPI, content, h.WebhookSecret, h.ActivityChannelID, h.WebhookID, 1)
+}
+
+// getBearerToken Authorization ヘッダーの Bearer トークン
+func getBearerToken(c echo.Context) (string, bool) {
+	token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	return token, ok && token != ""
 }
 
 // getRequestUserID sessionからuserを返します
@@ -284,6 +369,51 @@ func getPathRoomID(c echo.Context) (uuid.UUID, error) {
 	return roomID, nil
 }
 
+// getPathBlackoutID :blackoutidを返します
+func getPathBlackoutID(c echo.Context) (uuid.UUID, error) {
+	blackoutID, err := uuid.FromString(c.Param("blackoutid"))
+	if err != nil {
+		return uuid.Nil, errors.New("BlackoutID is not uuid")
+	}
+	return blackoutID, nil
+}
+
+// getPathSeriesID :seriesidを返します
+func getPathSeriesID(c echo.Context) (uuid.UUID, error) {
+	seriesID, err := uuid.FromString(c.Param("seriesid"))
+	if err != nil {
+		return uuid.Nil, errors.New("SeriesID is not uuid")
+	}
+	return seriesID, nil
+}
+
+// getPathInvitationID :invitationidを返します
+func getPathInvitationID(c echo.Context) (uuid.UUID, error) {
+	invitationID, err := uuid.FromString(c.Param("invitationid"))
+	if err != nil {
+		return uuid.Nil, errors.New("InvitationID is not uuid")
+	}
+	return invitationID, nil
+}
+
+// getPathRequestID :requestidを返します
+func getPathRequestID(c echo.Context) (uuid.UUID, error) {
+	requestID, err := uuid.FromString(c.Param("requestid"))
+	if err != nil {
+		return uuid.Nil, errors.New("RequestID is not uuid")
+	}
+	return requestID, nil
+}
+
+// getPathTokenID :tokenidを返します
+func getPathTokenID(c echo.Context) (uuid.UUID, error) {
+	tokenID, err := uuid.FromString(c.Param("tokenid"))
+	if err != nil {
+		return uuid.Nil, errors.New("TokenID is not uuid")
+	}
+	return tokenID, nil
+}
+
 // getPathUserID :useridを返します
 func getPathUserID(c echo.Context) (uuid.UUID, error) {
 	userID, err := uuid.FromString(c.Param("userid"))
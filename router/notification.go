@@ -0,0 +1,60 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/router/presentation"
+	"github.com/traPtitech/knoQ/utils"
+	"go.uber.org/zap"
+)
+
+// notifyAffectedEvents 影響を受けるイベントの管理者に activity チャンネルで知らせる
+// 終わったイベントは知らせない
+func (h *Handlers) notifyAffectedEvents(ctx context.Context, title string, details []string, events []*domain.Event) {
+	futureEvents := make([]*domain.Event, 0, len(events))
+	for _, e := range events {
+		if e.TimeEnd.After(time.Now()) {
+			futureEvents = append(futureEvents, e)
+		}
+	}
+	if len(futureEvents) == 0 {
+		return
+	}
+
+	users, err := h.Service.GetAllUsers(ctx, false, true)
+	if err != nil {
+		h.Logger.Error("failed to get users", zap.Error(err))
+		return
+	}
+	content := presentation.GenerateAffectedEventsWebhookContent(title, details, futureEvents, createUserMap(users), h.Origin, !domain.DEVELOPMENT)
+	if err := utils.RequestWebhook(h.TraQAPI, content, h.WebhookSecret, h.ActivityChannelID, h.WebhookID, 1); err != nil {
+		h.Logger.Error("failed to send webhook", zap.Error(err))
+	}
+}
+
+// notifyGroupUsers グループの招待や参加申請を userIDs のユーザーに activity チャンネルで知らせる
+func (h *Handlers) notifyGroupUsers(ctx context.Context, title string, group *domain.Group, details []string, userIDs []uuid.UUID) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	users, err := h.Service.GetAllUsers(ctx, false, true)
+	if err != nil {
+		h.Logger.Error("failed to get users", zap.Error(err))
+		return
+	}
+	userMap := createUserMap(users)
+	targets := make([]*domain.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		if user, ok := userMap[id]; ok && !user.Guest {
+			targets = append(targets, user)
+		}
+	}
+	content := presentation.GenerateGroupWebhookContent(title, group, details, targets, h.Origin, !domain.DEVELOPMENT)
+	if err := utils.RequestWebhook(h.TraQAPI, content, h.WebhookSecret, h.ActivityChannelID, h.WebhookID, 1); err != nil {
+		h.Logger.Error("failed to send webhook", zap.Error(err))
+	}
+}
@@ -0,0 +1,148 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/router/presentation"
+)
+
+// HandleGetOAuthClients 登録されている OAuth クライアント。client_secret は返さない
+func (h *Handlers) HandleGetOAuthClients(c echo.Context) error {
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	clients, err := h.Service.GetOAuthClients(c.Request().Context(), reqID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainOAuthClientToSOAuthClientRes(clients))
+}
+
+// HandlePostOAuthClient OAuth クライアントを登録
+func (h *Handlers) HandlePostOAuthClient(c echo.Context) error {
+	var req presentation.OAuthClientReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	client, err := h.Service.CreateOAuthClient(ctx, reqID, presentation.ConvOAuthClientReqTodomainWriteOAuthClientParams(req))
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusCreated, presentation.ConvdomainOAuthClientToOAuthClientRes(*client))
+}
+
+// HandleDeleteOAuthClient OAuth クライアントと発行したトークンを削除
+func (h *Handlers) HandleDeleteOAuthClient(c echo.Context) error {
+	clientID, err := uuid.FromString(c.Param("clientid"))
+	if err != nil {
+		return notFound(err)
+	}
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	if err := h.Service.DeleteOAuthClient(c.Request().Context(), reqID, clientID); err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func bindOAuthAuthorizeParams(c echo.Context) (domain.OAuthAuthorizeParams, error) {
+	var req presentation.OAuthAuthorizeReq
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return domain.OAuthAuthorizeParams{}, err
+	}
+	return presentation.ConvOAuthAuthorizeReqTodomainOAuthAuthorizeParams(req), nil
+}
+
+// HandleGetOAuthAuthorize 認可リクエストを確認して同意画面の内容を返す
+func (h *Handlers) HandleGetOAuthAuthorize(c echo.Context) error {
+	params, err := bindOAuthAuthorizeParams(c)
+	if err != nil {
+		return badRequest(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	consent, err := h.Service.GetOAuthConsent(ctx, reqID, params)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainOAuthConsentToOAuthConsentRes(*consent))
+}
+
+// HandlePostOAuthAuthorize 同意の結果を受け取り、クライアントへのリダイレクト先を返す
+func (h *Handlers) HandlePostOAuthAuthorize(c echo.Context) error {
+	params, err := bindOAuthAuthorizeParams(c)
+	if err != nil {
+		return badRequest(err)
+	}
+	var req presentation.OAuthAuthorizeDecisionReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	redirectURI, err := h.Service.AuthorizeOAuthClient(ctx, reqID, params, req.Approved)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.OAuthAuthorizeRes{RedirectURI: redirectURI})
+}
+
+// getOAuthClientCredentials Basic 認証かフォームの client_id, client_secret
+func getOAuthClientCredentials(c echo.Context) (uuid.UUID, string) {
+	clientID, clientSecret, ok := c.Request().BasicAuth()
+	if !ok {
+		clientID, clientSecret = c.FormValue("client_id"), c.FormValue("client_secret")
+	}
+	return uuid.FromStringOrNil(clientID), clientSecret
+}
+
+// oauthErrorResponse RFC 6749 5.2 の形式でエラーを返す
+func oauthErrorResponse(c echo.Context, err error) error {
+	var oe *domain.OAuthError
+	if !errors.As(err, &oe) {
+		return internalServerError(err)
+	}
+	code := http.StatusBadRequest
+	if oe.Code == "invalid_client" {
+		code = http.StatusUnauthorized
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="knoQ"`)
+	}
+	c.Set("Error", err)
+	return c.JSON(code, presentation.OAuthErrorRes{
+		Error:            oe.Code,
+		ErrorDescription: oe.Description,
+	})
+}
+
+// HandlePostOAuthToken トークンエンドポイント。RFC 6749 4.1.3, 6
+func (h *Handlers) HandlePostOAuthToken(c echo.Context) error {
+	clientID, clientSecret := getOAuthClientCredentials(c)
+	token, err := h.Service.ExchangeOAuthToken(c.Request().Context(), domain.OAuthTokenRequest{
+		GrantType:    c.FormValue("grant_type"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         c.FormValue("code"),
+		RedirectURI:  c.FormValue("redirect_uri"),
+		CodeVerifier: c.FormValue("code_verifier"),
+		RefreshToken: c.FormValue("refresh_token"),
+	})
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainOAuthTokenToOAuthTokenRes(*token, time.Now()))
+}
+
+// HandlePostOAuthIntrospect トークンイントロスペクション。RFC 7662
+func (h *Handlers) HandlePostOAuthIntrospect(c echo.Context) error {
+	clientID, clientSecret := getOAuthClientCredentials(c)
+	token, err := h.Service.IntrospectOAuthToken(c.Request().Context(), clientID, clientSecret, c.FormValue("token"))
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainOAuthTokenToOAuthIntrospectionRes(token))
+}
@@ -0,0 +1,131 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/router/presentation"
+)
+
+type fakeOAuthService struct {
+	domain.Service
+	clientID uuid.UUID
+	secret   string
+	token    *domain.OAuthToken
+}
+
+func (s *fakeOAuthService) ExchangeOAuthToken(_ context.Context, req domain.OAuthTokenRequest) (*domain.OAuthToken, error) {
+	if req.ClientID != s.clientID || req.ClientSecret != s.secret {
+		return nil, &domain.OAuthError{Code: "invalid_client"}
+	}
+	if req.GrantType != "authorization_code" || req.Code != "code" {
+		return nil, &domain.OAuthError{Code: "invalid_grant"}
+	}
+	return s.token, nil
+}
+
+func (s *fakeOAuthService) IntrospectOAuthToken(_ context.Context, clientID uuid.UUID, clientSecret, token string) (*domain.OAuthToken, error) {
+	if clientID != s.clientID || clientSecret != s.secret {
+		return nil, &domain.OAuthError{Code: "invalid_client"}
+	}
+	if token != s.token.AccessToken {
+		return nil, nil
+	}
+	return s.token, nil
+}
+
+func TestOAuthTokenEndpoints(t *testing.T) {
+	clientID := uuid.Must(uuid.NewV4())
+	token := &domain.OAuthToken{
+		ClientID:     clientID,
+		UserID:       uuid.Must(uuid.NewV4()),
+		Scopes:       []domain.AccessTokenScope{domain.AccessTokenScopeReadEvents, domain.AccessTokenScopeRSVP},
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	h := &Handlers{Service: &fakeOAuthService{clientID: clientID, secret: "secret", token: token}}
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.POST("/api/oauth2/token", h.HandlePostOAuthToken)
+	e.POST("/api/oauth2/introspect", h.HandlePostOAuthIntrospect)
+
+	post := func(path string, form url.Values, basicAuth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		if basicAuth {
+			req.SetBasicAuth(clientID.String(), "secret")
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("exchange code", func(t *testing.T) {
+		rec := post("/api/oauth2/token", url.Values{"grant_type": {"authorization_code"}, "code": {"code"}}, true)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+		var res presentation.OAuthTokenRes
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "access", res.AccessToken)
+		assert.Equal(t, "Bearer", res.TokenType)
+		assert.Equal(t, "read:events rsvp", res.Scope)
+	})
+
+	t.Run("client credentials in form", func(t *testing.T) {
+		rec := post("/api/oauth2/token", url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {"code"},
+			"client_id":     {clientID.String()},
+			"client_secret": {"secret"},
+		}, false)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("oauth errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			form      url.Values
+			basicAuth bool
+			want      int
+			wantError string
+		}{
+			{"invalid client", url.Values{"grant_type": {"authorization_code"}, "code": {"code"}}, false, http.StatusUnauthorized, "invalid_client"},
+			{"invalid grant", url.Values{"grant_type": {"authorization_code"}, "code": {"used"}}, true, http.StatusBadRequest, "invalid_grant"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := post("/api/oauth2/token", tt.form, tt.basicAuth)
+				assert.Equal(t, tt.want, rec.Code)
+				var res presentation.OAuthErrorRes
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				assert.Equal(t, tt.wantError, res.Error)
+			})
+		}
+	})
+
+	t.Run("introspect", func(t *testing.T) {
+		rec := post("/api/oauth2/introspect", url.Values{"token": {"access"}}, true)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var res map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, true, res["active"])
+		assert.Equal(t, token.UserID.String(), res["sub"])
+		assert.Equal(t, clientID.String(), res["client_id"])
+
+		rec = post("/api/oauth2/introspect", url.Values{"token": {"unknown"}}, true)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"active": false}`, rec.Body.String())
+	})
+}
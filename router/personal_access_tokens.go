@@ -0,0 +1,67 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/router/presentation"
+)
+
+// accessTokenScopes 個人用アクセストークンや OAuth のアクセストークンで使えるルートと必要なスコープ
+// ここに無いルートはトークンでは使えない
+var accessTokenScopes = map[string]domain.AccessTokenScope{
+	"GET /api/events":                           domain.AccessTokenScopeReadEvents,
+	"GET /api/events/:eventid":                  domain.AccessTokenScopeReadEvents,
+	"GET /api/users/me/events":                  domain.AccessTokenScopeReadEvents,
+	"GET /api/users/:userid/events":             domain.AccessTokenScopeReadEvents,
+	"GET /api/rooms/:roomid/events":             domain.AccessTokenScopeReadEvents,
+	"GET /api/groups/:groupid/events":           domain.AccessTokenScopeReadEvents,
+	"POST /api/events":                          domain.AccessTokenScopeWriteEvents,
+	"PUT /api/events/:eventid":                  domain.AccessTokenScopeWriteEvents,
+	"DELETE /api/events/:eventid":               domain.AccessTokenScopeWriteEvents,
+	"POST /api/events/:eventid/tags":            domain.AccessTokenScopeWriteEvents,
+	"DELETE /api/events/:eventid/tags/:tagName": domain.AccessTokenScopeWriteEvents,
+	"PUT /api/events/:eventid/attendees/me":     domain.AccessTokenScopeRSVP,
+}
+
+// HandleGetMyPersonalAccessTokens 自分の個人用アクセストークンを取得。トークンの値は返さない
+func (h *Handlers) HandleGetMyPersonalAccessTokens(c echo.Context) error {
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	tokens, err := h.Service.GetMyPersonalAccessTokens(ctx, reqID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainPersonalAccessTokenToSPersonalAccessTokenRes(tokens))
+}
+
+// HandlePostPersonalAccessToken 個人用アクセストークンを発行
+func (h *Handlers) HandlePostPersonalAccessToken(c echo.Context) error {
+	var req presentation.PersonalAccessTokenReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	token, err := h.Service.CreatePersonalAccessToken(ctx, reqID, presentation.ConvPersonalAccessTokenReqTodomainWritePersonalAccessTokenParams(req))
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusCreated, presentation.ConvdomainPersonalAccessTokenToPersonalAccessTokenRes(*token))
+}
+
+// HandleDeletePersonalAccessToken 個人用アクセストークンを無効にする
+func (h *Handlers) HandleDeletePersonalAccessToken(c echo.Context) error {
+	tokenID, err := getPathTokenID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	if err := h.Service.RevokePersonalAccessToken(ctx, reqID, tokenID); err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+type fakePersonalAccessTokenService struct {
+	domain.Service
+	tokens      map[string]*domain.PersonalAccessToken
+	oauthTokens map[string]*domain.OAuthToken
+}
+
+func (s *fakePersonalAccessTokenService) AuthenticatePersonalAccessToken(_ context.Context, token string) (*domain.PersonalAccessToken, error) {
+	t, ok := s.tokens[token]
+	if !ok {
+		return nil, domain.ErrUnAuthorized
+	}
+	return t, nil
+}
+
+func (s *fakePersonalAccessTokenService) AuthenticateOAuthToken(_ context.Context, token string) (*domain.OAuthToken, error) {
+	t, ok := s.oauthTokens[token]
+	if !ok {
+		return nil, domain.ErrUnAuthorized
+	}
+	return t, nil
+}
+
+func (s *fakePersonalAccessTokenService) GetUserMe(_ context.Context, reqID uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: reqID, State: 1}, nil
+}
+
+func TestPersonalAccessTokenMiddleware(t *testing.T) {
+	userID := uuid.Must(uuid.NewV4())
+	h := &Handlers{Service: &fakePersonalAccessTokenService{
+		tokens: map[string]*domain.PersonalAccessToken{
+			"read": {UserID: userID, Scopes: []domain.AccessTokenScope{domain.AccessTokenScopeReadEvents}},
+			"rsvp": {UserID: userID, Scopes: []domain.AccessTokenScope{domain.AccessTokenScopeRSVP}},
+		},
+		oauthTokens: map[string]*domain.OAuthToken{
+			"oauth-rsvp": {UserID: userID, Scopes: []domain.AccessTokenScope{domain.AccessTokenScopeRSVP}},
+		},
+	}}
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Use(session.Middleware(sessions.NewCookieStore([]byte("secret"))))
+	ok := func(c echo.Context) error {
+		assert.Equal(t, userID, c.Get(userIDKey))
+		return c.NoContent(http.StatusOK)
+	}
+	api := e.Group("/api", h.TraQUserMiddleware)
+	api.GET("/events/:eventid", ok)
+	api.PUT("/events/:eventid/attendees/me", ok)
+	api.GET("/users/me/tokens", ok)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"read events", http.MethodGet, "/api/events/" + uuid.Must(uuid.NewV4()).String(), "read", http.StatusOK},
+		{"rsvp", http.MethodPut, "/api/events/" + uuid.Must(uuid.NewV4()).String() + "/attendees/me", "rsvp", http.StatusOK},
+		{"oauth rsvp", http.MethodPut, "/api/events/" + uuid.Must(uuid.NewV4()).String() + "/attendees/me", "oauth-rsvp", http.StatusOK},
+		{"oauth missing scope", http.MethodGet, "/api/events/" + uuid.Must(uuid.NewV4()).String(), "oauth-rsvp", http.StatusForbidden},
+		{"missing scope", http.MethodPut, "/api/events/" + uuid.Must(uuid.NewV4()).String() + "/attendees/me", "read", http.StatusForbidden},
+		{"route without scope", http.MethodGet, "/api/users/me/tokens", "read", http.StatusForbidden},
+		{"unknown token", http.MethodGet, "/api/events/" + uuid.Must(uuid.NewV4()).String(), "unknown", http.StatusUnauthorized},
+		{"no token and no session", http.MethodGet, "/api/events/" + uuid.Must(uuid.NewV4()).String(), "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
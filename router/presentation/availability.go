@@ -0,0 +1,76 @@
+package presentation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/traPtitech/knoQ/utils"
+	"github.com/traPtitech/knoQ/utils/tz"
+)
+
+type PeriodRes struct {
+	Name string `json:"name"`
+	// Start "08:50" の形式
+	Start          string `json:"start"`
+	DisplayDefault bool   `json:"displayDefault"`
+}
+
+type RoomAvailabilityRes struct {
+	Periods []PeriodRes              `json:"periods"`
+	Days    []DayRoomAvailabilityRes `json:"days"`
+}
+
+type DayRoomAvailabilityRes struct {
+	Date   string                     `json:"date"`
+	Places []PlaceRoomAvailabilityRes `json:"places"`
+}
+
+type PlaceRoomAvailabilityRes struct {
+	Place   string                      `json:"place"`
+	Periods []PeriodRoomAvailabilityRes `json:"periods"`
+}
+
+// PeriodRoomAvailabilityRes Status は "full", "partial", "none" のいずれか
+type PeriodRoomAvailabilityRes struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	TimeStart *time.Time `json:"timeStart,omitempty"`
+	TimeEnd   *time.Time `json:"timeEnd,omitempty"`
+}
+
+func ConvSPeriodToSPeriodRes(src []utils.Period) (dst []PeriodRes) {
+	dst = make([]PeriodRes, len(src))
+	for i, p := range src {
+		dst[i] = PeriodRes{
+			Name:           p.Name,
+			Start:          fmt.Sprintf("%02d:%02d", int(p.Start.Hours()), int(p.Start.Minutes())%60),
+			DisplayDefault: p.DisplayDefault,
+		}
+	}
+	return
+}
+
+func ConvSPlaceAvailabilityToDayRoomAvailabilityRes(src []utils.PlaceAvailability, periods []utils.Period, date time.Time) (dst DayRoomAvailabilityRes) {
+	dst.Date = date.In(tz.JST).Format("2006-01-02")
+	dst.Places = make([]PlaceRoomAvailabilityRes, len(src))
+	for i, place := range src {
+		dst.Places[i].Place = place.Place
+		dst.Places[i].Periods = make([]PeriodRoomAvailabilityRes, len(place.Usages))
+		for j, usage := range place.Usages {
+			p := PeriodRoomAvailabilityRes{
+				Name:   periods[j].Name,
+				Status: "none",
+			}
+			if usage != nil {
+				p.Status = "partial"
+				if usage.Full {
+					p.Status = "full"
+				}
+				p.TimeStart = &usage.TimeStart
+				p.TimeEnd = &usage.TimeEnd
+			}
+			dst.Places[i].Periods[j] = p
+		}
+	}
+	return
+}
@@ -132,6 +132,7 @@ func ConvdomainEventToEventRes(src domain.Event) (dst EventRes) {
 	for i := range src.Attendees {
 		dst.Attendees[i] = convdomainAttendeeToEventAttendeeRes(src.Attendees[i])
 	}
+	dst.BlackoutConflict = src.BlackoutConflict
 	dst.Model = Model(src.Model)
 	return
 }
@@ -141,6 +142,16 @@ func ConvdomainGroupToGroupRes(src domain.Group) (dst GroupRes) {
 	dst.Name = src.Name
 	dst.Description = src.Description
 	dst.JoinFreely = src.JoinFreely
+	dst.Archived = src.Archived
+	dst.ParentID = src.ParentID
+	dst.Organizers = make([]uuid.UUID, len(src.Organizers))
+	for i := range src.Organizers {
+		dst.Organizers[i] = convdomainUserTouuidUUID(src.Organizers[i])
+	}
+	dst.Viewers = make([]uuid.UUID, len(src.Viewers))
+	for i := range src.Viewers {
+		dst.Viewers[i] = convdomainUserTouuidUUID(src.Viewers[i])
+	}
 	dst.Members = make([]uuid.UUID, len(src.Members))
 	for i := range src.Members {
 		dst.Members[i] = convdomainUserTouuidUUID(src.Members[i])
@@ -170,6 +181,7 @@ func ConvdomainUserToUserRes(src domain.User) (dst UserRes) {
 		Icon:        src.Icon,
 		Privileged:  src.Privileged,
 		State:       src.State,
+		Guest:       src.Guest,
 	}
 	return
 }
@@ -192,6 +204,16 @@ func convdomainGroupToGroupRes(src domain.Group) (dst GroupRes) {
 	dst.Name = src.Name
 	dst.Description = src.Description
 	dst.JoinFreely = src.JoinFreely
+	dst.Archived = src.Archived
+	dst.ParentID = src.ParentID
+	dst.Organizers = make([]uuid.UUID, len(src.Organizers))
+	for i := range src.Organizers {
+		dst.Organizers[i] = convdomainUserTouuidUUID(src.Organizers[i])
+	}
+	dst.Viewers = make([]uuid.UUID, len(src.Viewers))
+	for i := range src.Viewers {
+		dst.Viewers[i] = convdomainUserTouuidUUID(src.Viewers[i])
+	}
 	dst.Members = make([]uuid.UUID, len(src.Members))
 	for i := range src.Members {
 		dst.Members[i] = convdomainUserTouuidUUID(src.Members[i])
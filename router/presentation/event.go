@@ -69,6 +69,10 @@ type EventDetailRes struct {
 	AllowTogether bool               `json:"sharedRoom"`
 	Open          bool               `json:"open"`
 	Attendees     []EventAttendeeRes `json:"attendees"`
+	// CapacityExceeded 欠席以外の参加者数が部屋の座席数を超えている
+	CapacityExceeded bool `json:"capacityExceeded" cvt:"-"`
+	// BlackoutConflict 後から設定された使えない時間帯と重なっている
+	BlackoutConflict bool `json:"blackoutConflict"`
 	Model
 }
 
@@ -103,6 +107,8 @@ type EventRes struct {
 	CreatedBy     uuid.UUID          `json:"createdBy"`
 	Open          bool               `json:"open"`
 	Attendees     []EventAttendeeRes `json:"attendees"`
+	// BlackoutConflict 後から設定された使えない時間帯と重なっている
+	BlackoutConflict bool `json:"blackoutConflict"`
 	Model
 }
 
@@ -161,7 +167,73 @@ func iCalVeventFormat(e *domain.Event, host string, userMap map[uuid.UUID]*domai
 	return vevent
 }
 
-func ICalFormat(events []*domain.Event, host string, userMap map[uuid.UUID]*domain.User) *ics.Calendar {
+// iCalRoomVeventFormat 部屋の確保時間を他の予定を妨げない VEVENT にする
+func iCalRoomVeventFormat(r *domain.Room) *ics.VEvent {
+	vevent := ics.NewEvent("room-" + r.ID.String())
+	vevent.SetDtStampTime(time.Now().UTC())
+	vevent.SetStartAt(r.TimeStart.UTC())
+	vevent.SetEndAt(r.TimeEnd.UTC())
+	vevent.SetCreatedTime(r.CreatedAt.UTC())
+	vevent.SetModifiedAt(r.UpdatedAt.UTC())
+	vevent.SetSummary(fmt.Sprintf("%s (部屋確保)", r.Place))
+	vevent.SetTimeTransparency(ics.TransparencyTransparent)
+
+	timeFormat := "15:04"
+	description := "空いている時間帯\n"
+	for _, t := range r.CalcAvailableTime(false) {
+		description += fmt.Sprintf("%s ~ %s\n", t.TimeStart.In(tz.JST).Format(timeFormat), t.TimeEnd.In(tz.JST).Format(timeFormat))
+	}
+	description += "\n"
+	description += "併用すれば使える時間帯\n"
+	for _, t := range r.CalcAvailableTime(true) {
+		description += fmt.Sprintf("%s ~ %s\n", t.TimeStart.In(tz.JST).Format(timeFormat), t.TimeEnd.In(tz.JST).Format(timeFormat))
+	}
+	vevent.SetDescription(description)
+	vevent.SetLocation(r.Place)
+	return vevent
+}
+
+// iCalBusyVeventFormat 公開されていないイベントを、内容を含まない時間帯だけの VEVENT にする
+func iCalBusyVeventFormat(e *domain.Event) *ics.VEvent {
+	vevent := ics.NewEvent(e.ID.String())
+	vevent.SetDtStampTime(time.Now().UTC())
+	vevent.SetStartAt(e.TimeStart.UTC())
+	vevent.SetEndAt(e.TimeEnd.UTC())
+	vevent.SetSummary("予定あり")
+	vevent.SetLocation(e.Room.Place)
+	return vevent
+}
+
+// ICalFormat rooms を渡すと、部屋の確保時間もカレンダーに含める
+func ICalFormat(events []*domain.Event, rooms []*domain.Room, host string, userMap map[uuid.UUID]*domain.User) *ics.Calendar {
+	cal := newICalCalendar()
+	for _, e := range events {
+		vevent := iCalVeventFormat(e, host, userMap)
+		cal.AddVEvent(vevent)
+	}
+	for _, r := range rooms {
+		cal.AddVEvent(iCalRoomVeventFormat(r))
+	}
+	return cal
+}
+
+// ICalPlaceFormat 誰でも取得できるカレンダー。公開されていないイベントは時間帯だけにする
+func ICalPlaceFormat(events []*domain.Event, rooms []*domain.Room, host string, userMap map[uuid.UUID]*domain.User) *ics.Calendar {
+	cal := newICalCalendar()
+	for _, e := range events {
+		if e.Open {
+			cal.AddVEvent(iCalVeventFormat(e, host, userMap))
+		} else {
+			cal.AddVEvent(iCalBusyVeventFormat(e))
+		}
+	}
+	for _, r := range rooms {
+		cal.AddVEvent(iCalRoomVeventFormat(r))
+	}
+	return cal
+}
+
+func newICalCalendar() *ics.Calendar {
 	var std ics.Standard
 	std.AddProperty(ics.ComponentProperty(ics.PropertyTzoffsetfrom), "+0900")
 	std.AddProperty(ics.ComponentProperty(ics.PropertyTzoffsetto), "+0900")
@@ -173,10 +245,6 @@ func ICalFormat(events []*domain.Event, host string, userMap map[uuid.UUID]*doma
 
 	cal := ics.NewCalendar()
 	cal.AddVTimezone(tz)
-	for _, e := range events {
-		vevent := iCalVeventFormat(e, host, userMap)
-		cal.AddVEvent(vevent)
-	}
 	return cal
 }
 
@@ -219,6 +287,40 @@ func GenerateEventWebhookContent(method string, e *EventDetailRes, nofiticationT
 	return content
 }
 
+// GenerateAffectedEventsWebhookContent 部屋が使えなくなったときなどに、影響を受けるイベントの管理者へ知らせる
+// details は見出しの下に箇条書きで表示される
+func GenerateAffectedEventsWebhookContent(title string, details []string, events []*domain.Event, userMap map[uuid.UUID]*domain.User, origin string, isMention bool) string {
+	timeFormat := "01/02(Mon) 15:04"
+	prefix := "@"
+	if !isMention {
+		prefix = "@."
+	}
+
+	content := "## " + title + "\n"
+	for _, d := range details {
+		content += "- " + d + "\n"
+	}
+	content += "\n"
+	content += "以下のイベントは日時や場所の変更をお願いします:pray:" + "\n"
+	for _, e := range events {
+		content += fmt.Sprintf("- [%s](%s/events/%s) %s ~ %s", e.Name, origin, e.ID, e.TimeStart.In(tz.JST).Format(timeFormat), e.TimeEnd.In(tz.JST).Format(timeFormat))
+		admins := make([]string, 0, len(e.Admins))
+		for _, admin := range e.Admins {
+			user, ok := userMap[admin.ID]
+			if ok {
+				admins = append(admins, prefix+user.Name)
+			}
+		}
+		sort.Strings(admins)
+		if len(admins) > 0 {
+			content += " " + strings.Join(admins, " ")
+		}
+		content += "\n"
+	}
+
+	return strings.TrimRight(content, "\n")
+}
+
 func ConvdomainEventToEventDetailRes(src domain.Event) (dst EventDetailRes) {
 	dst.ID = src.ID
 	dst.Name = src.Name
@@ -244,6 +346,8 @@ func ConvdomainEventToEventDetailRes(src domain.Event) (dst EventDetailRes) {
 	for i := range src.Attendees {
 		dst.Attendees[i] = convdomainAttendeeToEventAttendeeRes(src.Attendees[i])
 	}
+	dst.CapacityExceeded = src.ExceedsCapacity()
+	dst.BlackoutConflict = src.BlackoutConflict
 	dst.Model = Model(src.Model)
 	return
 }
@@ -1,7 +1,13 @@
 package presentation
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
 )
 
 //go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s GroupReq -d domain.WriteGroupParams -o converter.go .
@@ -11,6 +17,8 @@ type GroupReq struct {
 	JoinFreely  bool        `json:"open"`
 	Members     []uuid.UUID `json:"members"`
 	Admins      []uuid.UUID `json:"admins"`
+	// ParentID uuid.Nil の場合は親を持たない
+	ParentID uuid.UUID `json:"parentId"`
 }
 
 //go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s domain.Group -d GroupRes -o converter.go .
@@ -18,7 +26,180 @@ type GroupReq struct {
 type GroupRes struct {
 	ID uuid.UUID `json:"groupId"`
 	GroupReq
-	IsTraQGroup bool      `json:"isTraQGroup"`
-	CreatedBy   uuid.UUID `json:"createdBy"`
+	Organizers  []uuid.UUID `json:"organizers"`
+	Viewers     []uuid.UUID `json:"viewers"`
+	Archived    bool        `json:"archived"`
+	IsTraQGroup bool        `json:"isTraQGroup"`
+	CreatedBy   uuid.UUID   `json:"createdBy"`
+	Model
+}
+
+type GroupInvitationReq struct {
+	// ExpiresAt nil の場合は期限なし
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// Invitee uuid.Nil の場合は誰でも使える
+	Invitee uuid.UUID `json:"invitee"`
+}
+
+type GroupInvitationRes struct {
+	ID      uuid.UUID `json:"invitationId"`
+	GroupID uuid.UUID `json:"groupId"`
+	Token   string    `json:"token"`
+	GroupInvitationReq
+	CreatedBy uuid.UUID `json:"createdBy"`
+	Model
+}
+
+type AcceptGroupInvitationReq struct {
+	Token string `json:"token"`
+}
+
+type GroupJoinRequestReq struct {
+	Message string `json:"message"`
+}
+
+type GroupJoinRequestRes struct {
+	ID      uuid.UUID `json:"requestId"`
+	GroupID uuid.UUID `json:"groupId"`
+	UserID  uuid.UUID `json:"userId"`
+	GroupJoinRequestReq
+	Model
+}
+
+type GroupAuditLogRes struct {
+	ID        uuid.UUID `json:"logId"`
+	GroupID   uuid.UUID `json:"groupId"`
+	Action    string    `json:"action"`
+	UserID    uuid.UUID `json:"userId"`
+	CreatedBy uuid.UUID `json:"createdBy"`
 	Model
 }
+
+type GroupICalTokenRes struct {
+	ID        uuid.UUID `json:"tokenId"`
+	GroupID   uuid.UUID `json:"groupId"`
+	Token     string    `json:"token"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	Model
+}
+
+func ConvGroupInvitationReqTodomainWriteGroupInvitationParams(src GroupInvitationReq) (dst domain.WriteGroupInvitationParams) {
+	if src.ExpiresAt != nil {
+		dst.ExpiresAt = *src.ExpiresAt
+	}
+	dst.Invitee = src.Invitee
+	return
+}
+
+func ConvdomainGroupInvitationToGroupInvitationRes(src domain.GroupInvitation) (dst GroupInvitationRes) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.Token = src.Token
+	if !src.ExpiresAt.IsZero() {
+		dst.ExpiresAt = new(time.Time)
+		(*dst.ExpiresAt) = src.ExpiresAt
+	}
+	dst.Invitee = src.Invitee
+	dst.CreatedBy = convdomainUserTouuidUUID(src.CreatedBy)
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainGroupInvitationToSGroupInvitationRes(src []*domain.GroupInvitation) (dst []GroupInvitationRes) {
+	dst = make([]GroupInvitationRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainGroupInvitationToGroupInvitationRes(*src[i]))
+		}
+	}
+	return
+}
+
+func ConvGroupJoinRequestReqTodomainWriteGroupJoinRequestParams(src GroupJoinRequestReq) (dst domain.WriteGroupJoinRequestParams) {
+	dst = domain.WriteGroupJoinRequestParams(src)
+	return
+}
+
+func ConvdomainGroupJoinRequestToGroupJoinRequestRes(src domain.GroupJoinRequest) (dst GroupJoinRequestRes) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.UserID = convdomainUserTouuidUUID(src.User)
+	dst.Message = src.Message
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainGroupJoinRequestToSGroupJoinRequestRes(src []*domain.GroupJoinRequest) (dst []GroupJoinRequestRes) {
+	dst = make([]GroupJoinRequestRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainGroupJoinRequestToGroupJoinRequestRes(*src[i]))
+		}
+	}
+	return
+}
+
+func ConvdomainGroupAuditLogToGroupAuditLogRes(src domain.GroupAuditLog) (dst GroupAuditLogRes) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.Action = string(src.Action)
+	dst.UserID = convdomainUserTouuidUUID(src.Target)
+	dst.CreatedBy = convdomainUserTouuidUUID(src.CreatedBy)
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainGroupAuditLogToSGroupAuditLogRes(src []*domain.GroupAuditLog) (dst []GroupAuditLogRes) {
+	dst = make([]GroupAuditLogRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainGroupAuditLogToGroupAuditLogRes(*src[i]))
+		}
+	}
+	return
+}
+
+func ConvdomainGroupICalTokenToGroupICalTokenRes(src domain.GroupICalToken) (dst GroupICalTokenRes) {
+	dst.ID = src.ID
+	dst.GroupID = src.GroupID
+	dst.Token = src.Token
+	dst.CreatedBy = convdomainUserTouuidUUID(src.CreatedBy)
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainGroupICalTokenToSGroupICalTokenRes(src []*domain.GroupICalToken) (dst []GroupICalTokenRes) {
+	dst = make([]GroupICalTokenRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainGroupICalTokenToGroupICalTokenRes(*src[i]))
+		}
+	}
+	return
+}
+
+// GenerateGroupWebhookContent グループの招待や参加申請を users に知らせる
+// details は見出しの下に箇条書きで表示される
+func GenerateGroupWebhookContent(title string, group *domain.Group, details []string, users []*domain.User, origin string, isMention bool) string {
+	prefix := "@"
+	if !isMention {
+		prefix = "@."
+	}
+
+	content := "## " + title + "\n"
+	content += fmt.Sprintf("### [%s](%s/groups/%s)", group.Name, origin, group.ID) + "\n"
+	for _, d := range details {
+		content += "- " + d + "\n"
+	}
+
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, prefix+user.Name)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		content += "\n" + strings.Join(names, " ")
+	}
+
+	return strings.TrimRight(content, "\n")
+}
@@ -0,0 +1,163 @@
+package presentation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/samber/lo"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+type OAuthClientReq struct {
+	Name         string                    `json:"name"`
+	Description  string                    `json:"description"`
+	RedirectURIs []string                  `json:"redirectUris"`
+	Scopes       []domain.AccessTokenScope `json:"scopes"`
+	Confidential bool                      `json:"confidential"`
+}
+
+type OAuthClientRes struct {
+	ID           uuid.UUID                 `json:"clientId"`
+	Name         string                    `json:"name"`
+	Description  string                    `json:"description"`
+	RedirectURIs []string                  `json:"redirectUris"`
+	Scopes       []domain.AccessTokenScope `json:"scopes"`
+	Confidential bool                      `json:"confidential"`
+	// Secret confidential なクライアントを作成したときだけ返す
+	Secret    string    `json:"clientSecret,omitempty"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	Model
+}
+
+// OAuthAuthorizeReq 認可リクエストのクエリパラメータ。RFC 6749 4.1.1, RFC 7636 4.3
+type OAuthAuthorizeReq struct {
+	ClientID            string `query:"client_id"`
+	RedirectURI         string `query:"redirect_uri"`
+	Scope               string `query:"scope"`
+	State               string `query:"state"`
+	CodeChallenge       string `query:"code_challenge"`
+	CodeChallengeMethod string `query:"code_challenge_method"`
+}
+
+type OAuthAuthorizeDecisionReq struct {
+	Approved bool `json:"approved"`
+}
+
+type OAuthConsentRes struct {
+	Client      OAuthClientRes            `json:"client"`
+	RedirectURI string                    `json:"redirectUri"`
+	Scopes      []domain.AccessTokenScope `json:"scopes"`
+}
+
+type OAuthAuthorizeRes struct {
+	RedirectURI string `json:"redirectUri"`
+}
+
+// OAuthTokenRes RFC 6749 5.1
+type OAuthTokenRes struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthIntrospectionRes RFC 7662 2.2。無効なトークンの場合は active だけを返す
+type OAuthIntrospectionRes struct {
+	Active    bool      `json:"active"`
+	Scope     string    `json:"scope,omitempty"`
+	ClientID  uuid.UUID `json:"client_id,omitzero"`
+	Subject   uuid.UUID `json:"sub,omitzero"`
+	TokenType string    `json:"token_type,omitempty"`
+	ExpiresAt int64     `json:"exp,omitempty"`
+	IssuedAt  int64     `json:"iat,omitempty"`
+}
+
+// OAuthErrorRes RFC 6749 5.2
+type OAuthErrorRes struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+func ConvOAuthClientReqTodomainWriteOAuthClientParams(src OAuthClientReq) (dst domain.WriteOAuthClientParams) {
+	dst.Name = src.Name
+	dst.Description = src.Description
+	dst.RedirectURIs = src.RedirectURIs
+	dst.Scopes = src.Scopes
+	dst.Confidential = src.Confidential
+	return
+}
+
+func ConvdomainOAuthClientToOAuthClientRes(src domain.OAuthClient) (dst OAuthClientRes) {
+	dst.ID = src.ID
+	dst.Name = src.Name
+	dst.Description = src.Description
+	dst.RedirectURIs = src.RedirectURIs
+	dst.Scopes = src.Scopes
+	dst.Confidential = src.Confidential
+	dst.Secret = src.Secret
+	dst.CreatedBy = src.CreatedBy
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainOAuthClientToSOAuthClientRes(src []*domain.OAuthClient) (dst []OAuthClientRes) {
+	dst = make([]OAuthClientRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainOAuthClientToOAuthClientRes(*src[i]))
+		}
+	}
+	return
+}
+
+// ConvOAuthAuthorizeReqTodomainOAuthAuthorizeParams client_id が UUID でない場合は uuid.Nil
+func ConvOAuthAuthorizeReqTodomainOAuthAuthorizeParams(src OAuthAuthorizeReq) (dst domain.OAuthAuthorizeParams) {
+	dst.ClientID = uuid.FromStringOrNil(src.ClientID)
+	dst.RedirectURI = src.RedirectURI
+	dst.Scopes = lo.Map(strings.Fields(src.Scope), func(s string, _ int) domain.AccessTokenScope {
+		return domain.AccessTokenScope(s)
+	})
+	dst.State = src.State
+	dst.CodeChallenge = src.CodeChallenge
+	dst.CodeChallengeMethod = src.CodeChallengeMethod
+	return
+}
+
+func ConvdomainOAuthConsentToOAuthConsentRes(src domain.OAuthConsent) (dst OAuthConsentRes) {
+	dst.Client = ConvdomainOAuthClientToOAuthClientRes(*src.Client)
+	dst.RedirectURI = src.RedirectURI
+	dst.Scopes = src.Scopes
+	return
+}
+
+func joinOAuthScopes(scopes []domain.AccessTokenScope) string {
+	return strings.Join(lo.Map(scopes, func(s domain.AccessTokenScope, _ int) string {
+		return string(s)
+	}), " ")
+}
+
+func ConvdomainOAuthTokenToOAuthTokenRes(src domain.OAuthToken, now time.Time) (dst OAuthTokenRes) {
+	dst.AccessToken = src.AccessToken
+	dst.TokenType = "Bearer"
+	dst.ExpiresIn = int64(src.ExpiresAt.Sub(now).Seconds())
+	dst.RefreshToken = src.RefreshToken
+	dst.Scope = joinOAuthScopes(src.Scopes)
+	return
+}
+
+// ConvdomainOAuthTokenToOAuthIntrospectionRes src が nil の場合は無効なトークン
+func ConvdomainOAuthTokenToOAuthIntrospectionRes(src *domain.OAuthToken) (dst OAuthIntrospectionRes) {
+	if src == nil {
+		return
+	}
+	dst.Active = true
+	dst.Scope = joinOAuthScopes(src.Scopes)
+	dst.ClientID = src.ClientID
+	dst.Subject = src.UserID
+	dst.TokenType = "Bearer"
+	dst.ExpiresAt = src.ExpiresAt.Unix()
+	dst.IssuedAt = src.CreatedAt.Unix()
+	return
+}
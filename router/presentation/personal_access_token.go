@@ -0,0 +1,60 @@
+package presentation
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+type PersonalAccessTokenReq struct {
+	Name   string                    `json:"name"`
+	Scopes []domain.AccessTokenScope `json:"scopes"`
+	// ExpiresAt nil の場合は期限なし
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type PersonalAccessTokenRes struct {
+	ID     uuid.UUID                 `json:"tokenId"`
+	Name   string                    `json:"name"`
+	Scopes []domain.AccessTokenScope `json:"scopes"`
+	// Token 作成したときだけ返す
+	Token      string     `json:"token,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	Model
+}
+
+func ConvPersonalAccessTokenReqTodomainWritePersonalAccessTokenParams(src PersonalAccessTokenReq) (dst domain.WritePersonalAccessTokenParams) {
+	dst.Name = src.Name
+	dst.Scopes = src.Scopes
+	if src.ExpiresAt != nil {
+		dst.ExpiresAt = *src.ExpiresAt
+	}
+	return
+}
+
+func ConvdomainPersonalAccessTokenToPersonalAccessTokenRes(src domain.PersonalAccessToken) (dst PersonalAccessTokenRes) {
+	dst.ID = src.ID
+	dst.Name = src.Name
+	dst.Scopes = src.Scopes
+	dst.Token = src.Token
+	if !src.ExpiresAt.IsZero() {
+		dst.ExpiresAt = &src.ExpiresAt
+	}
+	if !src.LastUsedAt.IsZero() {
+		dst.LastUsedAt = &src.LastUsedAt
+	}
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainPersonalAccessTokenToSPersonalAccessTokenRes(src []*domain.PersonalAccessToken) (dst []PersonalAccessTokenRes) {
+	dst = make([]PersonalAccessTokenRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainPersonalAccessTokenToPersonalAccessTokenRes(*src[i]))
+		}
+	}
+	return
+}
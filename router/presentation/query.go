@@ -1,10 +1,14 @@
 package presentation
 
 import (
+	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/tz"
 )
 
 // getTimeRange ?dateBegin=2020-03-27T00:00:00Z
@@ -57,3 +61,77 @@ func GetExcludeEventID(values url.Values) (uuid.UUID, error) {
 	}
 	return excludeEventID, nil
 }
+
+// GetStatsTimeRange ?since=2020-04-01T00:00:00+09:00&until=2020-10-01T00:00:00+09:00
+func GetStatsTimeRange(values url.Values) (since time.Time, until time.Time, err error) {
+	if values.Get("since") != "" {
+		since, err = time.Parse(time.RFC3339, values.Get("since"))
+		if err != nil {
+			return
+		}
+	}
+	if values.Get("until") != "" {
+		until, err = time.Parse(time.RFC3339, values.Get("until"))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func GetRoomStatsGroupBy(values url.Values) (domain.RoomStatsGroupBy, error) {
+	groupBy := values.Get("groupBy")
+	switch groupBy {
+	case "", "place":
+		return domain.RoomStatsGroupByPlace, nil
+	case "week":
+		return domain.RoomStatsGroupByWeek, nil
+	case "group":
+		return domain.RoomStatsGroupByGroup, nil
+	}
+
+	return 0, fmt.Errorf("invalid groupBy: %s", groupBy)
+}
+
+// GetRoomAttributeFilter ?minCapacity=30&projector=true&whiteboard=true&powerOutlets=true&accessible=true
+func GetRoomAttributeFilter(values url.Values) (attr domain.RoomAttributeFilter, err error) {
+	if values.Get("minCapacity") != "" {
+		attr.MinCapacity, err = strconv.Atoi(values.Get("minCapacity"))
+		if err != nil {
+			return
+		}
+	}
+	attr.Projector = values.Get("projector") == "true"
+	attr.Whiteboard = values.Get("whiteboard") == "true"
+	attr.PowerOutlets = values.Get("powerOutlets") == "true"
+	attr.Accessible = values.Get("accessible") == "true"
+	return
+}
+
+// GetAvailabilityDays ?date=2024-04-02&view=week
+// day はその日のみ、week はその日を含む週 (月曜始まり) の7日間を返す
+func GetAvailabilityDays(values url.Values) ([]time.Time, error) {
+	date := time.Now().In(tz.JST)
+	if values.Get("date") != "" {
+		var err error
+		date, err = time.ParseInLocation("2006-01-02", values.Get("date"), tz.JST)
+		if err != nil {
+			return nil, err
+		}
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, tz.JST)
+
+	switch view := values.Get("view"); view {
+	case "", "day":
+		return []time.Time{date}, nil
+	case "week":
+		monday := date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+		days := make([]time.Time, 7)
+		for i := range days {
+			days[i] = monday.AddDate(0, 0, i)
+		}
+		return days, nil
+	default:
+		return nil, fmt.Errorf("invalid view: %s", view)
+	}
+}
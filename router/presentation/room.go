@@ -14,6 +14,8 @@ type RoomReq struct {
 	TimeStart time.Time   `json:"timeStart"`
 	TimeEnd   time.Time   `json:"timeEnd"`
 	Admins    []uuid.UUID `json:"admins"`
+	// SharedCapacity 同時に入れる共用イベントの数。0 は場所の設定に従う
+	SharedCapacity int `json:"sharedCapacity"`
 }
 
 type RoomCSVReq struct {
@@ -36,12 +38,64 @@ type RoomRes struct {
 	// Verifeid indicates if the room has been verified by privileged users.
 	Verified bool `json:"verified"`
 	RoomReq
-	FreeTimes   []StartEndTime `json:"freeTimes" cvt:"-"`
-	SharedTimes []StartEndTime `json:"sharedTimes" cvt:"-"`
-	CreatedBy   uuid.UUID      `json:"createdBy"`
+	FreeTimes   []StartEndTime     `json:"freeTimes" cvt:"-"`
+	SharedTimes []StartEndTime     `json:"sharedTimes" cvt:"-"`
+	Blackouts   []RoomBlackoutRes  `json:"blackouts"`
+	SeriesID    uuid.UUID          `json:"seriesId"`
+	Attribute   *PlaceAttributeRes `json:"attribute"`
+	CreatedBy   uuid.UUID          `json:"createdBy"`
 	Model
 }
 
+type RoomAdminsReq struct {
+	RoomIDs []uuid.UUID `json:"roomIds"`
+	Admins  []uuid.UUID `json:"admins"`
+	// Replace true のとき既存の管理者を置き換える
+	Replace bool `json:"replace"`
+}
+
+type MoveRoomEventsReq struct {
+	ToRoomID uuid.UUID `json:"roomId"`
+	// EventIDs 空の場合は部屋の全てのイベントを移す
+	EventIDs []uuid.UUID `json:"eventIds"`
+}
+
+type PlaceAttributeReq struct {
+	Capacity     int  `json:"capacity"`
+	Projector    bool `json:"projector"`
+	Whiteboard   bool `json:"whiteboard"`
+	PowerOutlets bool `json:"powerOutlets"`
+	Accessible   bool `json:"accessible"`
+	// SharedCapacity 同時に入れる共用イベントの数。0 は制限なし
+	SharedCapacity int `json:"sharedCapacity"`
+}
+
+type PlaceAttributeRes struct {
+	Place string `json:"place"`
+	PlaceAttributeReq
+}
+
+type RoomBlackoutReq struct {
+	RoomID    uuid.UUID `json:"roomId"`
+	Place     string    `json:"place"`
+	TimeStart time.Time `json:"timeStart"`
+	TimeEnd   time.Time `json:"timeEnd"`
+	Reason    string    `json:"reason"`
+}
+
+type RoomBlackoutRes struct {
+	ID uuid.UUID `json:"blackoutId"`
+	RoomBlackoutReq
+	CreatedBy uuid.UUID `json:"createdBy"`
+	Model
+}
+
+type RoomBlackoutCreatedRes struct {
+	RoomBlackoutRes
+	// AffectedEvents 使えなくなった時間帯に重なるイベント
+	AffectedEvents []EventsResElement `json:"affectedEvents"`
+}
+
 // TODO: FreeTimesとShareTimesを埋めるために手動で書いている
 // //go:generate go run github.com/fuji8/gotypeconverter/cmd/gotypeconverter@latest -s []*domain.Room -d []*RoomRes -o converter.go .
 func ConvSPdomainRoomToSPRoomRes(src []*domain.Room) (dst []*RoomRes) {
@@ -66,6 +120,16 @@ func ConvdomainRoomToRoomRes(src domain.Room) (dst RoomRes) {
 	for i := range src.Admins {
 		dst.Admins[i] = convdomainUserTouuidUUID(src.Admins[i])
 	}
+	dst.Blackouts = make([]RoomBlackoutRes, len(src.Blackouts))
+	for i := range src.Blackouts {
+		dst.Blackouts[i] = ConvdomainRoomBlackoutToRoomBlackoutRes(src.Blackouts[i])
+	}
+	dst.SharedCapacity = src.SharedCapacity
+	dst.SeriesID = src.SeriesID
+	if src.Attribute != nil {
+		dst.Attribute = new(PlaceAttributeRes)
+		(*dst.Attribute) = ConvdomainPlaceAttributeToPlaceAttributeRes(*src.Attribute)
+	}
 	dst.CreatedBy = convdomainUserTouuidUUID(src.CreatedBy)
 	dst.FreeTimes = ConvSdomainStartEndTimeToSStartEndTime(src.CalcAvailableTime(false))
 	dst.SharedTimes = ConvSdomainStartEndTimeToSStartEndTime(src.CalcAvailableTime(true))
@@ -73,6 +137,75 @@ func ConvdomainRoomToRoomRes(src domain.Room) (dst RoomRes) {
 	return
 }
 
+func ConvRoomAdminsReqTodomainWriteRoomAdminsParams(src RoomAdminsReq) (dst domain.WriteRoomAdminsParams) {
+	dst = domain.WriteRoomAdminsParams(src)
+	return
+}
+
+func ConvMoveRoomEventsReqTodomainMoveRoomEventsParams(src MoveRoomEventsReq) (dst domain.MoveRoomEventsParams) {
+	dst = domain.MoveRoomEventsParams(src)
+	return
+}
+
+func ConvPlaceAttributeReqTodomainPlaceAttribute(src PlaceAttributeReq, place string) (dst domain.PlaceAttribute) {
+	dst.Place = place
+	dst.Capacity = src.Capacity
+	dst.Projector = src.Projector
+	dst.Whiteboard = src.Whiteboard
+	dst.PowerOutlets = src.PowerOutlets
+	dst.Accessible = src.Accessible
+	dst.SharedCapacity = src.SharedCapacity
+	return
+}
+
+func ConvdomainPlaceAttributeToPlaceAttributeRes(src domain.PlaceAttribute) (dst PlaceAttributeRes) {
+	dst.Place = src.Place
+	dst.Capacity = src.Capacity
+	dst.Projector = src.Projector
+	dst.Whiteboard = src.Whiteboard
+	dst.PowerOutlets = src.PowerOutlets
+	dst.Accessible = src.Accessible
+	dst.SharedCapacity = src.SharedCapacity
+	return
+}
+
+func ConvSPdomainPlaceAttributeToSPlaceAttributeRes(src []*domain.PlaceAttribute) (dst []PlaceAttributeRes) {
+	dst = make([]PlaceAttributeRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainPlaceAttributeToPlaceAttributeRes(*src[i]))
+		}
+	}
+	return
+}
+
+func ConvRoomBlackoutReqTodomainWriteRoomBlackoutParams(src RoomBlackoutReq) (dst domain.WriteRoomBlackoutParams) {
+	dst = domain.WriteRoomBlackoutParams(src)
+	return
+}
+
+func ConvdomainRoomBlackoutToRoomBlackoutRes(src domain.RoomBlackout) (dst RoomBlackoutRes) {
+	dst.ID = src.ID
+	dst.RoomID = src.RoomID
+	dst.Place = src.Place
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Reason = src.Reason
+	dst.CreatedBy = convdomainUserTouuidUUID(src.CreatedBy)
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainRoomBlackoutToSRoomBlackoutRes(src []*domain.RoomBlackout) (dst []RoomBlackoutRes) {
+	dst = make([]RoomBlackoutRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainRoomBlackoutToRoomBlackoutRes(*src[i]))
+		}
+	}
+	return
+}
+
 func ChangeRoomCSVReqTodomainWriteRoomParams(src RoomCSVReq, userID uuid.UUID) (*domain.WriteRoomParams, error) {
 	layout := "2006/01/02 15:04"
 	var params domain.WriteRoomParams
@@ -93,3 +226,34 @@ func ChangeRoomCSVReqTodomainWriteRoomParams(src RoomCSVReq, userID uuid.UUID) (
 
 	return &params, err
 }
+
+type RoomStatsRes struct {
+	Key              string  `json:"key"`
+	RoomCount        int     `json:"roomCount"`
+	ReservedHours    float64 `json:"reservedHours"`
+	UsedHours        float64 `json:"usedHours"`
+	IdleHours        float64 `json:"idleHours"`
+	EventCount       int     `json:"eventCount"`
+	SharedHours      float64 `json:"sharedHours"`
+	SharedEventCount int     `json:"sharedEventCount"`
+}
+
+func ConvSPdomainRoomStatsToSRoomStatsRes(src []*domain.RoomStats) (dst []RoomStatsRes) {
+	dst = make([]RoomStatsRes, 0, len(src))
+	for _, s := range src {
+		if s == nil {
+			continue
+		}
+		dst = append(dst, RoomStatsRes{
+			Key:              s.Key,
+			RoomCount:        s.RoomCount,
+			ReservedHours:    s.ReservedTime.Hours(),
+			UsedHours:        s.UsedTime.Hours(),
+			IdleHours:        s.IdleTime().Hours(),
+			EventCount:       s.EventCount,
+			SharedHours:      s.SharedTime.Hours(),
+			SharedEventCount: s.SharedEventCount,
+		})
+	}
+	return
+}
@@ -0,0 +1,96 @@
+package presentation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/tz"
+)
+
+const roomSeriesDateLayout = "2006-01-02"
+
+type RoomSeriesReq struct {
+	Place     string    `json:"place"`
+	TimeStart time.Time `json:"timeStart"`
+	TimeEnd   time.Time `json:"timeEnd"`
+	// Weekdays 0 (日曜) ~ 6 (土曜)
+	Weekdays   []int       `json:"weekdays"`
+	Until      string      `json:"until"`
+	Exceptions []string    `json:"exceptions"`
+	Admins     []uuid.UUID `json:"admins"`
+}
+
+type RoomSeriesRes struct {
+	ID         uuid.UUID `json:"seriesId"`
+	Place      string    `json:"place"`
+	TimeStart  time.Time `json:"timeStart"`
+	TimeEnd    time.Time `json:"timeEnd"`
+	Weekdays   []int     `json:"weekdays"`
+	Until      string    `json:"until"`
+	Exceptions []string  `json:"exceptions"`
+	Rooms      []RoomRes `json:"rooms"`
+	CreatedBy  uuid.UUID `json:"createdBy"`
+	Model
+}
+
+// ConvRoomSeriesReqTodomainWriteRoomSeriesParams 日付は JST として解釈する
+func ConvRoomSeriesReqTodomainWriteRoomSeriesParams(src RoomSeriesReq) (dst domain.WriteRoomSeriesParams, err error) {
+	dst.Place = src.Place
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Weekdays = make([]time.Weekday, len(src.Weekdays))
+	for i, w := range src.Weekdays {
+		if w < int(time.Sunday) || int(time.Saturday) < w {
+			return dst, fmt.Errorf("invalid weekday: %d", w)
+		}
+		dst.Weekdays[i] = time.Weekday(w)
+	}
+	dst.Until, err = time.ParseInLocation(roomSeriesDateLayout, src.Until, tz.JST)
+	if err != nil {
+		return dst, err
+	}
+	dst.Exceptions = make([]time.Time, len(src.Exceptions))
+	for i := range src.Exceptions {
+		dst.Exceptions[i], err = time.ParseInLocation(roomSeriesDateLayout, src.Exceptions[i], tz.JST)
+		if err != nil {
+			return dst, err
+		}
+	}
+	dst.Admins = src.Admins
+	return dst, nil
+}
+
+func ConvdomainRoomSeriesToRoomSeriesRes(src domain.RoomSeries) (dst RoomSeriesRes) {
+	dst.ID = src.ID
+	dst.Place = src.Place
+	dst.TimeStart = src.TimeStart
+	dst.TimeEnd = src.TimeEnd
+	dst.Weekdays = make([]int, len(src.Weekdays))
+	for i := range src.Weekdays {
+		dst.Weekdays[i] = int(src.Weekdays[i])
+	}
+	dst.Until = src.Until.In(tz.JST).Format(roomSeriesDateLayout)
+	dst.Exceptions = make([]string, len(src.Exceptions))
+	for i := range src.Exceptions {
+		dst.Exceptions[i] = src.Exceptions[i].In(tz.JST).Format(roomSeriesDateLayout)
+	}
+	dst.Rooms = make([]RoomRes, len(src.Rooms))
+	for i := range src.Rooms {
+		dst.Rooms[i] = ConvdomainRoomToRoomRes(src.Rooms[i])
+	}
+	dst.CreatedBy = convdomainUserTouuidUUID(src.CreatedBy)
+	dst.Model = Model(src.Model)
+	return
+}
+
+func ConvSPdomainRoomSeriesToSRoomSeriesRes(src []*domain.RoomSeries) (dst []RoomSeriesRes) {
+	dst = make([]RoomSeriesRes, 0, len(src))
+	for i := range src {
+		if src[i] != nil {
+			dst = append(dst, ConvdomainRoomSeriesToRoomSeriesRes(*src[i]))
+		}
+	}
+	return
+}
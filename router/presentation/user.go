@@ -11,4 +11,14 @@ type UserRes struct {
 	Icon        string    `json:"icon"`
 	Privileged  bool      `json:"privileged"`
 	State       int       `json:"state"`
+	Guest       bool      `json:"guest"`
+}
+
+type HandOverReq struct {
+	SuccessorID uuid.UUID `json:"successorId"`
+}
+
+type HandOverRes struct {
+	RoomCount  int `json:"roomCount"`
+	EventCount int `json:"eventCount"`
 }
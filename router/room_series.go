@@ -0,0 +1,89 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/traPtitech/knoQ/router/presentation"
+)
+
+// HandlePostRoomSeries 繰り返し確保する部屋を作成
+func (h *Handlers) HandlePostRoomSeries(c echo.Context) error {
+	var req presentation.RoomSeriesReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+	params, err := presentation.ConvRoomSeriesReqTodomainWriteRoomSeriesParams(req)
+	if err != nil {
+		return badRequest(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	series, err := h.Service.CreateRoomSeries(c.Request().Context(), reqID, params)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusCreated, presentation.ConvdomainRoomSeriesToRoomSeriesRes(*series))
+}
+
+// HandleUpdateRoomSeries 繰り返しを変更し、まだ終わっていない部屋を変更
+func (h *Handlers) HandleUpdateRoomSeries(c echo.Context) error {
+	seriesID, err := getPathSeriesID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	var req presentation.RoomSeriesReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+	params, err := presentation.ConvRoomSeriesReqTodomainWriteRoomSeriesParams(req)
+	if err != nil {
+		return badRequest(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	series, err := h.Service.UpdateRoomSeries(c.Request().Context(), reqID, seriesID, params)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainRoomSeriesToRoomSeriesRes(*series))
+}
+
+// HandleDeleteRoomSeries 繰り返しを取り消し、まだ終わっていない部屋を削除
+func (h *Handlers) HandleDeleteRoomSeries(c echo.Context) error {
+	seriesID, err := getPathSeriesID(c)
+	if err != nil {
+		return notFound(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	err = h.Service.DeleteRoomSeries(c.Request().Context(), reqID, seriesID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleGetRoomSeries 繰り返しと確保した部屋を取得
+func (h *Handlers) HandleGetRoomSeries(c echo.Context) error {
+	seriesID, err := getPathSeriesID(c)
+	if err != nil {
+		return notFound(err)
+	}
+
+	series, err := h.Service.GetRoomSeries(c.Request().Context(), seriesID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainRoomSeriesToRoomSeriesRes(*series))
+}
+
+// HandleGetAllRoomSeries 繰り返しを全て取得
+func (h *Handlers) HandleGetAllRoomSeries(c echo.Context) error {
+	series, err := h.Service.GetAllRoomSeries(c.Request().Context())
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainRoomSeriesToSRoomSeriesRes(series))
+}
@@ -1,10 +1,15 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
 	"github.com/traPtitech/knoQ/router/presentation"
+	"github.com/traPtitech/knoQ/utils"
+	"github.com/traPtitech/knoQ/utils/tz"
 
 	"github.com/labstack/echo/v4"
 )
@@ -92,9 +97,29 @@ func (h *Handlers) HandleGetRooms(c echo.Context) error {
 	if err != nil {
 		return badRequest(err)
 	}
+	attr, err := presentation.GetRoomAttributeFilter(values)
+	if err != nil {
+		return badRequest(err)
+	}
 
 	ctx := c.Request().Context()
-	rooms, err := h.Service.GetAllRooms(ctx, start, end, excludeEventID, onlyVerified)
+	rooms, err := h.Service.GetAllRooms(ctx, start, end, excludeEventID, onlyVerified, attr)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainRoomToSPRoomRes(rooms))
+}
+
+// HandleUpdateRoomsAdmins 複数の部屋の管理者をまとめて変更
+func (h *Handlers) HandleUpdateRoomsAdmins(c echo.Context) error {
+	var req presentation.RoomAdminsReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	rooms, err := h.Service.UpdateRoomsAdmins(c.Request().Context(), reqID,
+		presentation.ConvRoomAdminsReqTodomainWriteRoomAdminsParams(req))
 	if err != nil {
 		return judgeErrorResponse(err)
 	}
@@ -110,12 +135,17 @@ func (h *Handlers) HandleDeleteRoom(c echo.Context) error {
 
 	ctx := c.Request().Context()
 	reqID := c.Get(userIDKey).(uuid.UUID)
-	err = h.Service.DeleteRoom(ctx, reqID, roomID)
+	room, err := h.Service.GetRoom(ctx, roomID, uuid.Nil)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	events, err := h.Service.DeleteRoom(ctx, reqID, roomID)
 	if err != nil {
 		return judgeErrorResponse(err)
 	}
+	h.notifyAffectedEvents(ctx, "部屋が削除されました", roomDetails(room), events)
 
-	return c.NoContent(http.StatusNoContent)
+	return c.JSON(http.StatusOK, presentation.ConvDomainEventsToEventsResElems(events))
 }
 
 func (h *Handlers) HandleVerifyRoom(c echo.Context) error {
@@ -141,9 +171,205 @@ func (h *Handlers) HandleUnVerifyRoom(c echo.Context) error {
 
 	ctx := c.Request().Context()
 	reqID := c.Get(userIDKey).(uuid.UUID)
-	err = h.Service.UnVerifyRoom(ctx, reqID, roomID)
+	room, err := h.Service.GetRoom(ctx, roomID, uuid.Nil)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	events, err := h.Service.UnVerifyRoom(ctx, reqID, roomID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	h.notifyAffectedEvents(ctx, "部屋の確認が取り消されました", roomDetails(room), events)
+
+	return c.JSON(http.StatusOK, presentation.ConvDomainEventsToEventsResElems(events))
+}
+
+// HandleMoveRoomEvents 部屋のイベントを別の部屋に移す
+func (h *Handlers) HandleMoveRoomEvents(c echo.Context) error {
+	roomID, err := getPathRoomID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	var req presentation.MoveRoomEventsReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	events, err := h.Service.MoveRoomEvents(c.Request().Context(), reqID, roomID,
+		presentation.ConvMoveRoomEventsReqTodomainMoveRoomEventsParams(req))
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvDomainEventsToEventsResElems(events))
+}
+
+func roomDetails(room *domain.Room) []string {
+	timeFormat := "01/02(Mon) 15:04"
+	return []string{
+		fmt.Sprintf("場所: %s", room.Place),
+		fmt.Sprintf("日時: %s ~ %s", room.TimeStart.In(tz.JST).Format(timeFormat), room.TimeEnd.In(tz.JST).Format(timeFormat)),
+	}
+}
+
+// HandleGetRoomStats 部屋の利用統計を取得
+func (h *Handlers) HandleGetRoomStats(c echo.Context) error {
+	values := c.QueryParams()
+	since, until, err := presentation.GetStatsTimeRange(values)
+	if err != nil {
+		return badRequest(err)
+	}
+	groupBy, err := presentation.GetRoomStatsGroupBy(values)
+	if err != nil {
+		return badRequest(err)
+	}
+	onlyVerified := values.Get("onlyVerified") == "true"
+
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	stats, err := h.Service.GetRoomStats(ctx, reqID, since, until, groupBy, onlyVerified)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainRoomStatsToSRoomStatsRes(stats))
+}
+
+// HandlePostRoomBlackout 部屋が使えなくなる時間帯を追加
+func (h *Handlers) HandlePostRoomBlackout(c echo.Context) error {
+	var req presentation.RoomBlackoutReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+
+	params := presentation.ConvRoomBlackoutReqTodomainWriteRoomBlackoutParams(req)
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	blackout, events, err := h.Service.CreateRoomBlackout(ctx, reqID, params)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	timeFormat := "01/02(Mon) 15:04"
+	details := []string{
+		fmt.Sprintf("場所: %s", blackout.Place),
+		fmt.Sprintf("日時: %s ~ %s", blackout.TimeStart.In(tz.JST).Format(timeFormat), blackout.TimeEnd.In(tz.JST).Format(timeFormat)),
+	}
+	if blackout.Reason != "" {
+		details = append(details, fmt.Sprintf("理由: %s", blackout.Reason))
+	}
+	h.notifyAffectedEvents(ctx, "部屋が使えなくなりました", details, events)
+
+	return c.JSON(http.StatusCreated, presentation.RoomBlackoutCreatedRes{
+		RoomBlackoutRes: presentation.ConvdomainRoomBlackoutToRoomBlackoutRes(*blackout),
+		AffectedEvents:  presentation.ConvDomainEventsToEventsResElems(events),
+	})
+}
+
+// HandleGetRoomBlackouts 部屋が使えない時間帯を取得
+func (h *Handlers) HandleGetRoomBlackouts(c echo.Context) error {
+	start, end, err := presentation.GetTimeRange(c.QueryParams())
+	if err != nil {
+		return badRequest(err)
+	}
+
+	blackouts, err := h.Service.GetRoomBlackouts(c.Request().Context(), start, end)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainRoomBlackoutToSRoomBlackoutRes(blackouts))
+}
+
+// HandleDeleteRoomBlackout 部屋が使えない時間帯を削除
+func (h *Handlers) HandleDeleteRoomBlackout(c echo.Context) error {
+	blackoutID, err := getPathBlackoutID(c)
+	if err != nil {
+		return notFound(err)
+	}
+
+	ctx := c.Request().Context()
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	err = h.Service.DeleteRoomBlackout(ctx, reqID, blackoutID)
 	if err != nil {
 		return judgeErrorResponse(err)
 	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+// HandleGetPlaceAttributes 場所ごとの設備を取得
+func (h *Handlers) HandleGetPlaceAttributes(c echo.Context) error {
+	attrs, err := h.Service.GetPlaceAttributes(c.Request().Context())
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvSPdomainPlaceAttributeToSPlaceAttributeRes(attrs))
+}
+
+// HandlePutPlaceAttribute 場所の設備を登録
+func (h *Handlers) HandlePutPlaceAttribute(c echo.Context) error {
+	place, err := url.PathUnescape(c.Param("place"))
+	if err != nil {
+		return badRequest(err)
+	}
+	var req presentation.PlaceAttributeReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	attr, err := h.Service.UpsertPlaceAttribute(c.Request().Context(), reqID,
+		presentation.ConvPlaceAttributeReqTodomainPlaceAttribute(req, place))
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.ConvdomainPlaceAttributeToPlaceAttributeRes(*attr))
+}
+
+// HandleDeletePlaceAttribute 場所の設備を削除
+func (h *Handlers) HandleDeletePlaceAttribute(c echo.Context) error {
+	place, err := url.PathUnescape(c.Param("place"))
+	if err != nil {
+		return badRequest(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	err = h.Service.DeletePlaceAttribute(c.Request().Context(), reqID, place)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleGetRoomAvailability 時間割に沿って場所ごとの部屋の確保状況を取得
+func (h *Handlers) HandleGetRoomAvailability(c echo.Context) error {
+	days, err := presentation.GetAvailabilityDays(c.QueryParams())
+	if err != nil {
+		return badRequest(err)
+	}
+	periods := h.Periods
+	if len(periods) == 0 {
+		periods = utils.DefaultPeriods
+	}
+
+	ctx := c.Request().Context()
+	rooms, err := h.Service.GetOverlappingRooms(ctx, days[0], days[len(days)-1].AddDate(0, 0, 1))
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+
+	res := presentation.RoomAvailabilityRes{
+		Periods: presentation.ConvSPeriodToSPeriodRes(periods),
+		Days:    make([]presentation.DayRoomAvailabilityRes, len(days)),
+	}
+	for i, day := range days {
+		next := day.AddDate(0, 0, 1)
+		dayRooms := make([]*domain.Room, 0)
+		for _, room := range rooms {
+			if room.TimeStart.Before(next) && day.Before(room.TimeEnd) {
+				dayRooms = append(dayRooms, room)
+			}
+		}
+		grid := utils.MakeRoomAvailabilityGrid(dayRooms, periods, day)
+		res.Days[i] = presentation.ConvSPlaceAvailabilityToDayRoomAvailabilityRes(grid, periods, day)
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -3,12 +3,14 @@ package router
 
 import (
 	"bytes"
+	"expvar"
 	"io"
 	"net/http"
 	"strings"
 
 	"github.com/jszwec/csvutil"
 	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils"
 
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
@@ -29,6 +31,10 @@ type Handlers struct {
 	ActivityChannelID string
 	DailyChannelID    string
 	Origin            string
+	// TraQAPI Webhook の送信先
+	TraQAPI utils.TraQAPI
+	// Periods 部屋の空き状況を表示する時間割
+	Periods []utils.Period
 }
 
 func (h *Handlers) SetupRoute() *echo.Echo {
@@ -62,8 +68,25 @@ func (h *Handlers) SetupRoute() *echo.Echo {
 	{
 		apiNoAuth.POST("/authParams", h.HandlePostAuthParams)
 		apiNoAuth.GET("/callback", h.HandleCallback)
+		apiNoAuth.GET("/providers", h.HandleGetLoginProviders)
+		apiNoAuth.POST("/authParams/:provider", h.HandlePostProviderAuthParams)
+		apiNoAuth.GET("/callback/:provider", h.HandleProviderCallback)
 		apiNoAuth.GET("/ical/v1/:userIDsecret", h.HandleGetiCalByPrivateID)
+		apiNoAuth.GET("/ical/v1/rooms/:roomPlace", h.HandleGetiCalByRoomPlace)
+		apiNoAuth.GET("/ical/v1/groups/:groupid", h.HandleGetiCalByGroupID)
 		apiNoAuth.GET("/version", h.HandleGetVersion)
+		// OAuth クライアントが client_id, client_secret で認証する
+		apiNoAuth.POST("/oauth2/token", h.HandlePostOAuthToken)
+		apiNoAuth.POST("/oauth2/introspect", h.HandlePostOAuthIntrospect)
+	}
+
+	// 認証あり (ゲストユーザーも使える)
+	apiWithGuestAuth := apiNoAuth.Group("", h.UserMiddleware)
+	{
+		apiWithGuestAuth.GET("/events/:eventid", h.HandleGetEvent)
+		apiWithGuestAuth.PUT("/events/:eventid/attendees/me", h.HandleUpsertMeEventSchedule)
+		apiWithGuestAuth.GET("/users/me", h.HandleGetUserMe)
+		apiWithGuestAuth.GET("/users/me/events", h.HandleGetMeEvents)
 	}
 
 	// 認証あり (JWT認証、traQ認証)
@@ -73,16 +96,39 @@ func (h *Handlers) SetupRoute() *echo.Echo {
 		{
 			groupsAPI.GET("", h.HandleGetGroups)
 			groupsAPI.POST("", h.HandlePostGroup)
+			groupsAPI.POST("/join", h.HandleAcceptGroupInvitation)
 			groupsAPI.GET("/:groupid", h.HandleGetGroup)
 			groupsAPI.PUT("/:groupid/members/me", h.HandleAddMeGroup)
 			groupsAPI.DELETE("/:groupid/members/me", h.HandleDeleteMeGroup)
 			groupsAPI.GET("/:groupid/events", h.HandleGetEventsByGroupID)
+			groupsAPI.POST("/:groupid/requests", h.HandlePostGroupJoinRequest)
+			// traQ のグループの管理者も使えるように、権限は service で確認する
+			groupsAPI.GET("/:groupid/ical", h.HandleGetGroupICalTokens)
+			groupsAPI.POST("/:groupid/ical", h.HandlePostGroupICalToken)
+			groupsAPI.DELETE("/:groupid/ical/:tokenid", h.HandleDeleteGroupICalToken)
 
 			// グループ管理者権限が必要
-			groupsAPIWithAdminAuth := groupsAPI.Group("", h.GroupAdminsMiddleware)
+			groupsAPIWithAdminAuth := groupsAPI.Group("", h.GroupRoleMiddleware(domain.GroupRoleAdmin))
 			{
+				groupsAPIWithAdminAuth.GET("/:groupid/audit", h.HandleGetGroupAuditLogs)
+				groupsAPIWithAdminAuth.GET("/:groupid/requests", h.HandleGetGroupJoinRequests)
 				groupsAPIWithAdminAuth.PUT("/:groupid", h.HandleUpdateGroup)
 				groupsAPIWithAdminAuth.DELETE("/:groupid", h.HandleDeleteGroup)
+				groupsAPIWithAdminAuth.POST("/:groupid/archive", h.HandleArchiveGroup)
+				groupsAPIWithAdminAuth.DELETE("/:groupid/archive", h.HandleUnArchiveGroup)
+				groupsAPIWithAdminAuth.POST("/:groupid/members/:userid", h.HandleAddMemberToGroup)
+				groupsAPIWithAdminAuth.DELETE("/:groupid/members/:userid", h.HandleDeleteMemberOfGroup)
+				groupsAPIWithAdminAuth.POST("/:groupid/admins/:userid", h.HandleAddAdminToGroup)
+				groupsAPIWithAdminAuth.DELETE("/:groupid/admins/:userid", h.HandleDeleteAdminOfGroup)
+				groupsAPIWithAdminAuth.POST("/:groupid/organizers/:userid", h.HandleAddOrganizerToGroup)
+				groupsAPIWithAdminAuth.DELETE("/:groupid/organizers/:userid", h.HandleDeleteOrganizerOfGroup)
+				groupsAPIWithAdminAuth.POST("/:groupid/viewers/:userid", h.HandleAddViewerToGroup)
+				groupsAPIWithAdminAuth.DELETE("/:groupid/viewers/:userid", h.HandleDeleteViewerOfGroup)
+				groupsAPIWithAdminAuth.GET("/:groupid/invitations", h.HandleGetGroupInvitations)
+				groupsAPIWithAdminAuth.POST("/:groupid/invitations", h.HandlePostGroupInvitation)
+				groupsAPIWithAdminAuth.DELETE("/:groupid/invitations/:invitationid", h.HandleDeleteGroupInvitation)
+				groupsAPIWithAdminAuth.POST("/:groupid/requests/:requestid/approve", h.HandleApproveGroupJoinRequest)
+				groupsAPIWithAdminAuth.POST("/:groupid/requests/:requestid/reject", h.HandleRejectGroupJoinRequest)
 			}
 		}
 
@@ -90,8 +136,6 @@ func (h *Handlers) SetupRoute() *echo.Echo {
 		{
 			eventsAPI.GET("", h.HandleGetEvents)
 			eventsAPI.POST("", h.HandlePostEvent, middleware.BodyDump(h.WebhookEventHandler))
-			eventsAPI.GET("/:eventid", h.HandleGetEvent)
-			eventsAPI.PUT("/:eventid/attendees/me", h.HandleUpsertMeEventSchedule)
 			eventsAPI.POST("/:eventid/tags", h.HandleAddEventTag)
 			eventsAPI.DELETE("/:eventid/tags/:tagName", h.HandleDeleteEventTag)
 
@@ -107,13 +151,29 @@ func (h *Handlers) SetupRoute() *echo.Echo {
 		{
 			roomsAPI.GET("", h.HandleGetRooms)
 			roomsAPI.POST("", h.HandlePostRoom)
+			roomsAPI.GET("/blackouts", h.HandleGetRoomBlackouts)
+			roomsAPI.GET("/series", h.HandleGetAllRoomSeries)
+			roomsAPI.GET("/places", h.HandleGetPlaceAttributes)
+			roomsAPI.GET("/availability", h.HandleGetRoomAvailability)
+			roomsAPI.PATCH("/admins", h.HandleUpdateRoomsAdmins)
+			roomsAPI.GET("/series/:seriesid", h.HandleGetRoomSeries)
 			roomsAPI.GET("/:roomid", h.HandleGetRoom)
+			roomsAPI.GET("/:roomid/events", h.HandleGetEventsByRoomID)
+			roomsAPI.POST("/:roomid/events/move", h.HandleMoveRoomEvents)
 			roomsAPI.DELETE("/:roomid", h.HandleDeleteRoom)
 
 			// サービス管理者権限が必要
 			roomsAPIWithPrivilegeAuth := roomsAPI.Group("", h.PrivilegeUserMiddleware)
 			{
 				roomsAPIWithPrivilegeAuth.POST("/all", h.HandleCreateVerifedRooms)
+				roomsAPIWithPrivilegeAuth.GET("/stats", h.HandleGetRoomStats)
+				roomsAPIWithPrivilegeAuth.POST("/blackouts", h.HandlePostRoomBlackout)
+				roomsAPIWithPrivilegeAuth.DELETE("/blackouts/:blackoutid", h.HandleDeleteRoomBlackout)
+				roomsAPIWithPrivilegeAuth.POST("/series", h.HandlePostRoomSeries)
+				roomsAPIWithPrivilegeAuth.PUT("/places/:place", h.HandlePutPlaceAttribute)
+				roomsAPIWithPrivilegeAuth.DELETE("/places/:place", h.HandleDeletePlaceAttribute)
+				roomsAPIWithPrivilegeAuth.PUT("/series/:seriesid", h.HandleUpdateRoomSeries)
+				roomsAPIWithPrivilegeAuth.DELETE("/series/:seriesid", h.HandleDeleteRoomSeries)
 				roomsAPIWithPrivilegeAuth.POST("/:roomid/verified", h.HandleVerifyRoom)
 				roomsAPIWithPrivilegeAuth.DELETE("/:roomid/verified", h.HandleUnVerifyRoom)
 			}
@@ -122,11 +182,12 @@ func (h *Handlers) SetupRoute() *echo.Echo {
 		usersAPI := apiWithAuth.Group("/users")
 		{
 			usersAPI.GET("", h.HandleGetUsers)
-			usersAPI.GET("/me", h.HandleGetUserMe)
 			usersAPI.GET("/me/ical", h.HandleGetiCal)
 			usersAPI.PUT("/me/ical", h.HandleUpdateiCal)
 			usersAPI.GET("/me/groups", h.HandleGetMeGroupIDs)
-			usersAPI.GET("/me/events", h.HandleGetMeEvents)
+			usersAPI.GET("/me/tokens", h.HandleGetMyPersonalAccessTokens)
+			usersAPI.POST("/me/tokens", h.HandlePostPersonalAccessToken)
+			usersAPI.DELETE("/me/tokens/:tokenid", h.HandleDeletePersonalAccessToken)
 			usersAPI.GET("/:userid/events", h.HandleGetEventsByUserID)
 			usersAPI.GET("/:userid/groups", h.HandleGetGroupIDsByUserID)
 
@@ -135,6 +196,7 @@ func (h *Handlers) SetupRoute() *echo.Echo {
 			{
 				usersAPIWithPrivilegeAuth.PATCH("/:userid/privileged", h.HandleGrantPrivilege)
 				usersAPIWithPrivilegeAuth.POST("/sync", h.HandleSyncUser)
+				usersAPIWithPrivilegeAuth.POST("/:userid/handover", h.HandleHandOverAdmins)
 			}
 		}
 
@@ -143,6 +205,23 @@ func (h *Handlers) SetupRoute() *echo.Echo {
 			tagsAPI.POST("", h.HandlePostTag)
 			tagsAPI.GET("", h.HandleGetTags)
 		}
+
+		oauth2API := apiWithAuth.Group("/oauth2")
+		{
+			oauth2API.GET("/authorize", h.HandleGetOAuthAuthorize)
+			oauth2API.POST("/authorize", h.HandlePostOAuthAuthorize)
+
+			// サービス管理者権限が必要
+			oauth2APIWithPrivilegeAuth := oauth2API.Group("", h.PrivilegeUserMiddleware)
+			{
+				oauth2APIWithPrivilegeAuth.GET("/clients", h.HandleGetOAuthClients)
+				oauth2APIWithPrivilegeAuth.POST("/clients", h.HandlePostOAuthClient)
+				oauth2APIWithPrivilegeAuth.DELETE("/clients/:clientid", h.HandleDeleteOAuthClient)
+			}
+		}
+
+		// traQ のキャッシュのヒット率などを確認する
+		apiWithAuth.GET("/debug/vars", echo.WrapHandler(expvar.Handler()), h.PrivilegeUserMiddleware)
 	}
 
 	e.Use(middleware.StaticWithConfig(middleware.StaticConfig{
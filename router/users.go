@@ -98,3 +98,22 @@ func (h *Handlers) HandleGrantPrivilege(c echo.Context) error {
 	}
 	return c.NoContent(http.StatusCreated)
 }
+
+// HandleHandOverAdmins 停止したユーザーが管理する部屋とイベントを後任に引き継ぐ
+func (h *Handlers) HandleHandOverAdmins(c echo.Context) error {
+	userID, err := getPathUserID(c)
+	if err != nil {
+		return notFound(err)
+	}
+	var req presentation.HandOverReq
+	if err := c.Bind(&req); err != nil {
+		return badRequest(err)
+	}
+
+	reqID := c.Get(userIDKey).(uuid.UUID)
+	result, err := h.Service.HandOverAdmins(c.Request().Context(), reqID, userID, req.SuccessorID)
+	if err != nil {
+		return judgeErrorResponse(err)
+	}
+	return c.JSON(http.StatusOK, presentation.HandOverRes(*result))
+}
@@ -14,6 +14,7 @@ var (
 	ErrTimeConsistency = errors.New("inconsistent time")
 	ErrRoomUndefined   = errors.New("invalid room or args")
 	ErrNoAdmins        = errors.New("no admins")
+	ErrGroupArchived   = errors.New("group is archived")
 )
 
 func handleTraQError(err error) error {
@@ -44,6 +45,9 @@ func handleDBError(err error) error {
 	if errors.Is(err, db.ErrNoAdmins) {
 		return fmt.Errorf("%w: %s", domain.ErrBadRequest, err)
 	}
+	if errors.Is(err, db.ErrGroupCycle) {
+		return fmt.Errorf("%w: %s", domain.ErrBadRequest, err)
+	}
 	if errors.Is(err, db.ErrDuplicateEntry) {
 		return fmt.Errorf("%w: %s", domain.ErrBadRequest, err)
 	}
@@ -71,6 +75,9 @@ func handleServiceError(err error) error {
 	if errors.Is(err, ErrNoAdmins) {
 		return fmt.Errorf("%w: %s", domain.ErrBadRequest, err)
 	}
+	if errors.Is(err, ErrGroupArchived) {
+		return fmt.Errorf("%w: %s", domain.ErrBadRequest, err)
+	}
 
 	return err
 }
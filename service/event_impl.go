@@ -8,12 +8,20 @@ import (
 	"github.com/traPtitech/knoQ/domain/filters"
 )
 
+// CreateEvent knoQ のグループのイベントはメンバー以上の役割か特権が必要
+// traQ のグループには役割がないので確認しない
 func (s *service) CreateEvent(ctx context.Context, reqID uuid.UUID, params domain.WriteEventParams) (*domain.Event, error) {
 	// groupの確認
 	group, err := s.GetGroup(ctx, params.GroupID)
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
+	if !group.IsTraQGroup && !group.HasRole(reqID, domain.GroupRoleMember) && !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	if group.Archived {
+		return nil, defaultErrorHandling(ErrGroupArchived)
+	}
 	if !params.TimeConsistency() {
 		return nil, ErrTimeConsistency
 	}
@@ -45,6 +53,11 @@ func (s *service) CreateEvent(ctx context.Context, reqID uuid.UUID, params domai
 			}
 		}
 
+		err = s.checkRoomTimeConsistency(ctx, p.RoomID, uuid.Nil, params)
+		if err != nil {
+			return err
+		}
+
 		eventResp, err = s.GormRepo.CreateEvent(ctx, p)
 		if err != nil {
 			return err
@@ -64,6 +77,25 @@ func (s *service) CreateEvent(ctx context.Context, reqID uuid.UUID, params domai
 	return s.GetEvent(ctx, eventResp.ID)
 }
 
+// checkRoomTimeConsistency イベントが部屋の使える時間帯に収まるか確認する
+// 更新の場合は eventID のイベントを除いて確認する
+func (s *service) checkRoomTimeConsistency(ctx context.Context, roomID uuid.UUID, eventID uuid.UUID, params domain.WriteEventParams) error {
+	room, err := s.GormRepo.GetRoom(ctx, roomID, eventID)
+	if err != nil {
+		return err
+	}
+	e := domain.Event{
+		Room:          *room,
+		TimeStart:     params.TimeStart,
+		TimeEnd:       params.TimeEnd,
+		AllowTogether: params.AllowTogether,
+	}
+	if !e.RoomTimeConsistency() {
+		return ErrTimeConsistency
+	}
+	return nil
+}
+
 func (s *service) UpdateEvent(ctx context.Context, reqID uuid.UUID, eventID uuid.UUID, params domain.WriteEventParams) (*domain.Event, error) {
 
 	if !s.IsEventAdmins(ctx, reqID, eventID) {
@@ -80,6 +112,10 @@ func (s *service) UpdateEvent(ctx context.Context, reqID uuid.UUID, eventID uuid
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
+	// 既存のイベントはグループがアーカイブされた後も編集できる
+	if group.Archived && currentEvent.Group.ID != params.GroupID {
+		return nil, defaultErrorHandling(ErrGroupArchived)
+	}
 	if !params.TimeConsistency() {
 		return nil, ErrTimeConsistency
 	}
@@ -117,7 +153,11 @@ func (s *service) UpdateEvent(ctx context.Context, reqID uuid.UUID, eventID uuid
 				}
 			}
 		}
-		var err error
+		err := s.checkRoomTimeConsistency(ctx, p.RoomID, eventID, params)
+		if err != nil {
+			return err
+		}
+
 		eventResp, err = s.GormRepo.UpdateEvent(ctx, eventID, p)
 		if err != nil {
 			return err
@@ -229,6 +269,7 @@ func (s *service) UpsertMeEventSchedule(ctx context.Context, reqID uuid.UUID, ev
 
 func (s *service) GetEvents(ctx context.Context, reqID uuid.UUID, expr filters.Expr) ([]*domain.Event, error) {
 
+	expr = addParentGroupIDs(ctx, s, expr)
 	expr = addTraQGroupIDs(ctx, s, reqID, expr)
 
 	es, err := s.GormRepo.GetAllEvents(ctx, expr)
@@ -240,6 +281,7 @@ func (s *service) GetEvents(ctx context.Context, reqID uuid.UUID, expr filters.E
 }
 
 func (s *service) GetEventsWithGroup(ctx context.Context, reqID uuid.UUID, expr filters.Expr) ([]*domain.Event, error) {
+	expr = addParentGroupIDs(ctx, s, expr)
 	expr = addTraQGroupIDs(ctx, s, reqID, expr)
 
 	events, err := s.GormRepo.GetAllEvents(ctx, expr)
@@ -248,7 +290,8 @@ func (s *service) GetEventsWithGroup(ctx context.Context, reqID uuid.UUID, expr
 	}
 
 	// add traQ groups and users
-	groups, err := s.GetAllGroups(ctx)
+	// アーカイブされたグループの過去のイベントも表示する
+	groups, err := s.GetAllGroups(ctx, true)
 	if err != nil {
 		return events, nil
 	}
@@ -288,7 +331,8 @@ func (s *service) IsEventAdmins(ctx context.Context, reqID uuid.UUID, eventID uu
 			return true
 		}
 	}
-	return false
+	// グループの organizer はグループのイベントを管理できる
+	return s.HasGroupRole(ctx, reqID, event.Group.ID, domain.GroupRoleOrganizer)
 }
 
 func createGroupMap(groups []*domain.Group) map[uuid.UUID]*domain.Group {
@@ -307,9 +351,57 @@ func createUserMap(users []*domain.User) map[uuid.UUID]*domain.User {
 	return userMap
 }
 
+// add knoQ parent groups
+// 子グループのメンバーは親グループのイベントにも belongs で一致する
+// belongs != の場合は親グループのイベントも除く
+func addParentGroupIDs(ctx context.Context, s *service, expr filters.Expr) filters.Expr {
+	var fixExpr func(filters.Expr) filters.Expr
+
+	fixExpr = func(expr filters.Expr) filters.Expr {
+		switch e := expr.(type) {
+		case *filters.CmpExpr:
+			if e.Attr != filters.AttrBelong || (e.Relation != filters.Eq && e.Relation != filters.Neq) {
+				return e
+			}
+			id, ok := e.Value.(uuid.UUID)
+			if !ok {
+				return e
+			}
+			groupIDs, err := s.GormRepo.GetBelongGroupIDs(ctx, id)
+			if err != nil || len(groupIDs) == 0 {
+				return e
+			}
+			if e.Relation == filters.Neq {
+				var excluded filters.Expr = e
+				for _, groupID := range groupIDs {
+					excluded = filters.AddAnd(excluded, &filters.CmpExpr{
+						Attr:     filters.AttrGroup,
+						Relation: filters.Neq,
+						Value:    groupID,
+					})
+				}
+				return excluded
+			}
+			return &filters.LogicOpExpr{
+				LogicOp: filters.Or,
+				LHS:     e,
+				RHS:     filters.FilterGroupIDs(groupIDs...),
+			}
+		case *filters.LogicOpExpr:
+			return &filters.LogicOpExpr{
+				LogicOp: e.LogicOp,
+				LHS:     fixExpr(e.LHS),
+				RHS:     fixExpr(e.RHS),
+			}
+		}
+		return nil
+	}
+	return fixExpr(expr)
+}
+
 // add traQ group and traP(111...)
 func addTraQGroupIDs(ctx context.Context, s *service, userID uuid.UUID, expr filters.Expr) filters.Expr {
-	t, err := s.GormRepo.GetToken(ctx, userID)
+	t, err := s.getToken(ctx, userID, false)
 	if err != nil {
 		return expr
 	}
@@ -333,7 +425,7 @@ func addTraQGroupIDs(ctx context.Context, s *service, userID uuid.UUID, expr fil
 				if err != nil {
 					return e
 				}
-				if user.Provider.Issuer == traQIssuerName {
+				if s.Providers.isTraQUser(user) {
 					groupIDs = append(groupIDs, traPGroupID)
 				}
 				return &filters.LogicOpExpr{
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/knoQ/domain/filters"
+)
+
+func Test_addParentGroupIDs(t *testing.T) {
+	user := uuid.Must(uuid.NewV4())
+	other := uuid.Must(uuid.NewV4())
+	group := uuid.Must(uuid.NewV4())
+	parent := uuid.Must(uuid.NewV4())
+	repo := newFakeRepository()
+	repo.belongGroupIDs[user] = []uuid.UUID{group, parent}
+	s := newFakeService(repo)
+
+	belongs := func(rel filters.Relation, id uuid.UUID) *filters.CmpExpr {
+		return &filters.CmpExpr{Attr: filters.AttrBelong, Relation: rel, Value: id}
+	}
+	groupIs := func(rel filters.Relation, id uuid.UUID) *filters.CmpExpr {
+		return &filters.CmpExpr{Attr: filters.AttrGroup, Relation: rel, Value: id}
+	}
+	eq := &filters.LogicOpExpr{
+		LogicOp: filters.Or,
+		LHS:     belongs(filters.Eq, user),
+		RHS:     &filters.LogicOpExpr{LogicOp: filters.Or, LHS: groupIs(filters.Eq, group), RHS: groupIs(filters.Eq, parent)},
+	}
+	neq := &filters.LogicOpExpr{
+		LogicOp: filters.And,
+		LHS:     &filters.LogicOpExpr{LogicOp: filters.And, LHS: belongs(filters.Neq, user), RHS: groupIs(filters.Neq, group)},
+		RHS:     groupIs(filters.Neq, parent),
+	}
+
+	tests := []struct {
+		name string
+		expr filters.Expr
+		want filters.Expr
+	}{
+		{"nil", nil, nil},
+		{"eq", belongs(filters.Eq, user), eq},
+		{"neq", belongs(filters.Neq, user), neq},
+		{"no groups", belongs(filters.Eq, other), belongs(filters.Eq, other)},
+		{
+			"nested",
+			&filters.LogicOpExpr{
+				LogicOp: filters.And,
+				LHS:     groupIs(filters.Neq, other),
+				RHS: &filters.LogicOpExpr{
+					LogicOp: filters.Or,
+					LHS:     belongs(filters.Eq, user),
+					RHS:     belongs(filters.Neq, user),
+				},
+			},
+			&filters.LogicOpExpr{
+				LogicOp: filters.And,
+				LHS:     groupIs(filters.Neq, other),
+				RHS:     &filters.LogicOpExpr{LogicOp: filters.Or, LHS: eq, RHS: neq},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, addParentGroupIDs(t.Context(), s, tt.expr))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/domain/filters"
+)
+
+// SyncTraQGroupAttendees 失敗したグループは飛ばして残りのグループを同期し、エラーはまとめて返す
+func (s *service) SyncTraQGroupAttendees(ctx context.Context) error {
+	events, err := s.GormRepo.GetAllEvents(ctx, filters.FilterTime(time.Now(), time.Time{}))
+	if err != nil {
+		return defaultErrorHandling(err)
+	}
+	eventsByGroup := make(map[uuid.UUID][]*domain.Event)
+	for _, e := range events {
+		eventsByGroup[e.Group.ID] = append(eventsByGroup[e.Group.ID], e)
+	}
+
+	var errs []error
+	for groupID, groupEvents := range eventsByGroup {
+		group, err := s.GetGroup(ctx, groupID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("group %s: %w", groupID, err))
+			continue
+		}
+		// knoQ のグループはメンバーが変わったときに同期している
+		if group == nil || !group.IsTraQGroup {
+			continue
+		}
+		err = s.TxManager.Do(ctx, func(ctx context.Context) error {
+			for _, e := range groupEvents {
+				err := s.syncEventAttendees(ctx, e, group.Members)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("group %s: %w", groupID, defaultErrorHandling(err)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncGroupEventAttendees グループのこれから始まるイベントの参加者をメンバーに合わせる
+// メンバーが変わった後に同じトランザクションの中で呼ぶ
+func (s *service) syncGroupEventAttendees(ctx context.Context, groupID uuid.UUID) error {
+	group, err := s.GormRepo.GetGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	expr := filters.AddAnd(filters.FilterGroupIDs(groupID), filters.FilterTime(time.Now(), time.Time{}))
+	events, err := s.GormRepo.GetAllEvents(ctx, expr)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		err = s.syncEventAttendees(ctx, e, group.Members)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncEventAttendees 参加者にいないメンバーを Pending で加え、メンバーでない参加者を外す
+// Open なイベントに自分で返答したメンバー以外の参加者は残す
+func (s *service) syncEventAttendees(ctx context.Context, event *domain.Event, members []domain.User) error {
+	isMember := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		isMember[m.ID] = true
+	}
+	isAttendee := make(map[uuid.UUID]bool, len(event.Attendees))
+	for _, a := range event.Attendees {
+		isAttendee[a.UserID] = true
+		if isMember[a.UserID] {
+			continue
+		}
+		if event.Open && a.Schedule != domain.Pending {
+			continue
+		}
+		err := s.GormRepo.DeleteEventSchedule(ctx, event.ID, a.UserID)
+		if err != nil {
+			return err
+		}
+	}
+	for _, m := range members {
+		if isAttendee[m.ID] {
+			continue
+		}
+		err := s.GormRepo.UpsertEventSchedule(ctx, event.ID, m.ID, domain.Pending)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+func TestService_syncEventAttendees(t *testing.T) {
+	stay := uuid.Must(uuid.NewV4())
+	joined := uuid.Must(uuid.NewV4())
+	left := uuid.Must(uuid.NewV4())
+	leftAttending := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name    string
+		open    bool
+		members []uuid.UUID
+		want    []domain.Attendee
+	}{
+		{
+			name:    "members join and leave",
+			members: []uuid.UUID{stay, joined},
+			want: []domain.Attendee{
+				{UserID: stay, Schedule: domain.Attendance},
+				{UserID: joined, Schedule: domain.Pending},
+			},
+		},
+		{
+			// Open なイベントに自分で返答した人はメンバーでなくても残す
+			name:    "open event keeps answered attendees",
+			open:    true,
+			members: []uuid.UUID{stay, joined},
+			want: []domain.Attendee{
+				{UserID: stay, Schedule: domain.Attendance},
+				{UserID: leftAttending, Schedule: domain.Attendance},
+				{UserID: joined, Schedule: domain.Pending},
+			},
+		},
+		{
+			name:    "all members leave",
+			members: []uuid.UUID{},
+			want:    []domain.Attendee{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			event := &domain.Event{
+				ID:   uuid.Must(uuid.NewV4()),
+				Open: tt.open,
+				Attendees: []domain.Attendee{
+					{UserID: stay, Schedule: domain.Attendance},
+					{UserID: left, Schedule: domain.Pending},
+					{UserID: leftAttending, Schedule: domain.Attendance},
+				},
+			}
+			repo.events[event.ID] = event
+			members := make([]domain.User, len(tt.members))
+			for i, id := range tt.members {
+				members[i] = domain.User{ID: id}
+			}
+
+			s := newFakeService(repo)
+			require.NoError(t, s.syncEventAttendees(t.Context(), event, members))
+			assert.ElementsMatch(t, tt.want, repo.events[event.ID].Attendees)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/domain/filters"
+	"github.com/traPtitech/knoQ/utils/random"
+)
+
+func (s *service) CreateGroupICalToken(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) (*domain.GroupICalToken, error) {
+	if !s.canManageGroupICal(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+	p := domain.CreateGroupICalTokenArgs{
+		GroupID:   groupID,
+		Token:     random.AlphaNumeric(32, true),
+		CreatedBy: reqID,
+	}
+
+	var token *domain.GroupICalToken
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		token, err = s.GormRepo.CreateGroupICalToken(ctx, p)
+		return err
+	})
+	return token, defaultErrorHandling(err)
+}
+
+func (s *service) GetGroupICalTokens(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*domain.GroupICalToken, error) {
+	if !s.canManageGroupICal(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+	tokens, err := s.GormRepo.GetGroupICalTokens(ctx, groupID)
+	return tokens, defaultErrorHandling(err)
+}
+
+func (s *service) DeleteGroupICalToken(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, tokenID uuid.UUID) error {
+	if !s.canManageGroupICal(ctx, reqID, groupID) {
+		return domain.ErrForbidden
+	}
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		token, err := s.GormRepo.GetGroupICalToken(ctx, tokenID)
+		if err != nil {
+			return err
+		}
+		if token.GroupID != groupID {
+			return domain.ErrNotFound
+		}
+		return s.GormRepo.DeleteGroupICalToken(ctx, tokenID)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (s *service) GetGroupICalEvents(ctx context.Context, groupID uuid.UUID, token string) ([]*domain.Event, error) {
+	if token == "" {
+		return nil, domain.ErrNotFound
+	}
+	tokens, err := s.GormRepo.GetGroupICalTokens(ctx, groupID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	valid := false
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, domain.ErrNotFound
+	}
+
+	events, err := s.GetEventsWithGroup(ctx, uuid.Nil, filters.FilterGroupIDs(groupID))
+	if err != nil {
+		return nil, err
+	}
+	// トークンは外部のカレンダーに渡すので、公開されているイベントだけを返す
+	return slices.DeleteFunc(events, func(e *domain.Event) bool {
+		return !e.Open
+	}), nil
+}
+
+// canManageGroupICal traQ のグループも含めてグループの管理者か確認する
+func (s *service) canManageGroupICal(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) bool {
+	if s.IsPrivilege(ctx, reqID) {
+		return true
+	}
+	group, err := s.GetGroup(ctx, groupID)
+	if err != nil || group == nil {
+		return false
+	}
+	for _, admin := range group.Admins {
+		if admin.ID == reqID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+func TestService_GetGroupICalEvents(t *testing.T) {
+	repo := newFakeRepository()
+	group := &domain.Group{ID: uuid.Must(uuid.NewV4())}
+	repo.groups[group.ID] = group
+	repo.icalTokens = append(repo.icalTokens, &domain.GroupICalToken{
+		ID:      uuid.Must(uuid.NewV4()),
+		GroupID: group.ID,
+		Token:   "token",
+	})
+	open := &domain.Event{ID: uuid.Must(uuid.NewV4()), Group: *group, Open: true}
+	closed := &domain.Event{ID: uuid.Must(uuid.NewV4()), Group: *group}
+	repo.events[open.ID] = open
+	repo.events[closed.ID] = closed
+	s := newFakeService(repo)
+
+	t.Run("open events only", func(t *testing.T) {
+		events, err := s.GetGroupICalEvents(t.Context(), group.ID, "token")
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, open.ID, events[0].ID)
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		_, err := s.GetGroupICalEvents(t.Context(), group.ID, "wrong")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/knoQ/domain"
@@ -12,6 +13,9 @@ import (
 var traPGroupID = uuid.Must(uuid.FromString("11111111-1111-1111-1111-111111111111"))
 
 func (s *service) CreateGroup(ctx context.Context, reqID uuid.UUID, params domain.WriteGroupParams) (*domain.Group, error) {
+	if err := s.checkGroupParent(ctx, reqID, params.ParentID); err != nil {
+		return nil, defaultErrorHandling(err)
+	}
 
 	p := domain.UpsertGroupArgs{
 		WriteGroupParams: params,
@@ -42,9 +46,21 @@ func (s *service) UpdateGroup(ctx context.Context, reqID uuid.UUID, groupID uuid
 	}
 	var groupResp *domain.Group
 	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
-		var err error
+		current, err := s.GormRepo.GetGroup(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if current.ParentID != params.ParentID {
+			err = s.checkGroupParent(ctx, reqID, params.ParentID)
+			if err != nil {
+				return err
+			}
+		}
 		groupResp, err = s.GormRepo.UpdateGroup(ctx, groupID, p)
-		return err
+		if err != nil {
+			return err
+		}
+		return s.syncGroupEventAttendees(ctx, groupID)
 	})
 	if err != nil {
 		return nil, defaultErrorHandling(err)
@@ -60,7 +76,11 @@ func (s *service) AddMeToGroup(ctx context.Context, reqID uuid.UUID, groupID uui
 		return domain.ErrForbidden
 	}
 	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
-		return s.GormRepo.AddMemberToGroup(ctx, groupID, reqID)
+		err := s.GormRepo.AddMemberToGroup(ctx, groupID, reqID)
+		if err != nil {
+			return err
+		}
+		return s.syncGroupEventAttendees(ctx, groupID)
 	})
 	return defaultErrorHandling(err)
 }
@@ -76,6 +96,34 @@ func (s *service) DeleteGroup(ctx context.Context, reqID uuid.UUID, groupID uuid
 	return defaultErrorHandling(err)
 }
 
+func (s *service) ArchiveGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) (*domain.Group, error) {
+	return s.updateGroupArchived(ctx, reqID, groupID, true)
+}
+
+func (s *service) UnArchiveGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) (*domain.Group, error) {
+	return s.updateGroupArchived(ctx, reqID, groupID, false)
+}
+
+func (s *service) updateGroupArchived(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, archived bool) (*domain.Group, error) {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+
+	var groupResp *domain.Group
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		err := s.GormRepo.UpdateGroupArchived(ctx, groupID, archived)
+		if err != nil {
+			return err
+		}
+		groupResp, err = s.GormRepo.GetGroup(ctx, groupID)
+		return err
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return groupResp, nil
+}
+
 // DeleteMeGroup delete me in that group if that group is open.
 func (s *service) DeleteMeGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) error {
 	if !s.IsGroupJoinFreely(ctx, groupID) {
@@ -83,11 +131,131 @@ func (s *service) DeleteMeGroup(ctx context.Context, reqID uuid.UUID, groupID uu
 	}
 
 	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
-		return s.GormRepo.DeleteMemberOfGroup(ctx, groupID, reqID)
+		err := s.GormRepo.DeleteMemberOfGroup(ctx, groupID, reqID)
+		if err != nil {
+			return err
+		}
+		return s.syncGroupEventAttendees(ctx, groupID)
 	})
 	return defaultErrorHandling(err)
 }
 
+func (s *service) AddMemberToGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*domain.Group, error) {
+	return s.updateGroupMembership(ctx, reqID, groupID, userID, domain.GroupAuditAddMember, s.GormRepo.AddMemberToGroup)
+}
+
+func (s *service) DeleteMemberOfGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*domain.Group, error) {
+	return s.updateGroupMembership(ctx, reqID, groupID, userID, domain.GroupAuditDeleteMember, s.GormRepo.DeleteMemberOfGroup)
+}
+
+func (s *service) AddAdminToGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*domain.Group, error) {
+	return s.updateGroupMembership(ctx, reqID, groupID, userID, domain.GroupAuditAddAdmin, s.GormRepo.AddAdminToGroup)
+}
+
+func (s *service) DeleteAdminOfGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID) (*domain.Group, error) {
+	return s.updateGroupMembership(ctx, reqID, groupID, userID, domain.GroupAuditDeleteAdmin, s.GormRepo.DeleteAdminOfGroup)
+}
+
+func (s *service) AddRoleToGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID, role domain.GroupRole) (*domain.Group, error) {
+	var action domain.GroupAuditAction
+	switch role {
+	case domain.GroupRoleOrganizer:
+		action = domain.GroupAuditAddOrganizer
+	case domain.GroupRoleViewer:
+		action = domain.GroupAuditAddViewer
+	default:
+		return nil, ErrInvalidArgs
+	}
+	return s.updateGroupMembership(ctx, reqID, groupID, userID, action, func(ctx context.Context, groupID, userID uuid.UUID) error {
+		return s.GormRepo.UpsertGroupRole(ctx, groupID, userID, role)
+	})
+}
+
+func (s *service) DeleteRoleOfGroup(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, userID uuid.UUID, role domain.GroupRole) (*domain.Group, error) {
+	var action domain.GroupAuditAction
+	switch role {
+	case domain.GroupRoleOrganizer:
+		action = domain.GroupAuditDeleteOrganizer
+	case domain.GroupRoleViewer:
+		action = domain.GroupAuditDeleteViewer
+	default:
+		return nil, ErrInvalidArgs
+	}
+	return s.updateGroupMembership(ctx, reqID, groupID, userID, action, func(ctx context.Context, groupID, userID uuid.UUID) error {
+		return s.GormRepo.DeleteGroupRole(ctx, groupID, userID, role)
+	})
+}
+
+// updateGroupMembership update でメンバー、管理者、役割のどれかを一人だけ変更し、変更履歴を残す
+func (s *service) updateGroupMembership(ctx context.Context, reqID, groupID, userID uuid.UUID, action domain.GroupAuditAction, update func(ctx context.Context, groupID, userID uuid.UUID) error) (*domain.Group, error) {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+	if userID == uuid.Nil {
+		return nil, ErrInvalidArgs
+	}
+
+	var groupResp *domain.Group
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		group, err := s.GormRepo.GetGroup(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		// 既に加わっている場合は変更履歴を残さない
+		if alreadyInGroup(group, action, userID) {
+			groupResp = group
+			return nil
+		}
+		err = update(ctx, groupID, userID)
+		if err != nil {
+			return err
+		}
+		err = s.GormRepo.CreateGroupAuditLog(ctx, domain.CreateGroupAuditLogArgs{
+			GroupID:   groupID,
+			Action:    action,
+			TargetID:  userID,
+			CreatedBy: reqID,
+		})
+		if err != nil {
+			return err
+		}
+		err = s.syncGroupEventAttendees(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		groupResp, err = s.GormRepo.GetGroup(ctx, groupID)
+		return err
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return groupResp, nil
+}
+
+// alreadyInGroup action が加える操作で、userID が既にその立場にあるか
+func alreadyInGroup(group *domain.Group, action domain.GroupAuditAction, userID uuid.UUID) bool {
+	var users []domain.User
+	switch action {
+	case domain.GroupAuditAddMember:
+		users = group.Members
+	case domain.GroupAuditAddAdmin:
+		users = group.Admins
+	case domain.GroupAuditAddOrganizer:
+		users = group.Organizers
+	case domain.GroupAuditAddViewer:
+		users = group.Viewers
+	}
+	return slices.ContainsFunc(users, func(u domain.User) bool { return u.ID == userID })
+}
+
+func (s *service) GetGroupAuditLogs(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*domain.GroupAuditLog, error) {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+	logs, err := s.GormRepo.GetGroupAuditLogs(ctx, groupID)
+	return logs, defaultErrorHandling(err)
+}
+
 func (s *service) GetGroup(ctx context.Context, groupID uuid.UUID) (*domain.Group, error) {
 	domainGroup, err := s.GormRepo.GetGroup(ctx, groupID)
 	if err == nil {
@@ -117,13 +285,18 @@ func (s *service) GetGroup(ctx context.Context, groupID uuid.UUID) (*domain.Grou
 	return &group, nil
 }
 
-func (s *service) GetAllGroups(ctx context.Context) ([]*domain.Group, error) {
+func (s *service) GetAllGroups(ctx context.Context, includeArchived bool) ([]*domain.Group, error) {
 	groups := make([]*domain.Group, 0)
 	gg, err := s.GormRepo.GetAllGroups(ctx)
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
-	groups = append(groups, gg...)
+	for _, g := range gg {
+		if g.Archived && !includeArchived {
+			continue
+		}
+		groups = append(groups, g)
+	}
 	tg, err := s.TraQRepo.GetAllGroups()
 	if err != nil {
 		return nil, defaultErrorHandling(err)
@@ -139,13 +312,27 @@ func (s *service) GetAllGroups(ctx context.Context) ([]*domain.Group, error) {
 	return groups, nil
 }
 
+func (s *service) GetSubgroupIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	groupIDs, err := s.GormRepo.GetSubgroupIDs(ctx, groupID)
+	return groupIDs, defaultErrorHandling(err)
+}
+
 func (s *service) GetUserBelongingGroupIDs(ctx context.Context, reqID uuid.UUID, userID uuid.UUID) ([]uuid.UUID, error) {
 
-	t, err := s.GormRepo.GetToken(ctx, reqID)
+	ggIDs, err := s.GormRepo.GetBelongGroupIDs(ctx, userID)
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
-	ggIDs, err := s.GormRepo.GetBelongGroupIDs(ctx, userID)
+	// ゲストユーザーは traQ のグループと traP に属さない
+	// まだ knoQ に保存されていない traQ のユーザーもいる
+	user, err := s.GormRepo.GetUser(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, defaultErrorHandling(err)
+	}
+	if err == nil && !s.Providers.isTraQUser(user) {
+		return ggIDs, nil
+	}
+	t, err := s.getToken(ctx, reqID, false)
 	if err != nil {
 		return nil, defaultErrorHandling(err)
 	}
@@ -161,17 +348,34 @@ func (s *service) GetUserAdminGroupIDs(ctx context.Context, userID uuid.UUID) ([
 	return s.GormRepo.GetAdminGroupIDs(ctx, userID)
 }
 
+// checkGroupParent 親グループを設定するには親グループの管理者である必要がある
+func (s *service) checkGroupParent(ctx context.Context, reqID uuid.UUID, parentID uuid.UUID) error {
+	if parentID == uuid.Nil {
+		return nil
+	}
+	parent, err := s.GormRepo.GetGroup(ctx, parentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidArgs
+	}
+	if err != nil {
+		return defaultErrorHandling(err)
+	}
+	if !parent.HasRole(reqID, domain.GroupRoleAdmin) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
 func (s *service) IsGroupAdmins(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) bool {
+	return s.HasGroupRole(ctx, reqID, groupID, domain.GroupRoleAdmin)
+}
+
+func (s *service) HasGroupRole(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, role domain.GroupRole) bool {
 	group, err := s.GormRepo.GetGroup(ctx, groupID)
 	if err != nil {
 		return false
 	}
-	for _, admin := range group.Admins {
-		if reqID == admin.ID {
-			return true
-		}
-	}
-	return false
+	return group.HasRole(reqID, role)
 }
 
 func (s *service) IsGroupJoinFreely(ctx context.Context, groupID uuid.UUID) bool {
@@ -197,10 +401,16 @@ func (s *service) IsGroupMember(ctx context.Context, userID, groupID uuid.UUID)
 }
 
 func (s *service) getTraPGroup(ctx context.Context) *domain.Group {
-	members, err := s.GetAllUsers(ctx, false, false)
+	users, err := s.GetAllUsers(ctx, false, false)
 	if err != nil {
 		return nil
 	}
+	members := make([]*domain.User, 0, len(users))
+	for _, u := range users {
+		if !u.Guest {
+			members = append(members, u)
+		}
+	}
 
 	return &domain.Group{
 		ID:          traPGroupID,
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+func TestService_groupParent(t *testing.T) {
+	admin := domain.User{ID: uuid.Must(uuid.NewV4())}
+	member := domain.User{ID: uuid.Must(uuid.NewV4())}
+
+	tests := []struct {
+		name     string
+		reqID    uuid.UUID
+		parentID func(parent *domain.Group) uuid.UUID
+		wantErr  error
+	}{
+		{"admin of parent", admin.ID, func(p *domain.Group) uuid.UUID { return p.ID }, nil},
+		{"member of parent", member.ID, func(p *domain.Group) uuid.UUID { return p.ID }, domain.ErrForbidden},
+		{"unknown parent", admin.ID, func(*domain.Group) uuid.UUID { return uuid.Must(uuid.NewV4()) }, domain.ErrBadRequest},
+		{"no parent", member.ID, func(*domain.Group) uuid.UUID { return uuid.Nil }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			parent := &domain.Group{
+				ID:      uuid.Must(uuid.NewV4()),
+				Members: []domain.User{member},
+				Admins:  []domain.User{admin},
+			}
+			repo.groups[parent.ID] = parent
+			// 子グループの管理者は reqID 本人
+			child := &domain.Group{
+				ID:     uuid.Must(uuid.NewV4()),
+				Admins: []domain.User{{ID: tt.reqID}},
+			}
+			repo.groups[child.ID] = child
+			s := newFakeService(repo)
+			params := domain.WriteGroupParams{Name: "child", ParentID: tt.parentID(parent)}
+
+			t.Run("create", func(t *testing.T) {
+				g, err := s.CreateGroup(t.Context(), tt.reqID, params)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, params.ParentID, g.ParentID)
+			})
+
+			t.Run("update", func(t *testing.T) {
+				g, err := s.UpdateGroup(t.Context(), tt.reqID, child.ID, params)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					assert.Equal(t, uuid.Nil, repo.groups[child.ID].ParentID)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, params.ParentID, g.ParentID)
+			})
+		})
+	}
+
+	t.Run("keep current parent", func(t *testing.T) {
+		repo := newFakeRepository()
+		parent := &domain.Group{ID: uuid.Must(uuid.NewV4()), Admins: []domain.User{admin}}
+		child := &domain.Group{ID: uuid.Must(uuid.NewV4()), ParentID: parent.ID, Admins: []domain.User{member}}
+		repo.groups[parent.ID] = parent
+		repo.groups[child.ID] = child
+		s := newFakeService(repo)
+
+		// 親を変えなければ親グループの管理者でなくても更新できる
+		_, err := s.UpdateGroup(t.Context(), member.ID, child.ID, domain.WriteGroupParams{Name: "renamed", ParentID: parent.ID})
+		require.NoError(t, err)
+	})
+}
+
+func TestService_groupRoles(t *testing.T) {
+	admin := domain.User{ID: uuid.Must(uuid.NewV4())}
+	organizer := domain.User{ID: uuid.Must(uuid.NewV4())}
+	member := domain.User{ID: uuid.Must(uuid.NewV4())}
+	viewer := domain.User{ID: uuid.Must(uuid.NewV4())}
+	setup := func() (*fakeRepository, *domain.Group) {
+		repo := newFakeRepository()
+		group := &domain.Group{
+			ID:         uuid.Must(uuid.NewV4()),
+			Members:    []domain.User{admin, member},
+			Admins:     []domain.User{admin},
+			Organizers: []domain.User{organizer},
+			Viewers:    []domain.User{viewer},
+		}
+		repo.groups[group.ID] = group
+		return repo, group
+	}
+
+	t.Run("audit logs and join requests", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			reqID   uuid.UUID
+			wantErr error
+		}{
+			{"admin", admin.ID, nil},
+			{"organizer", organizer.ID, domain.ErrForbidden},
+			{"member", member.ID, domain.ErrForbidden},
+			{"viewer", viewer.ID, domain.ErrForbidden},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo, group := setup()
+				s := newFakeService(repo)
+				_, err := s.GetGroupAuditLogs(t.Context(), tt.reqID, group.ID)
+				assert.ErrorIs(t, err, tt.wantErr)
+				_, err = s.GetGroupJoinRequests(t.Context(), tt.reqID, group.ID)
+				assert.ErrorIs(t, err, tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("create event", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			reqID   uuid.UUID
+			wantErr error
+		}{
+			// 権限の確認を通ると、部屋がないので ErrRoomUndefined になる
+			{"admin", admin.ID, ErrRoomUndefined},
+			{"organizer", organizer.ID, ErrRoomUndefined},
+			{"member", member.ID, ErrRoomUndefined},
+			{"viewer", viewer.ID, domain.ErrForbidden},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo, group := setup()
+				s := newFakeService(repo)
+				_, err := s.CreateEvent(t.Context(), tt.reqID, domain.WriteEventParams{
+					GroupID:   group.ID,
+					TimeStart: time.Now(),
+					TimeEnd:   time.Now().Add(time.Hour),
+				})
+				assert.ErrorIs(t, err, tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("delete missing role", func(t *testing.T) {
+		repo, group := setup()
+		s := newFakeService(repo)
+		_, err := s.DeleteRoleOfGroup(t.Context(), admin.ID, group.ID, member.ID, domain.GroupRoleOrganizer)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Empty(t, repo.auditLogs)
+
+		_, err = s.DeleteRoleOfGroup(t.Context(), admin.ID, group.ID, organizer.ID, domain.GroupRoleOrganizer)
+		require.NoError(t, err)
+		assert.Len(t, repo.auditLogs, 1)
+	})
+
+	t.Run("delete non-member", func(t *testing.T) {
+		repo, group := setup()
+		s := newFakeService(repo)
+		_, err := s.DeleteMemberOfGroup(t.Context(), admin.ID, group.ID, viewer.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Empty(t, repo.auditLogs)
+	})
+
+	t.Run("add existing member", func(t *testing.T) {
+		repo, group := setup()
+		s := newFakeService(repo)
+		g, err := s.AddMemberToGroup(t.Context(), admin.ID, group.ID, member.ID)
+		require.NoError(t, err)
+		assert.Len(t, g.Members, 2)
+		assert.Empty(t, repo.auditLogs)
+
+		_, err = s.AddMemberToGroup(t.Context(), admin.ID, group.ID, viewer.ID)
+		require.NoError(t, err)
+		assert.Len(t, repo.auditLogs, 1)
+	})
+}
+
+func TestService_GetUserBelongingGroupIDs(t *testing.T) {
+	guest := &domain.User{ID: uuid.Must(uuid.NewV4()), Provider: &domain.Provider{Issuer: "google"}}
+	group := uuid.Must(uuid.NewV4())
+	repo := newFakeRepository()
+	repo.users[guest.ID] = guest
+	repo.belongGroupIDs[guest.ID] = []uuid.UUID{group}
+	s := newFakeService(repo)
+
+	t.Run("guest", func(t *testing.T) {
+		ids, err := s.GetUserBelongingGroupIDs(t.Context(), guest.ID, guest.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{group}, ids)
+	})
+
+	t.Run("traQ user not saved yet", func(t *testing.T) {
+		// knoQ に無くても traQ のグループを取りに行く
+		_, err := s.GetUserBelongingGroupIDs(t.Context(), guest.ID, uuid.Must(uuid.NewV4()))
+		assert.ErrorIs(t, err, errFakeNoToken)
+	})
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/random"
+)
+
+func (s *service) CreateGroupInvitation(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, params domain.WriteGroupInvitationParams) (*domain.GroupInvitation, error) {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+	if !params.ExpiresAt.IsZero() && !params.ExpiresAt.After(time.Now()) {
+		return nil, ErrTimeConsistency
+	}
+	p := domain.CreateGroupInvitationArgs{
+		WriteGroupInvitationParams: params,
+		GroupID:                    groupID,
+		Token:                      random.AlphaNumeric(32, true),
+		CreatedBy:                  reqID,
+	}
+
+	var invitation *domain.GroupInvitation
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		invitation, err = s.GormRepo.CreateGroupInvitation(ctx, p)
+		return err
+	})
+	return invitation, defaultErrorHandling(err)
+}
+
+func (s *service) GetGroupInvitations(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*domain.GroupInvitation, error) {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+	invitations, err := s.GormRepo.GetGroupInvitations(ctx, groupID)
+	return invitations, defaultErrorHandling(err)
+}
+
+func (s *service) DeleteGroupInvitation(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, invitationID uuid.UUID) error {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return domain.ErrForbidden
+	}
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		invitation, err := s.GormRepo.GetGroupInvitation(ctx, invitationID)
+		if err != nil {
+			return err
+		}
+		if invitation.GroupID != groupID {
+			return domain.ErrNotFound
+		}
+		return s.GormRepo.DeleteGroupInvitation(ctx, invitationID)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (s *service) AcceptGroupInvitation(ctx context.Context, reqID uuid.UUID, token string) (*domain.Group, error) {
+	if token == "" {
+		return nil, ErrInvalidArgs
+	}
+	var groupID uuid.UUID
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		invitation, err := s.GormRepo.GetGroupInvitationByToken(ctx, token)
+		if err != nil {
+			return err
+		}
+		if invitation.Expired(time.Now()) {
+			return domain.ErrForbidden
+		}
+		if invitation.Invitee != uuid.Nil {
+			if invitation.Invitee != reqID {
+				return domain.ErrForbidden
+			}
+			// 個人宛ての招待は一度だけ使える
+			err = s.GormRepo.DeleteGroupInvitation(ctx, invitation.ID)
+			if err != nil {
+				return err
+			}
+		}
+		groupID = invitation.GroupID
+		err = s.GormRepo.AddMemberToGroup(ctx, invitation.GroupID, reqID)
+		if err != nil {
+			return err
+		}
+		return s.syncGroupEventAttendees(ctx, invitation.GroupID)
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return s.GetGroup(ctx, groupID)
+}
+
+func (s *service) CreateGroupJoinRequest(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, params domain.WriteGroupJoinRequestParams) (*domain.GroupJoinRequest, error) {
+	p := domain.CreateGroupJoinRequestArgs{
+		WriteGroupJoinRequestParams: params,
+		GroupID:                     groupID,
+		UserID:                      reqID,
+	}
+
+	var request *domain.GroupJoinRequest
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		group, err := s.GormRepo.GetGroup(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		// JoinFreely なグループは申請せずに参加できる
+		if group.JoinFreely {
+			return ErrInvalidArgs
+		}
+		for _, member := range group.Members {
+			if member.ID == reqID {
+				return ErrInvalidArgs
+			}
+		}
+		requests, err := s.GormRepo.GetGroupJoinRequests(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		for _, r := range requests {
+			if r.User.ID == reqID {
+				return ErrInvalidArgs
+			}
+		}
+		request, err = s.GormRepo.CreateGroupJoinRequest(ctx, p)
+		return err
+	})
+	return request, defaultErrorHandling(err)
+}
+
+func (s *service) GetGroupJoinRequests(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID) ([]*domain.GroupJoinRequest, error) {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+	requests, err := s.GormRepo.GetGroupJoinRequests(ctx, groupID)
+	return requests, defaultErrorHandling(err)
+}
+
+func (s *service) ApproveGroupJoinRequest(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, requestID uuid.UUID) (*domain.GroupJoinRequest, error) {
+	return s.resolveGroupJoinRequest(ctx, reqID, groupID, requestID, true)
+}
+
+func (s *service) RejectGroupJoinRequest(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, requestID uuid.UUID) (*domain.GroupJoinRequest, error) {
+	return s.resolveGroupJoinRequest(ctx, reqID, groupID, requestID, false)
+}
+
+// resolveGroupJoinRequest 申請を消し、approve の場合はメンバーに加える
+func (s *service) resolveGroupJoinRequest(ctx context.Context, reqID uuid.UUID, groupID uuid.UUID, requestID uuid.UUID, approve bool) (*domain.GroupJoinRequest, error) {
+	if !s.IsGroupAdmins(ctx, reqID, groupID) {
+		return nil, domain.ErrForbidden
+	}
+
+	var request *domain.GroupJoinRequest
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.GormRepo.GetGroupJoinRequest(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		if request.GroupID != groupID {
+			return domain.ErrNotFound
+		}
+		if approve {
+			err = s.GormRepo.AddMemberToGroup(ctx, groupID, request.User.ID)
+			if err != nil {
+				return err
+			}
+			err = s.syncGroupEventAttendees(ctx, groupID)
+			if err != nil {
+				return err
+			}
+		}
+		return s.GormRepo.DeleteGroupJoinRequest(ctx, requestID)
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return request, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"gorm.io/gorm"
+)
+
+func (s *service) GetLoginProviders(ctx context.Context) []string {
+	return s.Providers.names()
+}
+
+func (s *service) GetProviderOAuthURL(ctx context.Context, provider string) (url, state string, err error) {
+	p, ok := s.Providers.get(provider)
+	if !ok {
+		return "", "", domain.ErrNotFound
+	}
+	url, state = p.GetOAuthURL()
+	return url, state, nil
+}
+
+func (s *service) LoginProviderUser(ctx context.Context, provider, query, state string) (*domain.User, error) {
+	p, ok := s.Providers.get(provider)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	subject, profile, err := p.Authenticate(query, state)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+
+	userID := uuid.Must(uuid.NewV4())
+	existing, err := s.GormRepo.GetUserByProvider(ctx, p.Issuer(), subject)
+	if err == nil {
+		userID = existing.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, defaultErrorHandling(err)
+	}
+
+	user := domain.SaveUserArgs{
+		UserID: userID,
+		State:  1,
+		ProviderArgs: domain.ProviderArgs{
+			Issuer:  p.Issuer(),
+			Subject: subject,
+		},
+		Profile: profile,
+	}
+	err = s.TxManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.GormRepo.SaveUser(ctx, user)
+		return err
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	u, err := s.GetUser(ctx, userID)
+	return u, defaultErrorHandling(err)
+}
+
+// guestUser ゲストユーザーの名前とアイコンは knoQ に保存したものを使う
+func guestUser(userMeta *domain.User) *domain.User {
+	return &domain.User{
+		ID:          userMeta.ID,
+		Name:        userMeta.Name,
+		DisplayName: userMeta.DisplayName,
+		Icon:        userMeta.Icon,
+		Privileged:  userMeta.Privileged,
+		State:       userMeta.State,
+		Guest:       true,
+	}
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid"
+	"github.com/samber/lo"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/infra/traq"
+	"github.com/traPtitech/knoQ/utils/random"
+	"gorm.io/gorm"
+)
+
+const (
+	oauthAccessTokenPrefix         = "knoqo_"
+	oauthRefreshTokenPrefix        = "knoqr_"
+	oauthAuthorizationCodeLifetime = 10 * time.Minute
+	oauthAccessTokenLifetime       = time.Hour
+)
+
+func invalidOAuthClient() error {
+	return &domain.OAuthError{Code: "invalid_client", Description: "client authentication failed"}
+}
+
+func invalidOAuthGrant(description string) error {
+	return &domain.OAuthError{Code: "invalid_grant", Description: description}
+}
+
+func (s *service) CreateOAuthClient(ctx context.Context, reqID uuid.UUID, params domain.WriteOAuthClientParams) (*domain.OAuthClient, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	if params.Name == "" || utf8.RuneCountInString(params.Name) > 64 ||
+		len(params.RedirectURIs) == 0 || len(params.Scopes) == 0 {
+		return nil, ErrInvalidArgs
+	}
+	for _, uri := range params.RedirectURIs {
+		if !validOAuthRedirectURI(uri) {
+			return nil, ErrInvalidArgs
+		}
+	}
+	for _, scope := range params.Scopes {
+		if !scope.Valid() {
+			return nil, ErrInvalidArgs
+		}
+	}
+	params.RedirectURIs = lo.Uniq(params.RedirectURIs)
+	params.Scopes = lo.Uniq(params.Scopes)
+	args := domain.CreateOAuthClientArgs{
+		WriteOAuthClientParams: params,
+		CreatedBy:              reqID,
+	}
+	if params.Confidential {
+		args.Secret = random.AlphaNumeric(40, true)
+	}
+
+	var client *domain.OAuthClient
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		client, err = s.GormRepo.CreateOAuthClient(ctx, args)
+		return err
+	})
+	return client, defaultErrorHandling(err)
+}
+
+// validOAuthRedirectURI 同意画面から移動するので javascript: などは使わせない
+// https、ループバックへの http、ネイティブアプリの private-use スキーム (RFC 8252 7.1) だけを許可する
+func validOAuthRedirectURI(uri string) bool {
+	if strings.ContainsFunc(uri, unicode.IsControl) {
+		return false
+	}
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Opaque != "" || u.User != nil || u.Fragment != "" {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		if u.Hostname() == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(u.Hostname())
+		return ip != nil && ip.IsLoopback()
+	}
+	// private-use スキームは逆ドメイン名にする
+	return strings.Contains(u.Scheme, ".")
+}
+
+func (s *service) GetOAuthClients(ctx context.Context, reqID uuid.UUID) ([]*domain.OAuthClient, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	clients, err := s.GormRepo.GetAllOAuthClients(ctx)
+	return clients, defaultErrorHandling(err)
+}
+
+func (s *service) DeleteOAuthClient(ctx context.Context, reqID uuid.UUID, clientID uuid.UUID) error {
+	if !s.IsPrivilege(ctx, reqID) {
+		return domain.ErrForbidden
+	}
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.GormRepo.GetOAuthClient(ctx, clientID); err != nil {
+			return err
+		}
+		return s.GormRepo.DeleteOAuthClient(ctx, clientID)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (s *service) GetOAuthConsent(ctx context.Context, _ uuid.UUID, params domain.OAuthAuthorizeParams) (*domain.OAuthConsent, error) {
+	consent, err := s.checkOAuthAuthorizeParams(ctx, params)
+	return consent, defaultErrorHandling(err)
+}
+
+func (s *service) AuthorizeOAuthClient(ctx context.Context, reqID uuid.UUID, params domain.OAuthAuthorizeParams, approved bool) (string, error) {
+	consent, err := s.checkOAuthAuthorizeParams(ctx, params)
+	if err != nil {
+		return "", defaultErrorHandling(err)
+	}
+	u, err := url.Parse(consent.RedirectURI)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	if approved {
+		code := random.AlphaNumeric(40, true)
+		err = s.TxManager.Do(ctx, func(ctx context.Context) error {
+			return s.GormRepo.CreateOAuthAuthorizationCode(ctx, domain.CreateOAuthAuthorizationCodeArgs{
+				OAuthAuthorizationCode: domain.OAuthAuthorizationCode{
+					ClientID: consent.Client.ID,
+					UserID:   reqID,
+					// トークンリクエストの redirect_uri と比べるので、省略された場合は空のまま
+					RedirectURI:   params.RedirectURI,
+					Scopes:        consent.Scopes,
+					CodeChallenge: params.CodeChallenge,
+					ExpiresAt:     time.Now().Add(oauthAuthorizationCodeLifetime),
+				},
+				Code: code,
+			})
+		})
+		if err != nil {
+			return "", defaultErrorHandling(err)
+		}
+		q.Set("code", code)
+	} else {
+		q.Set("error", "access_denied")
+	}
+	if params.State != "" {
+		q.Set("state", params.State)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// checkOAuthAuthorizeParams スコープが省略された場合はクライアントのスコープ全て
+// redirect_uri は登録されたものが一つだけの場合に省略できる
+func (s *service) checkOAuthAuthorizeParams(ctx context.Context, params domain.OAuthAuthorizeParams) (*domain.OAuthConsent, error) {
+	client, err := s.GormRepo.GetOAuthClient(ctx, params.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	redirectURI := params.RedirectURI
+	if redirectURI == "" {
+		if len(client.RedirectURIs) != 1 {
+			return nil, ErrInvalidArgs
+		}
+		redirectURI = client.RedirectURIs[0]
+	} else if !slices.Contains(client.RedirectURIs, redirectURI) {
+		return nil, ErrInvalidArgs
+	}
+	scopes := params.Scopes
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			return nil, ErrInvalidArgs
+		}
+	}
+	if params.CodeChallenge == "" || params.CodeChallengeMethod != "S256" {
+		return nil, ErrInvalidArgs
+	}
+	return &domain.OAuthConsent{
+		Client:      client,
+		RedirectURI: redirectURI,
+		Scopes:      lo.Uniq(scopes),
+	}, nil
+}
+
+func (s *service) ExchangeOAuthToken(ctx context.Context, req domain.OAuthTokenRequest) (*domain.OAuthToken, error) {
+	client, err := s.authenticateOAuthClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	switch req.GrantType {
+	case "authorization_code":
+		return s.exchangeOAuthAuthorizationCode(ctx, client, req)
+	case "refresh_token":
+		return s.refreshOAuthToken(ctx, client, req)
+	}
+	return nil, &domain.OAuthError{Code: "unsupported_grant_type", Description: req.GrantType}
+}
+
+// authenticateOAuthClient 公開クライアントは client_id だけで認証する
+func (s *service) authenticateOAuthClient(ctx context.Context, clientID uuid.UUID, secret string) (*domain.OAuthClient, error) {
+	client, err := s.GormRepo.GetOAuthClient(ctx, clientID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, invalidOAuthClient()
+	}
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	if !client.Confidential {
+		return client, nil
+	}
+	ok, err := s.GormRepo.VerifyOAuthClientSecret(ctx, clientID, secret)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	if !ok {
+		return nil, invalidOAuthClient()
+	}
+	return client, nil
+}
+
+func (s *service) exchangeOAuthAuthorizationCode(ctx context.Context, client *domain.OAuthClient, req domain.OAuthTokenRequest) (*domain.OAuthToken, error) {
+	// 検証に失敗しても認可コードは使えなくする
+	var code *domain.OAuthAuthorizationCode
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		code, err = s.GormRepo.TakeOAuthAuthorizationCode(ctx, req.Code)
+		return err
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, invalidOAuthGrant("invalid authorization code")
+	}
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	if code.ClientID != client.ID || code.RedirectURI != req.RedirectURI {
+		return nil, invalidOAuthGrant("authorization code was issued to another client or redirect_uri")
+	}
+	if !time.Now().Before(code.ExpiresAt) {
+		return nil, invalidOAuthGrant("authorization code has expired")
+	}
+	if !validPKCEVerifier(req.CodeVerifier) {
+		return nil, invalidOAuthGrant("invalid code_verifier")
+	}
+	if traq.PKCEChallenge(req.CodeVerifier) != code.CodeChallenge {
+		return nil, invalidOAuthGrant("invalid code_verifier")
+	}
+
+	var token *domain.OAuthToken
+	err = s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		token, err = s.createOAuthToken(ctx, code.ClientID, code.UserID, code.Scopes)
+		return err
+	})
+	return token, defaultErrorHandling(err)
+}
+
+// validPKCEVerifier RFC 7636 4.1 の code_verifier の形式か
+func validPKCEVerifier(verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	for _, c := range verifier {
+		if !('A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.ContainsRune("-._~", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshOAuthToken リフレッシュトークンは使うたびに新しくする
+func (s *service) refreshOAuthToken(ctx context.Context, client *domain.OAuthClient, req domain.OAuthTokenRequest) (*domain.OAuthToken, error) {
+	if !strings.HasPrefix(req.RefreshToken, oauthRefreshTokenPrefix) {
+		return nil, invalidOAuthGrant("invalid refresh token")
+	}
+	var token *domain.OAuthToken
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		old, err := s.GormRepo.GetOAuthTokenByRefreshToken(ctx, req.RefreshToken)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && old.ClientID != client.ID) {
+			return invalidOAuthGrant("invalid refresh token")
+		}
+		if err != nil {
+			return err
+		}
+		// 同じリフレッシュトークンで同時に更新された場合は片方だけが成功する
+		err = s.GormRepo.DeleteOAuthToken(ctx, old.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalidOAuthGrant("invalid refresh token")
+		}
+		if err != nil {
+			return err
+		}
+		token, err = s.createOAuthToken(ctx, old.ClientID, old.UserID, old.Scopes)
+		return err
+	})
+	return token, defaultErrorHandling(err)
+}
+
+func (s *service) createOAuthToken(ctx context.Context, clientID, userID uuid.UUID, scopes []domain.AccessTokenScope) (*domain.OAuthToken, error) {
+	return s.GormRepo.CreateOAuthToken(ctx, domain.CreateOAuthTokenArgs{
+		ClientID:     clientID,
+		UserID:       userID,
+		Scopes:       scopes,
+		AccessToken:  oauthAccessTokenPrefix + random.AlphaNumeric(40, true),
+		RefreshToken: oauthRefreshTokenPrefix + random.AlphaNumeric(40, true),
+		ExpiresAt:    time.Now().Add(oauthAccessTokenLifetime),
+	})
+}
+
+// IntrospectOAuthToken トークンを探られないように、confidential なクライアントだけが使える
+func (s *service) IntrospectOAuthToken(ctx context.Context, clientID uuid.UUID, clientSecret, token string) (*domain.OAuthToken, error) {
+	client, err := s.authenticateOAuthClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Confidential {
+		return nil, invalidOAuthClient()
+	}
+	t, err := s.AuthenticateOAuthToken(ctx, token)
+	if errors.Is(err, domain.ErrUnAuthorized) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.ClientID != client.ID {
+		return nil, nil
+	}
+	return t, nil
+}
+
+func (s *service) AuthenticateOAuthToken(ctx context.Context, token string) (*domain.OAuthToken, error) {
+	if !strings.HasPrefix(token, oauthAccessTokenPrefix) {
+		return nil, domain.ErrUnAuthorized
+	}
+	t, err := s.GormRepo.GetOAuthTokenByAccessToken(ctx, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrUnAuthorized
+	}
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	if t.Expired(time.Now()) {
+		return nil, domain.ErrUnAuthorized
+	}
+	return t, nil
+}
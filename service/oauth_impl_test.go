@@ -0,0 +1,164 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/infra/traq"
+)
+
+func assertOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *domain.OAuthError
+	if assert.ErrorAs(t, err, &oauthErr) {
+		assert.Equal(t, code, oauthErr.Code)
+	}
+}
+
+func TestService_CreateOAuthClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		wantErr bool
+	}{
+		{"https", "https://app.example.com/callback", false},
+		{"loopback", "http://127.0.0.1:8080/callback", false},
+		{"localhost", "http://localhost/callback", false},
+		{"private-use scheme", "com.example.app:/callback", false},
+		{"http", "http://app.example.com/callback", true},
+		{"javascript", "javascript:alert(document.cookie)", true},
+		{"data", "data:text/html,<script>alert(1)</script>", true},
+		{"userinfo", "https://user@app.example.com/callback", true},
+		{"fragment", "https://app.example.com/callback#a", true},
+		{"relative", "/callback", true},
+		{"newline", "https://app.example.com/\ncallback", true},
+	}
+	admin := &domain.User{ID: uuid.Must(uuid.NewV4()), Privileged: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			repo.users[admin.ID] = admin
+			s := newFakeService(repo)
+			_, err := s.CreateOAuthClient(t.Context(), admin.ID, domain.WriteOAuthClientParams{
+				Name:         "app",
+				RedirectURIs: []string{tt.uri},
+				Scopes:       []domain.AccessTokenScope{domain.AccessTokenScopeReadEvents},
+			})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidArgs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_OAuthClientPrivilege(t *testing.T) {
+	admin := &domain.User{ID: uuid.Must(uuid.NewV4()), Privileged: true}
+	user := &domain.User{ID: uuid.Must(uuid.NewV4())}
+	params := domain.WriteOAuthClientParams{
+		Name:         "app",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		Scopes:       []domain.AccessTokenScope{domain.AccessTokenScopeReadEvents},
+	}
+
+	tests := []struct {
+		name    string
+		reqID   uuid.UUID
+		wantErr error
+	}{
+		{"privileged", admin.ID, nil},
+		{"not privileged", user.ID, domain.ErrForbidden},
+		{"unknown user", uuid.Must(uuid.NewV4()), domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			repo.users[admin.ID] = admin
+			repo.users[user.ID] = user
+			s := newFakeService(repo)
+			existing, err := s.CreateOAuthClient(t.Context(), admin.ID, params)
+			require.NoError(t, err)
+
+			_, err = s.CreateOAuthClient(t.Context(), tt.reqID, params)
+			assert.ErrorIs(t, err, tt.wantErr)
+			_, err = s.GetOAuthClients(t.Context(), tt.reqID)
+			assert.ErrorIs(t, err, tt.wantErr)
+			err = s.DeleteOAuthClient(t.Context(), tt.reqID, existing.ID)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_ExchangeOAuthToken(t *testing.T) {
+	client := &domain.OAuthClient{ID: uuid.Must(uuid.NewV4())}
+	user := uuid.Must(uuid.NewV4())
+
+	t.Run("authorization code", func(t *testing.T) {
+		verifier := strings.Repeat("a", 43)
+		tests := []struct {
+			name     string
+			verifier string
+			wantErr  bool
+		}{
+			{"valid", verifier, false},
+			{"too short", strings.Repeat("a", 42), true},
+			{"too long", strings.Repeat("a", 129), true},
+			{"invalid character", strings.Repeat("a", 42) + "+", true},
+			{"empty", "", true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := newFakeRepository()
+				repo.oauthClients[client.ID] = client
+				// 検証しないと空の code_verifier でも code_challenge と一致してしまう
+				repo.oauthCodes["code"] = &domain.OAuthAuthorizationCode{
+					ClientID:      client.ID,
+					UserID:        user,
+					CodeChallenge: traq.PKCEChallenge(tt.verifier),
+					ExpiresAt:     time.Now().Add(time.Minute),
+				}
+				s := newFakeService(repo)
+
+				token, err := s.ExchangeOAuthToken(t.Context(), domain.OAuthTokenRequest{
+					GrantType:    "authorization_code",
+					ClientID:     client.ID,
+					Code:         "code",
+					CodeVerifier: tt.verifier,
+				})
+				if tt.wantErr {
+					assertOAuthError(t, err, "invalid_grant")
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, user, token.UserID)
+			})
+		}
+	})
+
+	t.Run("refresh twice with the same token", func(t *testing.T) {
+		repo := newFakeRepository()
+		repo.oauthClients[client.ID] = client
+		s := newFakeService(repo)
+		old, err := s.createOAuthToken(t.Context(), client.ID, user, nil)
+		require.NoError(t, err)
+		req := domain.OAuthTokenRequest{
+			GrantType:    "refresh_token",
+			ClientID:     client.ID,
+			RefreshToken: old.RefreshToken,
+		}
+
+		token, err := s.ExchangeOAuthToken(t.Context(), req)
+		require.NoError(t, err)
+		assert.NotEqual(t, old.RefreshToken, token.RefreshToken)
+
+		_, err = s.ExchangeOAuthToken(t.Context(), req)
+		assertOAuthError(t, err, "invalid_grant")
+		assert.Len(t, repo.oauthTokens, 2)
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid"
+	"github.com/samber/lo"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/random"
+	"gorm.io/gorm"
+)
+
+const (
+	personalAccessTokenPrefix = "knoq_"
+	// personalAccessTokenLastUsedInterval 使った時刻はこれより細かく記録しない
+	personalAccessTokenLastUsedInterval = time.Minute
+)
+
+func (s *service) CreatePersonalAccessToken(ctx context.Context, reqID uuid.UUID, params domain.WritePersonalAccessTokenParams) (*domain.PersonalAccessToken, error) {
+	if params.Name == "" || utf8.RuneCountInString(params.Name) > 64 || len(params.Scopes) == 0 {
+		return nil, ErrInvalidArgs
+	}
+	for _, scope := range params.Scopes {
+		if !scope.Valid() {
+			return nil, ErrInvalidArgs
+		}
+	}
+	if !params.ExpiresAt.IsZero() && !params.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrTimeHasPassed
+	}
+	params.Scopes = lo.Uniq(params.Scopes)
+	args := domain.CreatePersonalAccessTokenArgs{
+		WritePersonalAccessTokenParams: params,
+		UserID:                         reqID,
+		Token:                          personalAccessTokenPrefix + random.AlphaNumeric(40, true),
+	}
+
+	var token *domain.PersonalAccessToken
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		token, err = s.GormRepo.CreatePersonalAccessToken(ctx, args)
+		return err
+	})
+	return token, defaultErrorHandling(err)
+}
+
+func (s *service) GetMyPersonalAccessTokens(ctx context.Context, reqID uuid.UUID) ([]*domain.PersonalAccessToken, error) {
+	tokens, err := s.GormRepo.GetPersonalAccessTokens(ctx, reqID)
+	return tokens, defaultErrorHandling(err)
+}
+
+func (s *service) RevokePersonalAccessToken(ctx context.Context, reqID uuid.UUID, tokenID uuid.UUID) error {
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		token, err := s.GormRepo.GetPersonalAccessToken(ctx, tokenID)
+		if err != nil {
+			return err
+		}
+		if token.UserID != reqID {
+			return domain.ErrNotFound
+		}
+		return s.GormRepo.DeletePersonalAccessToken(ctx, tokenID)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (s *service) AuthenticatePersonalAccessToken(ctx context.Context, token string) (*domain.PersonalAccessToken, error) {
+	if !strings.HasPrefix(token, personalAccessTokenPrefix) {
+		return nil, domain.ErrUnAuthorized
+	}
+	t, err := s.GormRepo.GetPersonalAccessTokenByToken(ctx, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrUnAuthorized
+	}
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	now := time.Now()
+	if t.Expired(now) {
+		return nil, domain.ErrUnAuthorized
+	}
+	if now.Sub(t.LastUsedAt) >= personalAccessTokenLastUsedInterval {
+		err = s.TxManager.Do(ctx, func(ctx context.Context) error {
+			return s.GormRepo.UpdatePersonalAccessTokenLastUsedAt(ctx, t.ID, now)
+		})
+		if err != nil {
+			return nil, defaultErrorHandling(err)
+		}
+		t.LastUsedAt = now
+	}
+	return t, nil
+}
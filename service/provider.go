@@ -0,0 +1,57 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/traPtitech/knoQ/domain"
+)
+
+// LoginProvider traQ 以外でログインに使うプロバイダ
+// ここで認証したユーザーはゲストとして扱う
+type LoginProvider interface {
+	// Issuer domain.Provider.Issuer に保存する値
+	Issuer() string
+	GetOAuthURL() (url, state string)
+	// Authenticate コールバックのクエリからユーザーの subject とプロフィールを得る
+	Authenticate(query, state string) (subject string, profile *domain.ProfileArgs, err error)
+}
+
+// ProviderRegistry ログインに使うプロバイダ
+// traQ の Issuer もここに登録し、それ以外で認証したユーザーはゲストとして扱う
+type ProviderRegistry struct {
+	traQIssuer string
+	// guests 名前はログインの URL (/api/authParams/:provider) に使う
+	guests map[string]LoginProvider
+}
+
+func NewProviderRegistry(traQIssuer string) *ProviderRegistry {
+	return &ProviderRegistry{
+		traQIssuer: traQIssuer,
+		guests:     make(map[string]LoginProvider),
+	}
+}
+
+// Register name でゲストがログインできるようにする
+func (r *ProviderRegistry) Register(name string, p LoginProvider) {
+	r.guests[name] = p
+}
+
+func (r *ProviderRegistry) get(name string) (LoginProvider, bool) {
+	p, ok := r.guests[name]
+	return p, ok
+}
+
+func (r *ProviderRegistry) names() []string {
+	names := make([]string, 0, len(r.guests))
+	for name := range r.guests {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// isTraQUser traQ で認証したユーザーか
+// 設定から外れたプロバイダのユーザーも、保存したプロフィールを持つゲストとして扱う
+func (r *ProviderRegistry) isTraQUser(user *domain.User) bool {
+	return user.Provider == nil || user.Provider.Issuer == r.traQIssuer
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+func TestProviderRegistry_isTraQUser(t *testing.T) {
+	r := NewProviderRegistry("traQ")
+	r.Register("google", nil)
+
+	tests := []struct {
+		name     string
+		provider *domain.Provider
+		want     bool
+	}{
+		{"no provider", nil, true},
+		{"traQ", &domain.Provider{Issuer: "traQ"}, true},
+		{"registered guest provider", &domain.Provider{Issuer: "google"}, false},
+		{"removed provider", &domain.Provider{Issuer: "https://idp.example.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.isTraQUser(&domain.User{Provider: tt.provider}))
+		})
+	}
+	assert.Equal(t, []string{"google"}, r.names())
+}
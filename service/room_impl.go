@@ -6,12 +6,16 @@ import (
 
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/domain/filters"
 )
 
 func (s *service) CreateUnVerifiedRoom(ctx context.Context, reqID uuid.UUID, params domain.WriteRoomParams) (*domain.Room, error) {
 	if !params.TimeConsistency() {
 		return nil, ErrTimeConsistency
 	}
+	if params.SharedCapacity < 0 {
+		return nil, ErrInvalidArgs
+	}
 	p := domain.CreateRoomArgs{
 		WriteRoomParams: params,
 		Verified:        false,
@@ -34,6 +38,9 @@ func (s *service) CreateVerifiedRoom(ctx context.Context, reqID uuid.UUID, param
 	if !params.TimeConsistency() {
 		return nil, ErrTimeConsistency
 	}
+	if params.SharedCapacity < 0 {
+		return nil, ErrInvalidArgs
+	}
 	p := domain.CreateRoomArgs{
 		WriteRoomParams: params,
 		Verified:        true,
@@ -65,6 +72,9 @@ func (s *service) UpdateRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.U
 	if !params.TimeConsistency() {
 		return nil, ErrTimeConsistency
 	}
+	if params.SharedCapacity < 0 {
+		return nil, ErrInvalidArgs
+	}
 
 	var roomResp *domain.Room
 	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
@@ -88,28 +98,133 @@ func (s *service) VerifyRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.U
 	return defaultErrorHandling(err)
 }
 
-func (s *service) UnVerifyRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) error {
+func (s *service) UnVerifyRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) ([]*domain.Event, error) {
 	if !s.IsPrivilege(ctx, reqID) {
-		return domain.ErrForbidden
+		return nil, domain.ErrForbidden
 	}
+	var events []*domain.Event
 	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
 		err := s.GormRepo.UpdateRoomVerified(ctx, roomID, false)
+		if err != nil {
+			return err
+		}
+		events, err = s.GormRepo.GetAllEvents(ctx, filters.FilterRoomIDs(roomID))
 		return err
 	})
 
-	return defaultErrorHandling(err)
+	return events, defaultErrorHandling(err)
+}
+
+func (s *service) UpdateRoomsAdmins(ctx context.Context, reqID uuid.UUID, params domain.WriteRoomAdminsParams) ([]*domain.Room, error) {
+	if len(params.RoomIDs) == 0 || len(params.Admins) == 0 {
+		return nil, ErrInvalidArgs
+	}
+	if !s.IsPrivilege(ctx, reqID) {
+		for _, roomID := range params.RoomIDs {
+			if !s.IsRoomAdmins(ctx, reqID, roomID) {
+				return nil, domain.ErrForbidden
+			}
+		}
+	}
+
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		for _, roomID := range params.RoomIDs {
+			err := s.GormRepo.UpdateRoomAdmins(ctx, roomID, params.Admins, params.Replace)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+
+	rooms := make([]*domain.Room, 0, len(params.RoomIDs))
+	for _, roomID := range params.RoomIDs {
+		room, err := s.GetRoom(ctx, roomID, uuid.Nil)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+	return rooms, nil
 }
 
-func (s *service) DeleteRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) error {
+func (s *service) DeleteRoom(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID) ([]*domain.Event, error) {
 	if !s.IsRoomAdmins(ctx, reqID, roomID) {
-		return domain.ErrForbidden
+		return nil, domain.ErrForbidden
 	}
+	var events []*domain.Event
 	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
-		err := s.GormRepo.DeleteRoom(ctx, roomID)
-		return err
+		var err error
+		events, err = s.GormRepo.GetAllEvents(ctx, filters.FilterRoomIDs(roomID))
+		if err != nil {
+			return err
+		}
+		return s.GormRepo.DeleteRoom(ctx, roomID)
 	})
 
-	return defaultErrorHandling(err)
+	return events, defaultErrorHandling(err)
+}
+
+// MoveRoomEvents 移す元と移す先の両方の部屋の管理者であれば、イベントの管理者でなくても移せる
+func (s *service) MoveRoomEvents(ctx context.Context, reqID uuid.UUID, roomID uuid.UUID, params domain.MoveRoomEventsParams) ([]*domain.Event, error) {
+	if !s.IsPrivilege(ctx, reqID) &&
+		(!s.IsRoomAdmins(ctx, reqID, roomID) || !s.IsRoomAdmins(ctx, reqID, params.ToRoomID)) {
+		return nil, domain.ErrForbidden
+	}
+	if roomID == params.ToRoomID {
+		return nil, ErrInvalidArgs
+	}
+
+	moved := make([]*domain.Event, 0)
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		events, err := s.GormRepo.GetAllEvents(ctx, filters.FilterRoomIDs(roomID))
+		if err != nil {
+			return err
+		}
+		if len(params.EventIDs) > 0 {
+			selected := make([]*domain.Event, 0, len(params.EventIDs))
+			for _, e := range events {
+				for _, id := range params.EventIDs {
+					if e.ID == id {
+						selected = append(selected, e)
+						break
+					}
+				}
+			}
+			// 部屋にないイベントが指定された
+			if len(selected) != len(params.EventIDs) {
+				return ErrInvalidArgs
+			}
+			events = selected
+		}
+		target, err := s.GormRepo.GetRoom(ctx, params.ToRoomID, uuid.Nil)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			// 先に移したイベントとも重ならないか確認する
+			m := *e
+			m.Room = *target
+			if !m.RoomTimeConsistency() {
+				return ErrTimeConsistency
+			}
+			err = s.GormRepo.UpdateEventRoom(ctx, e.ID, target.ID)
+			if err != nil {
+				return err
+			}
+			target.Events = append(target.Events, m)
+			moved = append(moved, &m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return moved, nil
 }
 
 func (s *service) GetRoom(ctx context.Context, roomID uuid.UUID, excludeEventID uuid.UUID) (*domain.Room, error) {
@@ -117,8 +232,18 @@ func (s *service) GetRoom(ctx context.Context, roomID uuid.UUID, excludeEventID
 	return rs, defaultErrorHandling(err)
 }
 
-func (s *service) GetAllRooms(ctx context.Context, start time.Time, end time.Time, excludeEventID uuid.UUID, onlyVerified bool) ([]*domain.Room, error) {
-	rs, err := s.GormRepo.GetAllRooms(ctx, start, end, excludeEventID, onlyVerified)
+func (s *service) GetAllRooms(ctx context.Context, start time.Time, end time.Time, excludeEventID uuid.UUID, onlyVerified bool, attr domain.RoomAttributeFilter) ([]*domain.Room, error) {
+	rs, err := s.GormRepo.GetAllRooms(ctx, start, end, excludeEventID, onlyVerified, attr)
+	return rs, defaultErrorHandling(err)
+}
+
+func (s *service) GetRoomsByPlace(ctx context.Context, place string, start time.Time, end time.Time) ([]*domain.Room, error) {
+	rs, err := s.GormRepo.GetRoomsByPlace(ctx, place, start, end)
+	return rs, defaultErrorHandling(err)
+}
+
+func (s *service) GetOverlappingRooms(ctx context.Context, start time.Time, end time.Time) ([]*domain.Room, error) {
+	rs, err := s.GormRepo.GetOverlappingRooms(ctx, start, end)
 	return rs, defaultErrorHandling(err)
 }
 
@@ -134,3 +259,86 @@ func (s *service) IsRoomAdmins(ctx context.Context, reqID uuid.UUID, roomID uuid
 	}
 	return false
 }
+
+func (s *service) GetRoomStats(ctx context.Context, reqID uuid.UUID, since, until time.Time, groupBy domain.RoomStatsGroupBy, onlyVerified bool) ([]*domain.RoomStats, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return nil, ErrTimeConsistency
+	}
+	stats, err := s.GormRepo.GetRoomStats(ctx, since, until, groupBy, onlyVerified)
+	return stats, defaultErrorHandling(err)
+}
+
+func (s *service) CreateRoomBlackout(ctx context.Context, reqID uuid.UUID, params domain.WriteRoomBlackoutParams) (*domain.RoomBlackout, []*domain.Event, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, nil, domain.ErrForbidden
+	}
+	if !params.TimeConsistency() {
+		return nil, nil, ErrTimeConsistency
+	}
+	// 部屋と場所のどちらか一方を指定する
+	if (params.RoomID == uuid.Nil) == (params.Place == "") {
+		return nil, nil, ErrInvalidArgs
+	}
+	p := domain.CreateRoomBlackoutArgs{
+		WriteRoomBlackoutParams: params,
+		CreatedBy:               reqID,
+	}
+
+	var blackout *domain.RoomBlackout
+	var events []*domain.Event
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		blackout, err = s.GormRepo.CreateRoomBlackout(ctx, p)
+		if err != nil {
+			return err
+		}
+		events, err = s.GormRepo.GetEventsInRoomBlackout(ctx, blackout.ID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, defaultErrorHandling(err)
+	}
+	return blackout, events, nil
+}
+
+func (s *service) DeleteRoomBlackout(ctx context.Context, reqID uuid.UUID, blackoutID uuid.UUID) error {
+	if !s.IsPrivilege(ctx, reqID) {
+		return domain.ErrForbidden
+	}
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		return s.GormRepo.DeleteRoomBlackout(ctx, blackoutID)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (s *service) GetRoomBlackouts(ctx context.Context, start time.Time, end time.Time) ([]*domain.RoomBlackout, error) {
+	bs, err := s.GormRepo.GetRoomBlackouts(ctx, start, end)
+	return bs, defaultErrorHandling(err)
+}
+
+func (s *service) UpsertPlaceAttribute(ctx context.Context, reqID uuid.UUID, params domain.PlaceAttribute) (*domain.PlaceAttribute, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	if params.Place == "" || params.Capacity < 0 || params.SharedCapacity < 0 {
+		return nil, ErrInvalidArgs
+	}
+	attr, err := s.GormRepo.UpsertPlaceAttribute(ctx, params)
+	return attr, defaultErrorHandling(err)
+}
+
+func (s *service) DeletePlaceAttribute(ctx context.Context, reqID uuid.UUID, place string) error {
+	if !s.IsPrivilege(ctx, reqID) {
+		return domain.ErrForbidden
+	}
+	err := s.GormRepo.DeletePlaceAttribute(ctx, place)
+	return defaultErrorHandling(err)
+}
+
+func (s *service) GetPlaceAttributes(ctx context.Context) ([]*domain.PlaceAttribute, error) {
+	attrs, err := s.GormRepo.GetPlaceAttributes(ctx)
+	return attrs, defaultErrorHandling(err)
+}
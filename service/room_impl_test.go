@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traPtitech/knoQ/domain"
+)
+
+func TestService_MoveRoomEvents(t *testing.T) {
+	now := time.Now()
+	privileged := domain.User{ID: uuid.Must(uuid.NewV4()), Privileged: true}
+	bothAdmin := domain.User{ID: uuid.Must(uuid.NewV4())}
+	fromAdmin := domain.User{ID: uuid.Must(uuid.NewV4())}
+	eventAdmin := domain.User{ID: uuid.Must(uuid.NewV4())}
+
+	tests := []struct {
+		name    string
+		reqID   uuid.UUID
+		wantErr error
+	}{
+		{"privileged", privileged.ID, nil},
+		// 部屋の管理者はイベントの管理者でなくても移せる
+		{"admin of both rooms", bothAdmin.ID, nil},
+		{"not admin of destination", fromAdmin.ID, domain.ErrForbidden},
+		{"event admin only", eventAdmin.ID, domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			for _, u := range []domain.User{privileged, bothAdmin, fromAdmin, eventAdmin} {
+				repo.users[u.ID] = &u
+			}
+			from := &domain.Room{
+				ID:        uuid.Must(uuid.NewV4()),
+				TimeStart: now,
+				TimeEnd:   now.Add(3 * time.Hour),
+				Admins:    []domain.User{bothAdmin, fromAdmin},
+			}
+			to := &domain.Room{
+				ID:        uuid.Must(uuid.NewV4()),
+				TimeStart: now,
+				TimeEnd:   now.Add(3 * time.Hour),
+				Admins:    []domain.User{bothAdmin},
+			}
+			repo.rooms[from.ID] = from
+			repo.rooms[to.ID] = to
+			event := &domain.Event{
+				ID:        uuid.Must(uuid.NewV4()),
+				Room:      *from,
+				TimeStart: now.Add(time.Hour),
+				TimeEnd:   now.Add(2 * time.Hour),
+				Admins:    []domain.User{eventAdmin},
+			}
+			repo.events[event.ID] = event
+
+			s := newFakeService(repo)
+			moved, err := s.MoveRoomEvents(t.Context(), tt.reqID, from.ID, domain.MoveRoomEventsParams{ToRoomID: to.ID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, from.ID, event.Room.ID)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, moved, 1)
+			assert.Equal(t, to.ID, event.Room.ID)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/tz"
+)
+
+// checkRoomSeriesEvents 変更した部屋に予約されたイベントが収まるか確認する
+func (s *service) checkRoomSeriesEvents(ctx context.Context, roomID uuid.UUID) error {
+	room, err := s.GormRepo.GetRoom(ctx, roomID, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	if !room.EventsTimeConsistency() {
+		return ErrTimeConsistency
+	}
+	return nil
+}
+
+// deleteRoomSeriesRoom イベントが予約された部屋は消さない
+func (s *service) deleteRoomSeriesRoom(ctx context.Context, room domain.Room) error {
+	r, err := s.GormRepo.GetRoom(ctx, room.ID, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	if len(r.Events) > 0 {
+		return ErrTimeConsistency
+	}
+	return s.GormRepo.DeleteRoom(ctx, room.ID)
+}
+
+func validateRoomSeriesParams(params domain.WriteRoomSeriesParams) error {
+	if !params.TimeConsistency() {
+		return ErrTimeConsistency
+	}
+	n := len(params.Occurrences())
+	if n == 0 || n > domain.MaxRoomSeriesOccurrences {
+		return ErrInvalidArgs
+	}
+	return nil
+}
+
+func (s *service) CreateRoomSeries(ctx context.Context, reqID uuid.UUID, params domain.WriteRoomSeriesParams) (*domain.RoomSeries, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	if err := validateRoomSeriesParams(params); err != nil {
+		return nil, err
+	}
+
+	var seriesID uuid.UUID
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		series, err := s.GormRepo.CreateRoomSeries(ctx, domain.UpsertRoomSeriesArgs{
+			WriteRoomSeriesParams: params,
+			CreatedBy:             reqID,
+		})
+		if err != nil {
+			return err
+		}
+		seriesID = series.ID
+		for _, o := range params.Occurrences() {
+			_, err = s.GormRepo.CreateRoom(ctx, domain.CreateRoomArgs{
+				WriteRoomParams: domain.WriteRoomParams{
+					Place:     params.Place,
+					TimeStart: o.TimeStart,
+					TimeEnd:   o.TimeEnd,
+					Admins:    params.Admins,
+				},
+				Verified:  true,
+				SeriesID:  series.ID,
+				CreatedBy: reqID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return s.GetRoomSeries(ctx, seriesID)
+}
+
+// UpdateRoomSeries 終わった部屋はそのまま残す
+// 同じ日の部屋は更新し、イベントとの紐づけを保つ
+// 予約されたイベントが収まらなくなる場合や、予約のある部屋がなくなる場合は何も変更しない
+func (s *service) UpdateRoomSeries(ctx context.Context, reqID uuid.UUID, seriesID uuid.UUID, params domain.WriteRoomSeriesParams) (*domain.RoomSeries, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	if err := validateRoomSeriesParams(params); err != nil {
+		return nil, err
+	}
+
+	dateFormat := "2006-01-02"
+	now := time.Now()
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		current, err := s.GormRepo.GetRoomSeries(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		_, err = s.GormRepo.UpdateRoomSeries(ctx, seriesID, domain.UpsertRoomSeriesArgs{
+			WriteRoomSeriesParams: params,
+			CreatedBy:             current.CreatedBy.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		futureRooms := make(map[string]domain.Room)
+		for _, room := range current.Rooms {
+			if room.TimeEnd.After(now) {
+				futureRooms[room.TimeStart.In(tz.JST).Format(dateFormat)] = room
+			}
+		}
+		for _, o := range params.Occurrences() {
+			if !o.TimeEnd.After(now) {
+				continue
+			}
+			roomParams := domain.WriteRoomParams{
+				Place:     params.Place,
+				TimeStart: o.TimeStart,
+				TimeEnd:   o.TimeEnd,
+				Admins:    params.Admins,
+			}
+			date := o.TimeStart.In(tz.JST).Format(dateFormat)
+			if room, ok := futureRooms[date]; ok {
+				delete(futureRooms, date)
+				// 部屋ごとに設定した共用イベントの数は引き継ぐ
+				roomParams.SharedCapacity = room.SharedCapacity
+				_, err = s.GormRepo.UpdateRoom(ctx, room.ID, domain.UpdateRoomArgs{
+					WriteRoomParams: roomParams,
+					CreatedBy:       reqID,
+				})
+				if err == nil {
+					err = s.checkRoomSeriesEvents(ctx, room.ID)
+				}
+			} else {
+				_, err = s.GormRepo.CreateRoom(ctx, domain.CreateRoomArgs{
+					WriteRoomParams: roomParams,
+					Verified:        true,
+					SeriesID:        seriesID,
+					CreatedBy:       reqID,
+				})
+			}
+			if err != nil {
+				return err
+			}
+		}
+		for _, room := range futureRooms {
+			err = s.deleteRoomSeriesRoom(ctx, room)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	return s.GetRoomSeries(ctx, seriesID)
+}
+
+// DeleteRoomSeries 終わった部屋はそのまま残す
+// まだ終わっていない部屋にイベントが予約されている場合は何も削除しない
+func (s *service) DeleteRoomSeries(ctx context.Context, reqID uuid.UUID, seriesID uuid.UUID) error {
+	if !s.IsPrivilege(ctx, reqID) {
+		return domain.ErrForbidden
+	}
+
+	now := time.Now()
+	err := s.TxManager.Do(ctx, func(ctx context.Context) error {
+		series, err := s.GormRepo.GetRoomSeries(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		for _, room := range series.Rooms {
+			if !room.TimeEnd.After(now) {
+				continue
+			}
+			err = s.deleteRoomSeriesRoom(ctx, room)
+			if err != nil {
+				return err
+			}
+		}
+		return s.GormRepo.DeleteRoomSeries(ctx, seriesID)
+	})
+	return defaultErrorHandling(err)
+}
+
+func (s *service) GetRoomSeries(ctx context.Context, seriesID uuid.UUID) (*domain.RoomSeries, error) {
+	series, err := s.GormRepo.GetRoomSeries(ctx, seriesID)
+	return series, defaultErrorHandling(err)
+}
+
+func (s *service) GetAllRoomSeries(ctx context.Context) ([]*domain.RoomSeries, error) {
+	series, err := s.GormRepo.GetAllRoomSeries(ctx)
+	return series, defaultErrorHandling(err)
+}
@@ -6,13 +6,15 @@ import (
 )
 
 type service struct {
-	GormRepo  domain.Repository
-	TraQRepo  *traq.TraQRepository
+	GormRepo domain.Repository
+	TraQRepo *traq.TraQRepository
+	// Providers ログインに使うプロバイダ
+	Providers *ProviderRegistry
 	TxManager domain.TransactionManager
 }
 
 // implements domain
 
-func NewService(repo domain.Repository, traqRepo *traq.TraQRepository, txManager domain.TransactionManager) domain.Service {
-	return &service{GormRepo: repo, TraQRepo: traqRepo, TxManager: txManager}
+func NewService(repo domain.Repository, traqRepo *traq.TraQRepository, providers *ProviderRegistry, txManager domain.TransactionManager) domain.Service {
+	return &service{GormRepo: repo, TraQRepo: traqRepo, Providers: providers, TxManager: txManager}
 }
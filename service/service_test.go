@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/domain/filters"
+	"github.com/traPtitech/knoQ/infra/traq"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// fakeRepository テストで使うメソッドだけを map で実装する
+type fakeRepository struct {
+	domain.Repository
+	users  map[uuid.UUID]*domain.User
+	groups map[uuid.UUID]*domain.Group
+	rooms  map[uuid.UUID]*domain.Room
+	events map[uuid.UUID]*domain.Event
+	// belongGroupIDs ユーザーが所属するグループと祖先のグループ
+	belongGroupIDs map[uuid.UUID][]uuid.UUID
+	auditLogs      []domain.CreateGroupAuditLogArgs
+	icalTokens     []*domain.GroupICalToken
+	oauthClients   map[uuid.UUID]*domain.OAuthClient
+	oauthCodes     map[string]*domain.OAuthAuthorizationCode
+	// oauthTokens リフレッシュトークンから引く
+	oauthTokens map[string]*domain.OAuthToken
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		users:          make(map[uuid.UUID]*domain.User),
+		groups:         make(map[uuid.UUID]*domain.Group),
+		rooms:          make(map[uuid.UUID]*domain.Room),
+		events:         make(map[uuid.UUID]*domain.Event),
+		belongGroupIDs: make(map[uuid.UUID][]uuid.UUID),
+		oauthClients:   make(map[uuid.UUID]*domain.OAuthClient),
+		oauthCodes:     make(map[string]*domain.OAuthAuthorizationCode),
+		oauthTokens:    make(map[string]*domain.OAuthToken),
+	}
+}
+
+var (
+	errFakeNoToken  = errors.New("fake: no token")
+	errFakeNoGroups = errors.New("fake: no groups")
+)
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newFakeService(repo *fakeRepository) *service {
+	return &service{GormRepo: repo, Providers: NewProviderRegistry(traq.IssuerName), TxManager: fakeTxManager{}}
+}
+
+func (r *fakeRepository) GetUser(_ context.Context, userID uuid.UUID) (*domain.User, error) {
+	u, ok := r.users[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+// GetToken traQ のトークンは持たない
+func (r *fakeRepository) GetToken(_ context.Context, _ uuid.UUID) (*oauth2.Token, error) {
+	return nil, errFakeNoToken
+}
+
+func (r *fakeRepository) GetRoom(_ context.Context, roomID uuid.UUID, excludeEventID uuid.UUID) (*domain.Room, error) {
+	room, ok := r.rooms[roomID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	res := *room
+	res.Events = make([]domain.Event, 0)
+	for _, e := range r.events {
+		if e.Room.ID == roomID && e.ID != excludeEventID {
+			res.Events = append(res.Events, *e)
+		}
+	}
+	return &res, nil
+}
+
+// GetAllEvents 部屋での絞り込みだけに対応する
+func (r *fakeRepository) GetAllEvents(_ context.Context, expr filters.Expr) ([]*domain.Event, error) {
+	events := make([]*domain.Event, 0)
+	for _, e := range r.events {
+		if cmp, ok := expr.(*filters.CmpExpr); ok && cmp.Attr == filters.AttrRoom && cmp.Value != e.Room.ID {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func (r *fakeRepository) UpdateEventRoom(_ context.Context, eventID, roomID uuid.UUID) error {
+	e, ok := r.events[eventID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	e.Room = *r.rooms[roomID]
+	return nil
+}
+
+func (r *fakeRepository) UpsertEventSchedule(_ context.Context, eventID, userID uuid.UUID, scheduleStatus domain.ScheduleStatus) error {
+	e, ok := r.events[eventID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	for i := range e.Attendees {
+		if e.Attendees[i].UserID == userID {
+			e.Attendees[i].Schedule = scheduleStatus
+			return nil
+		}
+	}
+	e.Attendees = append(e.Attendees, domain.Attendee{UserID: userID, Schedule: scheduleStatus})
+	return nil
+}
+
+func (r *fakeRepository) DeleteEventSchedule(_ context.Context, eventID, userID uuid.UUID) error {
+	e, ok := r.events[eventID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	e.Attendees = slices.DeleteFunc(slices.Clone(e.Attendees), func(a domain.Attendee) bool {
+		return a.UserID == userID
+	})
+	return nil
+}
+
+func (r *fakeRepository) GetGroup(_ context.Context, groupID uuid.UUID) (*domain.Group, error) {
+	g, ok := r.groups[groupID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	res := *g
+	return &res, nil
+}
+
+func (r *fakeRepository) CreateGroup(_ context.Context, args domain.UpsertGroupArgs) (*domain.Group, error) {
+	g := &domain.Group{
+		ID:          uuid.Must(uuid.NewV4()),
+		Name:        args.Name,
+		Description: args.Description,
+		JoinFreely:  args.JoinFreely,
+		ParentID:    args.ParentID,
+	}
+	r.groups[g.ID] = g
+	return g, nil
+}
+
+func (r *fakeRepository) UpdateGroup(_ context.Context, groupID uuid.UUID, args domain.UpsertGroupArgs) (*domain.Group, error) {
+	g, ok := r.groups[groupID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	g.Name = args.Name
+	g.Description = args.Description
+	g.JoinFreely = args.JoinFreely
+	g.ParentID = args.ParentID
+	return g, nil
+}
+
+// GetAllGroups traQ のグループを扱えないので一覧は返さない
+func (r *fakeRepository) GetAllGroups(_ context.Context) ([]*domain.Group, error) {
+	return nil, errFakeNoGroups
+}
+
+func (r *fakeRepository) GetBelongGroupIDs(_ context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return r.belongGroupIDs[userID], nil
+}
+
+func (r *fakeRepository) AddMemberToGroup(_ context.Context, groupID, userID uuid.UUID) error {
+	g, ok := r.groups[groupID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if !slices.ContainsFunc(g.Members, func(u domain.User) bool { return u.ID == userID }) {
+		g.Members = append(slices.Clone(g.Members), domain.User{ID: userID})
+	}
+	return nil
+}
+
+func (r *fakeRepository) DeleteMemberOfGroup(_ context.Context, groupID, userID uuid.UUID) error {
+	g, ok := r.groups[groupID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	n := len(g.Members)
+	g.Members = slices.DeleteFunc(slices.Clone(g.Members), func(u domain.User) bool {
+		return u.ID == userID
+	})
+	if len(g.Members) == n {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *fakeRepository) DeleteGroupRole(_ context.Context, groupID, userID uuid.UUID, role domain.GroupRole) error {
+	g, ok := r.groups[groupID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	users := &g.Organizers
+	if role == domain.GroupRoleViewer {
+		users = &g.Viewers
+	}
+	n := len(*users)
+	*users = slices.DeleteFunc(slices.Clone(*users), func(u domain.User) bool {
+		return u.ID == userID
+	})
+	if len(*users) == n {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *fakeRepository) CreateGroupAuditLog(_ context.Context, args domain.CreateGroupAuditLogArgs) error {
+	r.auditLogs = append(r.auditLogs, args)
+	return nil
+}
+
+func (r *fakeRepository) GetGroupAuditLogs(_ context.Context, _ uuid.UUID) ([]*domain.GroupAuditLog, error) {
+	return []*domain.GroupAuditLog{}, nil
+}
+
+func (r *fakeRepository) GetGroupJoinRequests(_ context.Context, _ uuid.UUID) ([]*domain.GroupJoinRequest, error) {
+	return []*domain.GroupJoinRequest{}, nil
+}
+
+func (r *fakeRepository) GetGroupICalTokens(_ context.Context, groupID uuid.UUID) ([]*domain.GroupICalToken, error) {
+	tokens := make([]*domain.GroupICalToken, 0)
+	for _, t := range r.icalTokens {
+		if t.GroupID == groupID {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeRepository) GetOAuthClient(_ context.Context, clientID uuid.UUID) (*domain.OAuthClient, error) {
+	c, ok := r.oauthClients[clientID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return c, nil
+}
+
+func (r *fakeRepository) TakeOAuthAuthorizationCode(_ context.Context, code string) (*domain.OAuthAuthorizationCode, error) {
+	c, ok := r.oauthCodes[code]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(r.oauthCodes, code)
+	return c, nil
+}
+
+func (r *fakeRepository) CreateOAuthToken(_ context.Context, args domain.CreateOAuthTokenArgs) (*domain.OAuthToken, error) {
+	t := &domain.OAuthToken{
+		ID:           uuid.Must(uuid.NewV4()),
+		ClientID:     args.ClientID,
+		UserID:       args.UserID,
+		Scopes:       args.Scopes,
+		AccessToken:  args.AccessToken,
+		RefreshToken: args.RefreshToken,
+		ExpiresAt:    args.ExpiresAt,
+	}
+	r.oauthTokens[args.RefreshToken] = t
+	return t, nil
+}
+
+// GetOAuthTokenByRefreshToken 削除したトークンも返し、同時に更新したリクエストが削除前の行を読んだ状態を再現する
+func (r *fakeRepository) GetOAuthTokenByRefreshToken(_ context.Context, refreshToken string) (*domain.OAuthToken, error) {
+	t, ok := r.oauthTokens[refreshToken]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (r *fakeRepository) DeleteOAuthToken(_ context.Context, tokenID uuid.UUID) error {
+	for _, t := range r.oauthTokens {
+		if t.ID == tokenID && t.DeletedAt == nil {
+			now := time.Now()
+			t.DeletedAt = &now
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *fakeRepository) CreateOAuthClient(_ context.Context, args domain.CreateOAuthClientArgs) (*domain.OAuthClient, error) {
+	c := &domain.OAuthClient{
+		ID:           uuid.Must(uuid.NewV4()),
+		Name:         args.Name,
+		RedirectURIs: args.RedirectURIs,
+		Scopes:       args.Scopes,
+		Confidential: args.Secret != "",
+		CreatedBy:    args.CreatedBy,
+	}
+	r.oauthClients[c.ID] = c
+	return c, nil
+}
+
+func (r *fakeRepository) GetAllOAuthClients(_ context.Context) ([]*domain.OAuthClient, error) {
+	clients := make([]*domain.OAuthClient, 0, len(r.oauthClients))
+	for _, c := range r.oauthClients {
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+func (r *fakeRepository) DeleteOAuthClient(_ context.Context, clientID uuid.UUID) error {
+	delete(r.oauthClients, clientID)
+	return nil
+}
@@ -4,19 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/traPtitech/go-traq"
 	"github.com/traPtitech/knoQ/domain"
+	traqrepo "github.com/traPtitech/knoQ/infra/traq"
 	"github.com/traPtitech/knoQ/utils/random"
+	"golang.org/x/oauth2"
 )
 
-const traQIssuerName = "traQ"
-
 func (s *service) SyncUsers(ctx context.Context, reqID uuid.UUID) error {
 	if !s.IsPrivilege(ctx, reqID) {
 		return domain.ErrForbidden
 	}
+	s.TraQRepo.InvalidateUsers()
 	traQUsers, err := s.TraQRepo.GetUsers(true)
 	if err != nil {
 		return defaultErrorHandling(err)
@@ -33,7 +35,7 @@ func (s *service) SyncUsers(ctx context.Context, reqID uuid.UUID) error {
 			UserID: uid,
 			State:  int(u.State),
 			ProviderArgs: domain.ProviderArgs{
-				Issuer:  traQIssuerName,
+				Issuer:  s.Providers.traQIssuer,
 				Subject: u.GetId(),
 			},
 		}
@@ -46,6 +48,79 @@ func (s *service) SyncUsers(ctx context.Context, reqID uuid.UUID) error {
 	return defaultErrorHandling(err)
 }
 
+// HandOverAdmins 後任は有効なユーザーでなければならない
+func (s *service) HandOverAdmins(ctx context.Context, reqID uuid.UUID, userID uuid.UUID, successorID uuid.UUID) (*domain.HandOverResult, error) {
+	if !s.IsPrivilege(ctx, reqID) {
+		return nil, domain.ErrForbidden
+	}
+	if userID == successorID {
+		return nil, ErrInvalidArgs
+	}
+	user, err := s.GormRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	successor, err := s.GormRepo.GetUser(ctx, successorID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	if user.State == 1 || successor.State != 1 {
+		return nil, ErrInvalidArgs
+	}
+
+	var result *domain.HandOverResult
+	err = s.TxManager.Do(ctx, func(ctx context.Context) error {
+		result, err = s.GormRepo.TransferAdmins(ctx, userID, successorID)
+		return err
+	})
+	return result, defaultErrorHandling(err)
+}
+
+// getToken 期限が切れていれば更新して保存した traQ のトークンを返す
+func (s *service) getToken(ctx context.Context, userID uuid.UUID, force bool) (*oauth2.Token, error) {
+	t, err := s.GormRepo.GetToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	newToken, err := s.TraQRepo.RefreshToken(t, force)
+	if err != nil {
+		// 他のリクエストが先に更新して refresh token が使えなくなっている場合がある
+		latest, latestErr := s.GormRepo.GetToken(ctx, userID)
+		if latestErr == nil && latest.RefreshToken != t.RefreshToken && latest.Valid() {
+			return latest, nil
+		}
+		return nil, err
+	}
+	if newToken.AccessToken == t.AccessToken && newToken.RefreshToken == t.RefreshToken {
+		return newToken, nil
+	}
+	err = s.TxManager.Do(ctx, func(ctx context.Context) error {
+		return s.GormRepo.UpdateToken(ctx, userID, newToken)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newToken, nil
+}
+
+// RefreshExpiringTokens 既に期限が切れたトークンは使うときに更新する
+func (s *service) RefreshExpiringTokens(ctx context.Context, within time.Duration) error {
+	now := time.Now()
+	userIDs, err := s.GormRepo.GetExpiringTokenUserIDs(ctx, now, now.Add(within))
+	if err != nil {
+		return defaultErrorHandling(err)
+	}
+	var errs []error
+	for _, userID := range userIDs {
+		_, err := s.getToken(ctx, userID, true)
+		// refresh token が無効なユーザーはログインし直すまで更新できない
+		if err != nil && !errors.Is(err, traqrepo.ErrUnAuthorized) {
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+		}
+	}
+	return defaultErrorHandling(errors.Join(errs...))
+}
+
 func (s *service) GetOAuthURL(ctx context.Context) (url, state, codeVerifier string) {
 	return s.TraQRepo.GetOAuthURL()
 }
@@ -71,7 +146,7 @@ func (s *service) LoginUser(ctx context.Context, query, state, codeVerifier stri
 			Expiry:       t.Expiry,
 		},
 		ProviderArgs: domain.ProviderArgs{
-			Issuer:  traQIssuerName,
+			Issuer:  s.Providers.traQIssuer,
 			Subject: traQUser.GetId(),
 		},
 	}
@@ -92,16 +167,15 @@ func (s *service) GetUser(ctx context.Context, userID uuid.UUID) (*domain.User,
 		return nil, defaultErrorHandling(err)
 	}
 
-	if userMeta.Provider.Issuer == traQIssuerName {
-		userBody, err := s.TraQRepo.GetUser(userID)
-		if err != nil {
-			return nil, defaultErrorHandling(err)
-		}
-		user, _ := s.mergeUser(userMeta, userBody)
-		return user, nil
+	if !s.Providers.isTraQUser(userMeta) {
+		return guestUser(userMeta), nil
 	}
-
-	return nil, errors.New("not implemented")
+	userBody, err := s.TraQRepo.GetUser(userID)
+	if err != nil {
+		return nil, defaultErrorHandling(err)
+	}
+	user, _ := s.mergeUser(userMeta, userBody)
+	return user, nil
 }
 
 func (s *service) GetUserMe(ctx context.Context, reqID uuid.UUID) (*domain.User, error) {
@@ -121,6 +195,10 @@ func (s *service) GetAllUsers(ctx context.Context, includeSuspend, includeBot bo
 	traQUserBodsMap := traQUserMap(traQUserBodys)
 	users := make([]*domain.User, 0, len(userMetas))
 	for _, userMeta := range userMetas {
+		if !s.Providers.isTraQUser(userMeta) {
+			users = append(users, guestUser(userMeta))
+			continue
+		}
 		userBody, ok := traQUserBodsMap[userMeta.ID]
 		if !ok {
 			continue
@@ -186,7 +264,7 @@ func (s *service) mergeUser(userMeta *domain.User, userBody *traq.User) (*domain
 	if userMeta.ID != uuid.Must(uuid.FromString(userBody.GetId())) {
 		return nil, errors.New("id does not match")
 	}
-	if userMeta.Provider.Issuer != traQIssuerName {
+	if !s.Providers.isTraQUser(userMeta) {
 		return nil, errors.New("different provider")
 	}
 	return &domain.User{
@@ -11,10 +11,25 @@ import (
 	"github.com/traPtitech/go-traq"
 )
 
-// RequestWebhook q.trap/jp にメッセージを送信します。
-func RequestWebhook(message, secret, channelID, webhookID string, embed int) error {
+// TraQAPI traQ API の接続先
+// URL が空の場合は https://q.trap.jp/api/v3 に、HTTPClient が nil の場合は http.DefaultClient で接続する
+type TraQAPI struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (api TraQAPI) NewAPIClient() *traq.APIClient {
 	configuration := traq.NewConfiguration()
-	apiClient := traq.NewAPIClient(configuration)
+	if api.URL != "" {
+		configuration.Servers = traq.ServerConfigurations{{URL: api.URL}}
+	}
+	configuration.HTTPClient = api.HTTPClient
+	return traq.NewAPIClient(configuration)
+}
+
+// RequestWebhook api の traQ にメッセージを送信します。
+func RequestWebhook(api TraQAPI, message, secret, channelID, webhookID string, embed int) error {
+	apiClient := api.NewAPIClient()
 
 	xTRAQSignature := calcSignature(message, secret)
 	res, err := apiClient.WebhookAPI.PostWebhook(context.TODO(), webhookID).
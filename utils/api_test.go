@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/traPtitech/knoQ/infra/traq/traqtest"
+)
+
+func TestRequestWebhook(t *testing.T) {
+	server := traqtest.NewServer()
+	defer server.Close()
+	api := TraQAPI{URL: server.APIURL(), HTTPClient: server.Client()}
+
+	if err := RequestWebhook(api, "hello", "secret", "channel", "webhook", 1); err != nil {
+		t.Fatalf("RequestWebhook() error = %v", err)
+	}
+
+	webhooks := server.Webhooks()
+	if len(webhooks) != 1 {
+		t.Fatalf("len(webhooks) = %d, want 1", len(webhooks))
+	}
+	want := traqtest.Webhook{
+		WebhookID: "webhook",
+		ChannelID: "channel",
+		Signature: calcSignature("hello", "secret"),
+		Body:      "hello",
+	}
+	if webhooks[0] != want {
+		t.Errorf("webhook = %+v, want %+v", webhooks[0], want)
+	}
+}
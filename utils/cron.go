@@ -21,19 +21,19 @@ type timeTable struct {
 
 // InitPostEventToTraQ 現在(job実行)から24時間以内に始まるイベントを取得し、
 // webhookでtraQに送るjobを作成。
-func InitPostEventToTraQ(repo domain.Repository, secret, channelID, webhookID, origin string) func() {
+func InitPostEventToTraQ(repo domain.Repository, api TraQAPI, periods []Period, secret, channelID, webhookID, origin string) func() {
 	job := func() {
 		now := setTimeFromString(time.Now().In(tz.JST), "06:00:00")
 		tomorrow := now.AddDate(0, 0, 1)
 
-		rooms, _ := repo.GetAllRooms(context.Background(), now, tomorrow, uuid.Nil, true)
+		rooms, _ := repo.GetAllRooms(context.Background(), now, tomorrow, uuid.Nil, true, domain.RoomAttributeFilter{})
 		expr, err := filters.FilterDuration(now, tomorrow)
 		if err != nil {
 			fmt.Println(err)
 		}
 		events, _ := repo.GetAllEvents(context.Background(), expr)
-		message := createMessage(now, rooms, events, origin)
-		err = RequestWebhook(message, secret, channelID, webhookID, 1)
+		message := createMessage(now, rooms, events, periods, origin)
+		err = RequestWebhook(api, message, secret, channelID, webhookID, 1)
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -53,53 +53,41 @@ func makeRoomAvailableByTimeTable(rooms []*domain.Room, timeTables []timeTable,
 	for i := range roomAvailable {
 		roomAvailable[i] = make(map[string]string)
 	}
-	for _, room := range rooms {
-		if !room.Verified {
-			continue
-		}
-
-		ts, te := room.TimeStart, room.TimeEnd
-		for i, row := range timeTables {
-			rowNextStart := setTimeFromString(date, "23:59:59")
-			if i < len(timeTables)-1 {
-				rowNextStart = timeTables[i+1].start
-			}
-
-			rs := row.start
-			// 進捗部屋使用開始 <= n限開始 < 進捗部屋使用終了
-			if (ts.Before(rs) || ts.Equal(rs)) && rs.Before(te) {
-				if rowNextStart.Before(te) || rowNextStart.Equal(te) {
-					// n限の間全使用
-					roomAvailable[i][room.Place] = ":white_check_mark:"
-				} else {
-					// n限の途中で使用終了
-					roomAvailable[i][room.Place] = fmt.Sprintf("- %s", te.Format("15:04"))
-				}
-				continue
-			}
+	rowStarts := make([]time.Time, len(timeTables))
+	for i, row := range timeTables {
+		rowStarts[i] = row.start
+	}
 
-			// n限開始 < 進捗部屋使用開始 < n+1限開始
-			if rs.Before(ts) && ts.Before(rowNextStart) {
-				if rowNextStart.Before(te) || rowNextStart.Equal(te) {
-					// n限の途中で使用開始し、n限の間は全使用
-					roomAvailable[i][room.Place] = fmt.Sprintf("%s -", ts.Format("15:04"))
-				} else {
-					// n限の途中で使用開始し、n限の途中で使用終了
-					roomAvailable[i][room.Place] = fmt.Sprintf("%s - %s", ts.Format("15:04"), te.Format("15:04"))
-				}
-				continue
+	dayEnd := setTimeFromString(date, "23:59:59")
+	for _, place := range calcPeriodUsages(rooms, rowStarts, dayEnd) {
+		for i, usage := range place.Usages {
+			rowNextStart := dayEnd
+			if i < len(rowStarts)-1 {
+				rowNextStart = rowStarts[i+1]
 			}
-
-			// n限の間は進捗部屋を使用しない
-			if _, ok := roomAvailable[i][room.Place]; !ok {
-				roomAvailable[i][room.Place] = ":regional_indicator_null:"
+			switch {
+			case usage == nil:
+				// n限の間は進捗部屋を使用しない
+				roomAvailable[i][place.Place] = ":regional_indicator_null:"
+			case usage.Full:
+				// n限の間全使用
+				roomAvailable[i][place.Place] = ":white_check_mark:"
+			case usage.TimeStart.Equal(rowStarts[i]):
+				// n限の途中で使用終了
+				roomAvailable[i][place.Place] = fmt.Sprintf("- %s", usage.TimeEnd.Format("15:04"))
+			case usage.TimeEnd.Equal(rowNextStart):
+				// n限の途中で使用開始し、n限の間は全使用
+				roomAvailable[i][place.Place] = fmt.Sprintf("%s -", usage.TimeStart.Format("15:04"))
+			default:
+				// n限の途中で使用開始し、n限の途中で使用終了
+				roomAvailable[i][place.Place] = fmt.Sprintf("%s - %s", usage.TimeStart.Format("15:04"), usage.TimeEnd.Format("15:04"))
 			}
 		}
 	}
 	return roomAvailable
 }
 
-func createMessage(t time.Time, rooms []*domain.Room, events []*domain.Event, origin string) string {
+func createMessage(t time.Time, rooms []*domain.Room, events []*domain.Event, periods []Period, origin string) string {
 	date := t.In(tz.JST).Format("01/02(Mon)")
 	combined := map[bool]string{
 		true:  "(併用可)",
@@ -123,15 +111,9 @@ func createMessage(t time.Time, rooms []*domain.Room, events []*domain.Event, or
 		if len(verifiedRoomNames) == 0 {
 			roomMessage = "本日は予約を取っていないようです。\n"
 		} else {
-			timeTables := []timeTable{
-				{":sunny:", setTimeFromString(t, "00:00:00"), false},
-				{"1-2", setTimeFromString(t, "08:50:00"), true},
-				{"3-4", setTimeFromString(t, "10:45:00"), true},
-				{"昼", setTimeFromString(t, "12:25:00"), true},
-				{"5-6", setTimeFromString(t, "13:30:00"), true},
-				{"7-8", setTimeFromString(t, "15:25:00"), true},
-				{"9-10", setTimeFromString(t, "17:15:00"), true},
-				{":crescent_moon:", setTimeFromString(t, "18:55:00"), false},
+			timeTables := make([]timeTable, len(periods))
+			for i, p := range periods {
+				timeTables[i] = timeTable{p.Name, p.StartOn(t), p.DisplayDefault}
 			}
 			roomAvailable := makeRoomAvailableByTimeTable(rooms, timeTables, t)
 
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/traPtitech/knoQ/domain"
+	"github.com/traPtitech/knoQ/utils/tz"
+)
+
+// Period 時間割の1コマ
+type Period struct {
+	Name string
+	// Start その日の 0 時からの経過時間
+	Start time.Duration
+	// DisplayDefault false のとき、どの部屋も確保していなければ traQ に表示しない
+	DisplayDefault bool
+}
+
+// StartOn date の日 (JST) のコマの開始時刻
+func (p Period) StartOn(date time.Time) time.Time {
+	d := date.In(tz.JST)
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, tz.JST).Add(p.Start)
+}
+
+var DefaultPeriods = []Period{
+	{":sunny:", 0, false},
+	{"1-2", 8*time.Hour + 50*time.Minute, true},
+	{"3-4", 10*time.Hour + 45*time.Minute, true},
+	{"昼", 12*time.Hour + 25*time.Minute, true},
+	{"5-6", 13*time.Hour + 30*time.Minute, true},
+	{"7-8", 15*time.Hour + 25*time.Minute, true},
+	{"9-10", 17*time.Hour + 15*time.Minute, true},
+	{":crescent_moon:", 18*time.Hour + 55*time.Minute, false},
+}
+
+// ParsePeriods "1-2=08:50,3-4=10:45,?夜=18:55" のような文字列から時間割を作る
+// 名前の先頭に ? を付けると DisplayDefault が false になる
+// 空文字列の場合は DefaultPeriods を返す
+func ParsePeriods(str string) ([]Period, error) {
+	if strings.TrimSpace(str) == "" {
+		return DefaultPeriods, nil
+	}
+	periods := make([]Period, 0)
+	for _, s := range strings.Split(str, ",") {
+		name, start, ok := strings.Cut(strings.TrimSpace(s), "=")
+		if !ok || name == "" || name == "?" {
+			return nil, fmt.Errorf("invalid period: %q", s)
+		}
+		t, err := time.Parse("15:04", start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period: %q", s)
+		}
+		p := Period{
+			Name:           strings.TrimPrefix(name, "?"),
+			Start:          time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute,
+			DisplayDefault: !strings.HasPrefix(name, "?"),
+		}
+		if len(periods) > 0 && p.Start <= periods[len(periods)-1].Start {
+			return nil, fmt.Errorf("periods must be in ascending order: %q", s)
+		}
+		periods = append(periods, p)
+	}
+	return periods, nil
+}
+
+// PeriodUsage コマの中で部屋を確保している時間帯
+type PeriodUsage struct {
+	TimeStart time.Time
+	TimeEnd   time.Time
+	// Full コマの間ずっと確保している
+	Full bool
+}
+
+// PlaceAvailability 場所ごとの各コマの確保状況
+// Usages[i] は i 番目のコマに対応し、確保していなければ nil
+type PlaceAvailability struct {
+	Place  string
+	Usages []*PeriodUsage
+}
+
+// MakeRoomAvailabilityGrid date の日の時間割に沿って、場所ごとの確保状況を作成する。unVerified の部屋は無視する。
+func MakeRoomAvailabilityGrid(rooms []*domain.Room, periods []Period, date time.Time) []PlaceAvailability {
+	rowStarts := make([]time.Time, len(periods))
+	for i, p := range periods {
+		rowStarts[i] = p.StartOn(date)
+	}
+	return calcPeriodUsages(rooms, rowStarts, setTimeFromString(date, "23:59:59"))
+}
+
+// calcPeriodUsages rowStarts[i] から次のコマ (最後のコマは dayEnd) までを i 番目のコマとする
+// 同じ場所の部屋が同じコマに重なる場合は後の部屋で上書きする
+func calcPeriodUsages(rooms []*domain.Room, rowStarts []time.Time, dayEnd time.Time) []PlaceAvailability {
+	places := make([]PlaceAvailability, 0)
+	index := make(map[string]int)
+	for _, room := range rooms {
+		if !room.Verified {
+			continue
+		}
+		i, ok := index[room.Place]
+		if !ok {
+			i = len(places)
+			index[room.Place] = i
+			places = append(places, PlaceAvailability{
+				Place:  room.Place,
+				Usages: make([]*PeriodUsage, len(rowStarts)),
+			})
+		}
+
+		ts, te := room.TimeStart, room.TimeEnd
+		for j, rs := range rowStarts {
+			rowNextStart := dayEnd
+			if j < len(rowStarts)-1 {
+				rowNextStart = rowStarts[j+1]
+			}
+			// 進捗部屋使用開始 < n+1限開始 かつ n限開始 < 進捗部屋使用終了
+			if !ts.Before(rowNextStart) || !rs.Before(te) {
+				continue
+			}
+			usage := PeriodUsage{TimeStart: ts, TimeEnd: te}
+			if ts.Before(rs) {
+				usage.TimeStart = rs
+			}
+			if rowNextStart.Before(te) {
+				usage.TimeEnd = rowNextStart
+			}
+			usage.Full = usage.TimeStart.Equal(rs) && usage.TimeEnd.Equal(rowNextStart)
+			places[i].Usages[j] = &usage
+		}
+	}
+	return places
+}
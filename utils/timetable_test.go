@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePeriods(t *testing.T) {
+	tests := map[string]struct {
+		str     string
+		want    []Period
+		wantErr bool
+	}{
+		"empty": {
+			str:  "",
+			want: DefaultPeriods,
+		},
+		"periods": {
+			str: "?朝=00:00, 1限=09:00,2限=10:40",
+			want: []Period{
+				{"朝", 0, false},
+				{"1限", 9 * time.Hour, true},
+				{"2限", 10*time.Hour + 40*time.Minute, true},
+			},
+		},
+		"invalid time": {
+			str:     "1限=9時",
+			wantErr: true,
+		},
+		"not ascending": {
+			str:     "1限=10:40,2限=09:00",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePeriods(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePeriods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}